@@ -0,0 +1,11 @@
+package api
+
+import _ "embed"
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 -config oapi-codegen.yaml openapi.json
+
+// Spec is the OpenAPI document of the Media HTTP API.
+// The server interface of the file routes is generated from it, the upload and presign routes follow it by hand.
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,992 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for Purpose.
+const (
+	Attachment Purpose = "attachment"
+	Avatar     Purpose = "avatar"
+	Generic    Purpose = "generic"
+)
+
+// Defines values for VariantName.
+const (
+	Medium   VariantName = "medium"
+	Original VariantName = "original"
+	Thumb    VariantName = "thumb"
+)
+
+// Defines values for Visibility.
+const (
+	Private Visibility = "private"
+	Public  Visibility = "public"
+	Shared  Visibility = "shared"
+)
+
+// Defines values for TusResumable.
+const (
+	N100 TusResumable = "1.0.0"
+)
+
+// Access defines model for Access.
+type Access struct {
+	SharedWith *[]openapi_types.UUID `json:"sharedWith,omitempty"`
+
+	// Visibility Who can read a file: anyone, the author, or the author and the users the file is shared with. Files are private by default.
+	Visibility *Visibility `json:"visibility,omitempty"`
+}
+
+// AccessRequest defines model for AccessRequest.
+type AccessRequest struct {
+	SharedWith *[]openapi_types.UUID `json:"shared_with,omitempty"`
+
+	// Visibility Who can read a file: anyone, the author, or the author and the users the file is shared with. Files are private by default.
+	Visibility *Visibility `json:"visibility,omitempty"`
+}
+
+// CreateFileForm defines model for CreateFileForm.
+type CreateFileForm struct {
+	// AuthorId Author of the file, only admins may upload on behalf of another user.
+	AuthorId *openapi_types.UUID `json:"author_id,omitempty"`
+
+	// Checksum Hex encoded SHA-256 of the content, files with another content are not created.
+	Checksum *string            `json:"checksum,omitempty"`
+	File     openapi_types.File `json:"file"`
+
+	// Purpose What a file is for, which decides its allowed content types and maximum size.
+	Purpose *Purpose `json:"purpose,omitempty"`
+
+	// SharedWith Comma separated IDs of the users the file is shared with.
+	SharedWith *string `json:"shared_with,omitempty"`
+
+	// Visibility Who can read a file: anyone, the author, or the author and the users the file is shared with. Files are private by default.
+	Visibility *Visibility `json:"visibility,omitempty"`
+}
+
+// CreatedFile defines model for CreatedFile.
+type CreatedFile struct {
+	Id openapi_types.UUID `json:"id"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	// Code Machine-readable code, such as not_found or quota_exceeded.
+	Code string `json:"code"`
+
+	// Error Message for humans.
+	Error string `json:"error"`
+
+	// RequestId ID of the request, from the X-Request-ID header.
+	RequestId *string `json:"requestId,omitempty"`
+}
+
+// FileList defines model for FileList.
+type FileList struct {
+	Items []FileMeta `json:"items"`
+
+	// NextCursor Cursor of the next page, absent on the last page.
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// FileMeta defines model for FileMeta.
+type FileMeta struct {
+	Access   *Access             `json:"access,omitempty"`
+	AuthorId *openapi_types.UUID `json:"authorId,omitempty"`
+
+	// Checksum Hex encoded SHA-256 of the content, empty if it is not known.
+	Checksum    *string             `json:"checksum,omitempty"`
+	ContentType *string             `json:"contentType,omitempty"`
+	CreatedAt   *time.Time          `json:"createdAt,omitempty"`
+	Id          *openapi_types.UUID `json:"id,omitempty"`
+	Name        *string             `json:"name,omitempty"`
+
+	// Purpose What a file is for, which decides its allowed content types and maximum size.
+	Purpose   *Purpose   `json:"purpose,omitempty"`
+	Size      *int64     `json:"size,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Purpose What a file is for, which decides its allowed content types and maximum size.
+type Purpose string
+
+// Usage defines model for Usage.
+type Usage struct {
+	// Quota Bytes, not limited if 0.
+	Quota *int64 `json:"quota,omitempty"`
+
+	// Used Bytes, including the declared size of unfinished uploads.
+	Used *int64 `json:"used,omitempty"`
+}
+
+// VariantName defines model for VariantName.
+type VariantName string
+
+// Visibility Who can read a file: anyone, the author, or the author and the users the file is shared with. Files are private by default.
+type Visibility string
+
+// FileID defines model for FileID.
+type FileID = openapi_types.UUID
+
+// OnBehalfOf defines model for OnBehalfOf.
+type OnBehalfOf = openapi_types.UUID
+
+// Owner defines model for Owner.
+type Owner = string
+
+// TusResumable defines model for TusResumable.
+type TusResumable string
+
+// UploadID defines model for UploadID.
+type UploadID = openapi_types.UUID
+
+// BadRequest defines model for BadRequest.
+type BadRequest = Error
+
+// Forbidden defines model for Forbidden.
+type Forbidden = Error
+
+// InsufficientStorage defines model for InsufficientStorage.
+type InsufficientStorage = Error
+
+// NotFound defines model for NotFound.
+type NotFound = Error
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = Error
+
+// RangeNotSatisfiable defines model for RangeNotSatisfiable.
+type RangeNotSatisfiable = Error
+
+// TooLarge defines model for TooLarge.
+type TooLarge = Error
+
+// Unauthenticated defines model for Unauthenticated.
+type Unauthenticated = Error
+
+// Unprocessable defines model for Unprocessable.
+type Unprocessable = Error
+
+// UnsupportedType defines model for UnsupportedType.
+type UnsupportedType = Error
+
+// ListFilesParams defines parameters for ListFiles.
+type ListFilesParams struct {
+	// AuthorId Only files of this author.
+	AuthorId *openapi_types.UUID `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// Since Only files created at or after this time.
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only files created before this time.
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// Limit Maximum number of files of the page.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor The nextCursor of the previous page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetFileParams defines parameters for GetFile.
+type GetFileParams struct {
+	// Variant Returns a variant of an image file instead, like getVariant.
+	Variant *VariantName `form:"variant,omitempty" json:"variant,omitempty"`
+}
+
+// HeadFileParams defines parameters for HeadFile.
+type HeadFileParams struct {
+	Variant *VariantName `form:"variant,omitempty" json:"variant,omitempty"`
+}
+
+// GetMetaParams defines parameters for GetMeta.
+type GetMetaParams struct {
+	// AuthorId Acts as this user, the client itself if not set. Only admins may act on behalf of another user, like services attaching the files of the users they act for.
+	AuthorId *OnBehalfOf `form:"author_id,omitempty" json:"author_id,omitempty"`
+}
+
+// RemoveReferenceParams defines parameters for RemoveReference.
+type RemoveReferenceParams struct {
+	// AuthorId Acts as this user, the client itself if not set. Only admins may act on behalf of another user, like services attaching the files of the users they act for.
+	AuthorId *OnBehalfOf `form:"author_id,omitempty" json:"author_id,omitempty"`
+}
+
+// AddReferenceParams defines parameters for AddReference.
+type AddReferenceParams struct {
+	// AuthorId Acts as this user, the client itself if not set. Only admins may act on behalf of another user, like services attaching the files of the users they act for.
+	AuthorId *OnBehalfOf `form:"author_id,omitempty" json:"author_id,omitempty"`
+}
+
+// CreateFileMultipartRequestBody defines body for CreateFile for multipart/form-data ContentType.
+type CreateFileMultipartRequestBody = CreateFileForm
+
+// UpdateAccessJSONRequestBody defines body for UpdateAccess for application/json ContentType.
+type UpdateAccessJSONRequestBody = AccessRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List files
+	// (GET /files)
+	ListFiles(w http.ResponseWriter, r *http.Request, params ListFilesParams)
+	// Upload a file
+	// (POST /files)
+	CreateFile(w http.ResponseWriter, r *http.Request)
+	// Delete a file
+	// (DELETE /files/{id})
+	DeleteFile(w http.ResponseWriter, r *http.Request, id FileID)
+	// Download a file
+	// (GET /files/{id})
+	GetFile(w http.ResponseWriter, r *http.Request, id FileID, params GetFileParams)
+	// Describe a file
+	// (HEAD /files/{id})
+	HeadFile(w http.ResponseWriter, r *http.Request, id FileID, params HeadFileParams)
+	// Change who can read a file
+	// (PUT /files/{id}/access)
+	UpdateAccess(w http.ResponseWriter, r *http.Request, id FileID)
+	// Get the metadata of a file
+	// (GET /files/{id}/meta)
+	GetMeta(w http.ResponseWriter, r *http.Request, id FileID, params GetMetaParams)
+	// Remove a reference to a file
+	// (DELETE /files/{id}/references/{owner})
+	RemoveReference(w http.ResponseWriter, r *http.Request, id FileID, owner Owner, params RemoveReferenceParams)
+	// Reference a file
+	// (PUT /files/{id}/references/{owner})
+	AddReference(w http.ResponseWriter, r *http.Request, id FileID, owner Owner, params AddReferenceParams)
+	// Download a variant of an image
+	// (GET /files/{id}/variants/{name})
+	GetVariant(w http.ResponseWriter, r *http.Request, id FileID, name VariantName)
+	// Describe a variant of an image
+	// (HEAD /files/{id}/variants/{name})
+	HeadVariant(w http.ResponseWriter, r *http.Request, id FileID, name VariantName)
+	// Get the storage used by the client
+	// (GET /usage)
+	GetUsage(w http.ResponseWriter, r *http.Request)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// List files
+// (GET /files)
+func (_ Unimplemented) ListFiles(w http.ResponseWriter, r *http.Request, params ListFilesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Upload a file
+// (POST /files)
+func (_ Unimplemented) CreateFile(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete a file
+// (DELETE /files/{id})
+func (_ Unimplemented) DeleteFile(w http.ResponseWriter, r *http.Request, id FileID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Download a file
+// (GET /files/{id})
+func (_ Unimplemented) GetFile(w http.ResponseWriter, r *http.Request, id FileID, params GetFileParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Describe a file
+// (HEAD /files/{id})
+func (_ Unimplemented) HeadFile(w http.ResponseWriter, r *http.Request, id FileID, params HeadFileParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Change who can read a file
+// (PUT /files/{id}/access)
+func (_ Unimplemented) UpdateAccess(w http.ResponseWriter, r *http.Request, id FileID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the metadata of a file
+// (GET /files/{id}/meta)
+func (_ Unimplemented) GetMeta(w http.ResponseWriter, r *http.Request, id FileID, params GetMetaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Remove a reference to a file
+// (DELETE /files/{id}/references/{owner})
+func (_ Unimplemented) RemoveReference(w http.ResponseWriter, r *http.Request, id FileID, owner Owner, params RemoveReferenceParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Reference a file
+// (PUT /files/{id}/references/{owner})
+func (_ Unimplemented) AddReference(w http.ResponseWriter, r *http.Request, id FileID, owner Owner, params AddReferenceParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Download a variant of an image
+// (GET /files/{id}/variants/{name})
+func (_ Unimplemented) GetVariant(w http.ResponseWriter, r *http.Request, id FileID, name VariantName) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Describe a variant of an image
+// (HEAD /files/{id}/variants/{name})
+func (_ Unimplemented) HeadVariant(w http.ResponseWriter, r *http.Request, id FileID, name VariantName) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the storage used by the client
+// (GET /usage)
+func (_ Unimplemented) GetUsage(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListFiles operation middleware
+func (siw *ServerInterfaceWrapper) ListFiles(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListFilesParams
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", r.URL.Query(), &params.Until)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "until", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListFiles(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateFile operation middleware
+func (siw *ServerInterfaceWrapper) CreateFile(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateFile(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteFile operation middleware
+func (siw *ServerInterfaceWrapper) DeleteFile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteFile(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetFile operation middleware
+func (siw *ServerInterfaceWrapper) GetFile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFileParams
+
+	// ------------- Optional query parameter "variant" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "variant", r.URL.Query(), &params.Variant)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "variant", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFile(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// HeadFile operation middleware
+func (siw *ServerInterfaceWrapper) HeadFile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params HeadFileParams
+
+	// ------------- Optional query parameter "variant" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "variant", r.URL.Query(), &params.Variant)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "variant", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.HeadFile(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateAccess operation middleware
+func (siw *ServerInterfaceWrapper) UpdateAccess(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateAccess(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetMeta operation middleware
+func (siw *ServerInterfaceWrapper) GetMeta(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMetaParams
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMeta(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RemoveReference operation middleware
+func (siw *ServerInterfaceWrapper) RemoveReference(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "owner" -------------
+	var owner Owner
+
+	err = runtime.BindStyledParameterWithOptions("simple", "owner", chi.URLParam(r, "owner"), &owner, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "owner", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RemoveReferenceParams
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RemoveReference(w, r, id, owner, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AddReference operation middleware
+func (siw *ServerInterfaceWrapper) AddReference(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "owner" -------------
+	var owner Owner
+
+	err = runtime.BindStyledParameterWithOptions("simple", "owner", chi.URLParam(r, "owner"), &owner, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "owner", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AddReferenceParams
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddReference(w, r, id, owner, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetVariant operation middleware
+func (siw *ServerInterfaceWrapper) GetVariant(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name VariantName
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", chi.URLParam(r, "name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetVariant(w, r, id, name)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// HeadVariant operation middleware
+func (siw *ServerInterfaceWrapper) HeadVariant(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id FileID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name VariantName
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", chi.URLParam(r, "name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.HeadVariant(w, r, id, name)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsage(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/files", wrapper.ListFiles)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/files", wrapper.CreateFile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/files/{id}", wrapper.DeleteFile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/files/{id}", wrapper.GetFile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Head(options.BaseURL+"/files/{id}", wrapper.HeadFile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/files/{id}/access", wrapper.UpdateAccess)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/files/{id}/meta", wrapper.GetMeta)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/files/{id}/references/{owner}", wrapper.RemoveReference)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/files/{id}/references/{owner}", wrapper.AddReference)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/files/{id}/variants/{name}", wrapper.GetVariant)
+	})
+	r.Group(func(r chi.Router) {
+		r.Head(options.BaseURL+"/files/{id}/variants/{name}", wrapper.HeadVariant)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/usage", wrapper.GetUsage)
+	})
+
+	return r
+}
@@ -2,18 +2,30 @@ package main
 
 import (
 	"Media/config"
+	contracts "Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"Media/internal/infrastructure/imaging"
+	"Media/internal/infrastructure/repositories/fs"
+	"Media/internal/infrastructure/repositories/inmemory"
 	"Media/internal/infrastructure/repositories/minio"
+	"Media/internal/infrastructure/repositories/sql"
 	"Media/internal/infrastructure/server"
 	"Media/internal/usecases"
+	"Media/pkg/jwtservice"
 
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	defaultLogger "log"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 // Environment constants
@@ -33,37 +45,92 @@ func startApp() error {
 	log := InitLogger(cfg.Env)
 	log.Info("Logger initialized", slog.Any("env", cfg.Env))
 
-	// Create a new MinIO client
-	mc, err := minio.New(cfg.Minio.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.Minio.AccessKey, cfg.Minio.SecretKey, ""),
-		Secure: *cfg.Minio.UseSSL,
-	})
+	var db *gorm.DB
+	var metaRepo usecases.MetaRepositoryInterface
+	var referenceRepo usecases.ReferenceRepositoryInterface
+	if !cfg.UseDatabase {
+		log.Info("Using in-memory file index")
+		repo := inmemoryRepo.NewMetaRepository()
+		metaRepo, referenceRepo = repo, repo
+	} else {
+		log.Info("Using Postgres file index", slog.Any("host", cfg.Postgres.Host))
+
+		connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+			cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Pass, cfg.Postgres.Name)
+
+		var err error
+		db, err = gorm.Open(postgres.Open(connStr), &gorm.Config{TranslateError: true})
+		if err != nil {
+			log.Error("Failed to connect to database", slog.Any("error", err.Error()))
+			return err
+		}
+		repo := sqlRepo.NewMetaRepository(db, log)
+		metaRepo, referenceRepo = repo, repo
+	}
+
+	// Create the repositories of the configured storage, with their counters in the database if there is one
+	store, err := newStorage(cfg, db, log)
 	if err != nil {
-		log.Error("Failed to create MinIO client", slog.Any("error", err.Error()))
 		return err
 	}
 
-	//Check if the bucket exists
-	if exists, err := mc.BucketExists(context.Background(), cfg.Minio.Bucket); err != nil {
-		log.Error("Failed to check if bucket exists", slog.Any("error", err.Error()))
-		return err
-	} else if !exists {
-		if err := mc.MakeBucket(context.Background(), cfg.Minio.Bucket, minio.MakeBucketOptions{}); err != nil {
-			log.Error("Failed to create bucket", slog.Any("error", err.Error()))
-			return err
-		}
+	// Create a new use case
+	limits := usecases.NewLimits(policies(cfg.Files), cfg.Files.Quota, store.usage, log)
+	processor := imaging.NewProcessor(cfg.Images.MaxPixels, cfg.Images.Quality)
+	variantUseCase := usecases.NewVariantUseCase(store.variants, store.files, processor, cfg.Images.MaxSize, cfg.Images.QueueSize, cfg.Images.RequestWorkers, log)
+	uc := usecases.NewFileUseCase(store.files, store.blobs, metaRepo, limits, variantUseCase, log)
+	uploadUseCase := usecases.NewUploadUseCase(store.uploads, store.files, store.blobs, metaRepo, limits, variantUseCase, cfg.Uploads.MaxSize, cfg.Uploads.TTL, log)
+	expirers := []uploadExpirer{uploadUseCase}
+	lifecycleUseCase := usecases.NewLifecycleUseCase(uc, referenceRepo, cfg.Lifecycle.GracePeriod, cfg.Lifecycle.BatchSize, log)
+
+	var presignUseCase contracts.PresignUseCaseInterface
+	if store.presign != nil {
+		puc := usecases.NewPresignUseCase(store.presign, store.files, store.blobs, metaRepo, limits, variantUseCase, cfg.Presign.TTL, log)
+		presignUseCase = puc
+		expirers = append(expirers, puc)
 	} else {
-		log.Info("Bucket already exists", slog.Any("bucket", cfg.Minio.Bucket))
+		log.Info("Presigned URLs are not available with the storage backend", slog.Any("backend", cfg.Storage.Backend))
 	}
 
-	// Create a new repository
-	fileRepo := minioRepo.NewFileRepository(mc, cfg.Minio.Bucket, log)
+	// Make the variants of uploaded images
+	go variantUseCase.Run(context.Background(), cfg.Images.Workers)
 
-	// Create a new use case
-	uc := usecases.NewFileUseCase(fileRepo, log)
+	// Remove abandoned uploads
+	go sweepUploads(context.Background(), cfg.Uploads.SweepInterval, log, expirers...)
+
+	// Remove the files nothing references, the index in memory does not outlive restarts so files are only removed with the database
+	if cfg.Lifecycle.Enabled && cfg.UseDatabase {
+		go lifecycleUseCase.Run(context.Background(), cfg.Lifecycle.Interval, cfg.Lifecycle.DryRun)
+	} else if cfg.Lifecycle.Enabled {
+		log.Warn("Files without references are not removed without the database")
+	}
+
+	// Create a verifier for the tokens issued by the SSO service
+	var keys jwtservice.KeySource
+	switch {
+	case cfg.Tokens.JWKSURL != "":
+		log.Info("Using JWKS keys", slog.Any("url", cfg.Tokens.JWKSURL))
+		jwks := jwtservice.NewJWKSKeySource(cfg.Tokens.JWKSURL, cfg.Tokens.JWKSRefresh, nil)
+		if err := jwks.Refresh(context.Background()); err != nil {
+			log.Warn("Failed to fetch JWKS keys, will retry on first request", slog.Any("error", err.Error()))
+		}
+		keys = jwks
+	case cfg.Tokens.PublicKeyPath != "":
+		log.Info("Using public key", slog.Any("path", cfg.Tokens.PublicKeyPath))
+		keys, err = jwtservice.NewPEMKeySource(cfg.Tokens.PublicKeyPath)
+		if err != nil {
+			log.Error("Failed to read public key", slog.Any("error", err.Error()))
+			return err
+		}
+	default:
+		err = errors.New("either tokens.jwks_url or tokens.public_key_path is required")
+		log.Error("Failed to verify tokens", slog.Any("error", err.Error()))
+		return err
+	}
+	verifier := jwtservice.NewVerifier(keys)
 
 	// Create a new server
-	srv := server.NewServer(cfg.Server.Address, uc, log)
+	srv := server.NewServer(cfg.Server.Address, uc, variantUseCase, uploadUseCase, presignUseCase, lifecycleUseCase, cfg.Uploads.MaxSize, verifier, log)
 
 	// Start the server
 	if err := srv.Start(); err != nil {
@@ -74,6 +141,160 @@ func startApp() error {
 	return nil
 }
 
+// storage is the set of repositories of a storage backend.
+type storage struct {
+	files    usecases.FileRepositoryInterface
+	blobs    usecases.BlobRepositoryInterface
+	uploads  usecases.UploadRepositoryInterface
+	usage    usecases.UsageRepositoryInterface
+	variants usecases.VariantRepositoryInterface
+	presign  usecases.PresignRepositoryInterface // nil if the backend cannot presign URLs
+}
+
+// newStorage creates the repositories of the configured storage backend.
+// The usage of authors and the references to MinIO blobs are kept in db unless it is nil,
+// so that instances of the service sharing the storage update them atomically.
+func newStorage(cfg config.Config, db *gorm.DB, log *slog.Logger) (storage, error) {
+	store, err := newBackendStorage(cfg, db, log)
+	if err != nil || db == nil {
+		return store, err
+	}
+	store.usage = sqlRepo.NewUsageRepository(db, store.usage, log)
+	return store, nil
+}
+
+// newBackendStorage creates the repositories of the configured storage backend.
+func newBackendStorage(cfg config.Config, db *gorm.DB, log *slog.Logger) (storage, error) {
+	switch cfg.Storage.Backend {
+	case config.BackendMinio:
+		return newMinioStorage(cfg.Minio, db, log)
+	case config.BackendFS:
+		log.Info("Using file system storage", slog.Any("path", cfg.Storage.Path))
+		return storage{
+			files:    fsRepo.NewFileRepository(cfg.Storage.Path, log),
+			blobs:    fsRepo.NewBlobRepository(cfg.Storage.Path, log),
+			uploads:  fsRepo.NewUploadRepository(cfg.Storage.Path, log),
+			usage:    fsRepo.NewUsageRepository(cfg.Storage.Path, log),
+			variants: fsRepo.NewVariantRepository(cfg.Storage.Path, log),
+		}, nil
+	case config.BackendMemory:
+		log.Info("Using in-memory storage")
+		files := inmemoryRepo.NewFileRepository()
+		return storage{
+			files:    files,
+			blobs:    inmemoryRepo.NewBlobRepository(files),
+			uploads:  inmemoryRepo.NewUploadRepository(files),
+			usage:    inmemoryRepo.NewUsageRepository(),
+			variants: inmemoryRepo.NewVariantRepository(),
+		}, nil
+	default:
+		return storage{}, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// newMinioStorage creates the repositories of a MinIO bucket, and the bucket if it does not exist.
+// The references to blobs are kept in db unless it is nil.
+func newMinioStorage(cfg config.Minio, db *gorm.DB, log *slog.Logger) (storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return storage{}, errors.New("minio endpoint and bucket are required with the minio storage backend")
+	}
+	// Create a new MinIO client
+	mc, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		log.Error("Failed to create MinIO client", slog.Any("error", err.Error()))
+		return storage{}, err
+	}
+
+	// Presigned URLs are signed for the endpoint clients reach MinIO at
+	signer := mc
+	if cfg.PublicEndpoint != "" {
+		signer, err = minio.New(cfg.PublicEndpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+			Secure: cfg.UseSSL,
+			Region: cfg.Region,
+		})
+		if err != nil {
+			log.Error("Failed to create MinIO client", slog.Any("error", err.Error()))
+			return storage{}, err
+		}
+	}
+
+	//Check if the bucket exists
+	if exists, err := mc.BucketExists(context.Background(), cfg.Bucket); err != nil {
+		log.Error("Failed to check if bucket exists", slog.Any("error", err.Error()))
+		return storage{}, err
+	} else if !exists {
+		if err := mc.MakeBucket(context.Background(), cfg.Bucket, minio.MakeBucketOptions{}); err != nil {
+			log.Error("Failed to create bucket", slog.Any("error", err.Error()))
+			return storage{}, err
+		}
+	} else {
+		log.Info("Bucket already exists", slog.Any("bucket", cfg.Bucket))
+	}
+
+	var refs minioRepo.RefCounter
+	if db != nil {
+		refs = sqlRepo.NewBlobRefRepository(db, log)
+	}
+
+	return storage{
+		files:    minioRepo.NewFileRepository(mc, cfg.Bucket, log),
+		blobs:    minioRepo.NewBlobRepository(mc, cfg.Bucket, refs, log),
+		uploads:  minioRepo.NewUploadRepository(mc, cfg.Bucket, log),
+		usage:    minioRepo.NewUsageRepository(mc, cfg.Bucket, log),
+		variants: minioRepo.NewVariantRepository(mc, cfg.Bucket, log),
+		presign:  minioRepo.NewPresignRepository(mc, signer, cfg.Bucket, log),
+	}, nil
+}
+
+// policies returns the policies of the upload purposes, generic files are not limited unless configured.
+func policies(cfg config.Files) map[domain.Purpose]domain.Policy {
+	policies := map[domain.Purpose]domain.Policy{
+		domain.PurposeGeneric: {},
+	}
+	for purpose, policy := range cfg.Purposes {
+		policies[domain.Purpose(purpose)] = domain.Policy{
+			MaxSize:      policy.MaxSize,
+			ContentTypes: policy.ContentTypes,
+			Retention:    policy.Retention,
+		}
+	}
+	return policies
+}
+
+// uploadExpirer removes expired uploads.
+type uploadExpirer interface {
+	ExpireUploads(ctx context.Context) (int, error)
+}
+
+// sweepUploads removes expired uploads periodically.
+func sweepUploads(ctx context.Context, interval time.Duration, log *slog.Logger, expirers ...uploadExpirer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, expirer := range expirers {
+				expired, err := expirer.ExpireUploads(ctx)
+				if err != nil {
+					log.Error("Failed to remove expired uploads", slog.Any("error", err.Error()))
+					continue
+				}
+				if expired > 0 {
+					log.Info("Removed expired uploads", slog.Any("count", expired))
+				}
+			}
+		}
+	}
+}
+
 func main() {
 	if err := startApp(); err != nil {
 		panic(err)
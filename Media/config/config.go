@@ -9,11 +9,18 @@ import (
 
 // Config is the configuration for the application.
 type Config struct {
-	Env    string `yaml:"env" env-required:"true"` // dev, test, prod
-	Server Server `yaml:"server"`
-	//Postgres Postgres `yaml:"postgres"`
-	Minio  Minio  `yaml:"minio"`
-	Tokens Tokens `yaml:"tokens"`
+	Env         string    `yaml:"env" env-required:"true"` // dev, test, prod
+	Server      Server    `yaml:"server"`
+	UseDatabase bool      `yaml:"use_database" env-default:"false"` // the file index is kept in memory if false
+	Postgres    Postgres  `yaml:"postgres"`
+	Storage     Storage   `yaml:"storage"`
+	Minio       Minio     `yaml:"minio"`
+	Tokens      Tokens    `yaml:"tokens"`
+	Uploads     Uploads   `yaml:"uploads"`
+	Presign     Presign   `yaml:"presign"`
+	Files       Files     `yaml:"files"`
+	Images      Images    `yaml:"images"`
+	Lifecycle   Lifecycle `yaml:"lifecycle"`
 }
 
 // Server is the configuration for the server.
@@ -22,27 +29,92 @@ type Server struct {
 	Timeout time.Duration `yaml:"timeout" env-required:"true"`
 }
 
-// Minio is the configuration for the Minio storage.
+// Storage backends of files.
+const (
+	BackendMinio  = "minio"  // MinIO or another S3 compatible storage
+	BackendFS     = "fs"     // a local directory, for development
+	BackendMemory = "memory" // memory, files are lost on restart
+)
+
+// Storage is the configuration for the storage of files.
+// Presigned URLs are only available with the MinIO backend.
+type Storage struct {
+	Backend string `yaml:"backend" env-default:"minio"` // minio, fs or memory
+	Path    string `yaml:"path" env-default:"data"`     // root directory of the fs backend
+}
+
+// Minio is the configuration for the Minio storage, required with the minio backend.
 type Minio struct {
-	Endpoint  string `yaml:"endpoint" env-required:"true"`
-	AccessKey string `yaml:"access_key" env-required:"true"`
-	SecretKey string `yaml:"secret_key" env-required:"true"`
-	UseSSL    *bool  `yaml:"use_ssl" env-required:"true"`
-	Bucket    string `yaml:"bucket" env-required:"true"`
+	Endpoint       string `yaml:"endpoint"`
+	PublicEndpoint string `yaml:"public_endpoint"` // endpoint of presigned URLs, Endpoint if empty
+	Region         string `yaml:"region" env-default:"us-east-1"`
+	AccessKey      string `yaml:"access_key"`
+	SecretKey      string `yaml:"secret_key"`
+	UseSSL         bool   `yaml:"use_ssl" env-default:"false"`
+	Bucket         string `yaml:"bucket"`
+}
+
+// Uploads is the configuration for resumable uploads.
+type Uploads struct {
+	MaxSize       int64         `yaml:"max_size" env-default:"10737418240"` // bytes, not limited if 0
+	TTL           time.Duration `yaml:"ttl" env-default:"24h"`              // unfinished uploads are removed after it
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1h"`
+}
+
+// Presign is the configuration for presigned URLs.
+type Presign struct {
+	TTL time.Duration `yaml:"ttl" env-default:"15m"`
+}
+
+// Files is the configuration for the uploaded files.
+type Files struct {
+	Quota    int64             `yaml:"quota" env-default:"5368709120"` // bytes per author, not limited if 0
+	Purposes map[string]Policy `yaml:"purposes"`                       // policies by upload purpose, generic files are not limited if it is not set
+}
+
+// Policy is the configuration for the files uploaded for a purpose.
+type Policy struct {
+	MaxSize      int64         `yaml:"max_size"`      // bytes, not limited if 0
+	ContentTypes []string      `yaml:"content_types"` // allowed media types like "image/png" or "image/*", any if empty
+	Retention    time.Duration `yaml:"retention"`     // files without references are removed after it, kept if 0
+}
+
+// Lifecycle is the configuration for the removal of files without references.
+// Files are only removed with the database, files indexed in memory would look unreferenced after a restart.
+type Lifecycle struct {
+	Enabled     bool          `yaml:"enabled" env-default:"false"`
+	Interval    time.Duration `yaml:"interval" env-default:"1h"`
+	GracePeriod time.Duration `yaml:"grace_period" env-default:"24h"` // files are kept at least this long without references
+	BatchSize   int           `yaml:"batch_size" env-default:"1000"`  // files of a purpose removed per run
+	DryRun      bool          `yaml:"dry_run" env-default:"false"`    // files to remove are only logged
+}
+
+// Images is the configuration for the variants of images.
+type Images struct {
+	Workers        int   `yaml:"workers" env-default:"2"`
+	QueueSize      int   `yaml:"queue_size" env-default:"256"`      // images queued while the queue is full get their variants on request
+	RequestWorkers int   `yaml:"request_workers" env-default:"2"`   // images whose variants are made on request at the same time, other requests wait
+	MaxSize        int64 `yaml:"max_size" env-default:"52428800"`   // bytes, larger images get no variants, not limited if 0
+	MaxPixels      int   `yaml:"max_pixels" env-default:"50000000"` // larger images get no variants, not limited if 0
+	Quality        int   `yaml:"quality" env-default:"85"`          // quality of JPEG variants
 }
 
 // Postgres is the configuration for the PostgreSQL database.
-//type Postgres struct {
-//	Host string `yaml:"host"`
-//	Port int    `yaml:"port"`
-//	User string `yaml:"user"`
-//	Pass string `yaml:"pass"`
-//	Name string `yaml:"name"`
-//}
+type Postgres struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	User string `yaml:"user"`
+	Pass string `yaml:"pass"`
+	Name string `yaml:"name"`
+}
 
 // Tokens is the configuration for the JWT tokens.
+//
+// Tokens issued by SSO are verified with RS256 keys from JWKSURL or PublicKeyPath, one of them is required.
 type Tokens struct {
-	PublicKeyPath string `yaml:"public_key_path" env-required:"true"`
+	PublicKeyPath string        `yaml:"public_key_path"`
+	JWKSURL       string        `yaml:"jwks_url"`
+	JWKSRefresh   time.Duration `yaml:"jwks_refresh" env-default:"5m"`
 }
 
 // MustParseConfig parses the configuration from the given path.
@@ -7,15 +7,33 @@ import (
 	"io"
 )
 
+// CreateFileDTO is the input of FileUseCaseInterface.CreateFile.
+// Size is -1 if the size of the content is not known in advance, Purpose is generic if empty.
+// Checksum is the hex encoded SHA-256 the content must have, it is not checked if empty.
 type CreateFileDTO struct {
 	Name     string
 	AuthorID uuid.UUID
 	Size     int64
 	Content  io.Reader
+	Access   domain.Access
+	Purpose  domain.Purpose
+	Checksum string
 }
 
 type FileUseCaseInterface interface {
 	CreateFile(ctx context.Context, dto CreateFileDTO) (uuid.UUID, error)
-	GetFile(ctx context.Context, id uuid.UUID) (domain.File, error)
-	DeleteFile(ctx context.Context, id uuid.UUID) error
+	// StatFile returns a file the user can read, uuid.Nil is an anonymous user.
+	StatFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.File, error)
+	// GetFile returns the content of a file the user can read, the caller must close it.
+	GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID, opts domain.ReadOptions) (io.ReadCloser, error)
+	// UpdateAccess replaces the access control of a file of the user.
+	UpdateAccess(ctx context.Context, userID uuid.UUID, id uuid.UUID, access domain.Access) (domain.File, error)
+	// DeleteFile deletes a file of the user.
+	DeleteFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	// GetMeta returns the indexed metadata of a file the user can read.
+	GetMeta(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.FileMeta, error)
+	// ListFiles returns a page of the indexed files the reader of the query can read.
+	ListFiles(ctx context.Context, query domain.MetaQuery) (domain.MetaPage, error)
+	// GetUsage returns the storage used by the user.
+	GetUsage(ctx context.Context, userID uuid.UUID) (domain.Usage, error)
 }
@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+)
+
+type LifecycleUseCaseInterface interface {
+	// AddReference registers that owner uses a file of the user, so the file is kept.
+	// Registering a reference again changes nothing.
+	AddReference(ctx context.Context, userID uuid.UUID, id uuid.UUID, owner string) error
+	// RemoveReference removes a reference to a file of the user.
+	// Files without references are removed once the retention of their purpose has passed.
+	RemoveReference(ctx context.Context, userID uuid.UUID, id uuid.UUID, owner string) error
+	// Collect removes the files without references past their retention and indexes stored files without metadata.
+	// A dry run changes nothing and reports what would be done.
+	Collect(ctx context.Context, dryRun bool) (domain.LifecycleReport, error)
+}
@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+)
+
+type PresignUploadDTO struct {
+	Name        string
+	AuthorID    uuid.UUID
+	ContentType string
+	Size        int64
+	Access      domain.Access
+	Purpose     domain.Purpose // generic if empty
+	Checksum    string         // hex encoded SHA-256 the content must have, not checked if empty
+}
+
+type PresignUseCaseInterface interface {
+	// PresignUpload returns the URL to upload the content of a new file to.
+	PresignUpload(ctx context.Context, dto PresignUploadDTO) (*domain.PresignedUpload, *domain.PresignedURL, error)
+	// FinalizeUpload checks the uploaded content of a presigned upload of the user and creates its file.
+	FinalizeUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.File, error)
+	// PresignDownload returns the URL to download the content of a file the user can read from.
+	PresignDownload(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.PresignedURL, error)
+	// ExpireUploads removes expired presigned uploads and returns their number.
+	ExpireUploads(ctx context.Context) (int, error)
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"io"
+)
+
+type CreateUploadDTO struct {
+	Name     string
+	AuthorID uuid.UUID
+	Size     int64
+	Access   domain.Access
+	Purpose  domain.Purpose // generic if empty
+	Checksum string         // hex encoded SHA-256 the content must have, not checked if empty
+}
+
+type UploadUseCaseInterface interface {
+	CreateUpload(ctx context.Context, dto CreateUploadDTO) (*domain.Upload, error)
+	// GetUpload returns an upload of the user.
+	GetUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Upload, error)
+	// WriteUpload appends content at the offset of an upload of the user.
+	// The file is created once the upload is done.
+	WriteUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID, offset int64, content io.Reader) (*domain.Upload, error)
+	// DeleteUpload cancels an upload of the user.
+	DeleteUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	// ExpireUploads cancels expired uploads and returns their number.
+	ExpireUploads(ctx context.Context) (int, error)
+}
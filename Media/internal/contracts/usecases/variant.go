@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"io"
+)
+
+type VariantUseCaseInterface interface {
+	// Processes reports whether variants are made of a file.
+	Processes(file domain.File) bool
+	// StatVariant returns a variant of an image file the user can read, uuid.Nil is an anonymous user.
+	// The variant is described as a file with the ID and the access control of the image file.
+	StatVariant(ctx context.Context, userID uuid.UUID, id uuid.UUID, name string) (domain.File, error)
+	// GetVariant returns the content of a variant of an image file the user can read, the caller must close it.
+	GetVariant(ctx context.Context, userID uuid.UUID, id uuid.UUID, name string, opts domain.ReadOptions) (io.ReadCloser, error)
+}
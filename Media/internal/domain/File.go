@@ -1,14 +1,21 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // File is a file.
 type File struct {
-	ID       uuid.UUID `json:"id"`
-	AuthorID uuid.UUID `json:"authorId"`
-	Name     string    `json:"name"`
-	Size     int64     `json:"size"`
-	Content  []byte    `json:"content"`
+	ID           uuid.UUID `json:"id"`
+	AuthorID     uuid.UUID `json:"authorId"`
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"lastModified"`
+	Access       Access    `json:"access"`
+	Checksum     string    `json:"checksum,omitempty"` // hex encoded SHA-256 of the content, empty for files stored before blobs
 }
 
 // GetID returns the ID of the file.
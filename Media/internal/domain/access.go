@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"slices"
+)
+
+// Visibility tells who can read a file.
+type Visibility string
+
+const (
+	VisibilityPublic  Visibility = "public"  // anyone, without authentication
+	VisibilityPrivate Visibility = "private" // the author
+	VisibilityShared  Visibility = "shared"  // the author and the users the file is shared with
+)
+
+// MaxSharedWith is the maximum number of users a file can be shared with.
+const MaxSharedWith = 32
+
+// Access is the access control of a file.
+type Access struct {
+	Visibility Visibility  `json:"visibility"`
+	SharedWith []uuid.UUID `json:"sharedWith,omitempty"`
+}
+
+// Validate checks the access control, files are private if the visibility is not set.
+func (a *Access) Validate() error {
+	if a.Visibility == "" {
+		a.Visibility = VisibilityPrivate
+	}
+
+	switch a.Visibility {
+	case VisibilityPublic, VisibilityPrivate:
+		if len(a.SharedWith) != 0 {
+			return ErrInvalidAccess
+		}
+	case VisibilityShared:
+		if len(a.SharedWith) > MaxSharedWith {
+			return ErrInvalidAccess
+		}
+	default:
+		return ErrInvalidAccess
+	}
+
+	return nil
+}
+
+// CanRead reports whether a user can read the file, uuid.Nil is an anonymous user.
+func (f *File) CanRead(userID uuid.UUID) bool {
+	return f.Access.CanRead(f.AuthorID, userID)
+}
+
+// CanRead reports whether a user can read a file of the author with this access control.
+// uuid.Nil is an anonymous user.
+func (a Access) CanRead(authorID uuid.UUID, userID uuid.UUID) bool {
+	switch a.Visibility {
+	case VisibilityPublic:
+		return true
+	case VisibilityShared:
+		return userID != uuid.Nil && (userID == authorID || slices.Contains(a.SharedWith, userID))
+	default:
+		return userID != uuid.Nil && userID == authorID
+	}
+}
+
+// CanModify reports whether a user can change or delete the file.
+func (f *File) CanModify(userID uuid.UUID) bool {
+	return userID != uuid.Nil && userID == f.AuthorID
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccess_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		access Access
+		want   Visibility
+		err    error
+	}{
+		{name: "private by default", access: Access{}, want: VisibilityPrivate},
+		{name: "public", access: Access{Visibility: VisibilityPublic}, want: VisibilityPublic},
+		{name: "shared", access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{uuid.New()}}, want: VisibilityShared},
+		{name: "shared with nobody", access: Access{Visibility: VisibilityShared}, want: VisibilityShared},
+		{name: "private shared with users", access: Access{Visibility: VisibilityPrivate, SharedWith: []uuid.UUID{uuid.New()}}, err: ErrInvalidAccess},
+		{name: "public shared with users", access: Access{Visibility: VisibilityPublic, SharedWith: []uuid.UUID{uuid.New()}}, err: ErrInvalidAccess},
+		{name: "shared with too many users", access: Access{Visibility: VisibilityShared, SharedWith: make([]uuid.UUID, MaxSharedWith+1)}, err: ErrInvalidAccess},
+		{name: "unknown visibility", access: Access{Visibility: "friends"}, err: ErrInvalidAccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.access.Validate()
+
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, tt.want, tt.access.Visibility)
+			}
+		})
+	}
+}
+
+func TestAccess_CanRead(t *testing.T) {
+	authorID := uuid.New()
+	friendID := uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name   string
+		access Access
+		userID uuid.UUID
+		want   bool
+	}{
+		{name: "public by anonymous", access: Access{Visibility: VisibilityPublic}, userID: uuid.Nil, want: true},
+		{name: "public by another user", access: Access{Visibility: VisibilityPublic}, userID: otherID, want: true},
+		{name: "private by the author", access: Access{Visibility: VisibilityPrivate}, userID: authorID, want: true},
+		{name: "private by another user", access: Access{Visibility: VisibilityPrivate}, userID: otherID, want: false},
+		{name: "private by anonymous", access: Access{Visibility: VisibilityPrivate}, userID: uuid.Nil, want: false},
+		{name: "unset visibility is private", access: Access{}, userID: otherID, want: false},
+		{name: "shared by the author", access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{friendID}}, userID: authorID, want: true},
+		{name: "shared by a user it is shared with", access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{friendID}}, userID: friendID, want: true},
+		{name: "shared by another user", access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{friendID}}, userID: otherID, want: false},
+		{name: "shared by anonymous", access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{friendID}}, userID: uuid.Nil, want: false},
+		{name: "shared with a nil user by anonymous", access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{uuid.Nil}}, userID: uuid.Nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.access.CanRead(authorID, tt.userID))
+
+			file := File{AuthorID: authorID, Access: tt.access}
+			assert.Equal(t, tt.want, file.CanRead(tt.userID))
+		})
+	}
+}
+
+func TestFile_CanModify(t *testing.T) {
+	authorID := uuid.New()
+	friendID := uuid.New()
+	file := File{AuthorID: authorID, Access: Access{Visibility: VisibilityShared, SharedWith: []uuid.UUID{friendID}}}
+
+	assert.True(t, file.CanModify(authorID))
+	assert.False(t, file.CanModify(friendID))
+	assert.False(t, file.CanModify(uuid.New()))
+	assert.False(t, file.CanModify(uuid.Nil))
+	assert.False(t, (&File{}).CanModify(uuid.Nil))
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// ParseChecksum returns the hex encoded SHA-256 sent by a client in lower case.
+// Clients may send no checksum, so an empty checksum is valid.
+func ParseChecksum(checksum string) (string, error) {
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	if checksum == "" {
+		return "", nil
+	}
+
+	raw, err := hex.DecodeString(checksum)
+	if err != nil || len(raw) != 32 {
+		return "", ErrInvalidChecksum
+	}
+	return checksum, nil
+}
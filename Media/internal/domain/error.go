@@ -2,8 +2,78 @@ package domain
 
 import "errors"
 
+// Kind tells what clients can do about an error, handlers map kinds to response statuses.
+type Kind string
+
+// Kinds of errors.
+const (
+	KindInternal            Kind = "internal"
+	KindBadRequest          Kind = "bad_request"
+	KindUnauthenticated     Kind = "unauthenticated"
+	KindForbidden           Kind = "forbidden"
+	KindNotFound            Kind = "not_found"
+	KindConflict            Kind = "conflict"
+	KindLocked              Kind = "locked"
+	KindGone                Kind = "gone"
+	KindPreconditionFailed  Kind = "precondition_failed"
+	KindTooLarge            Kind = "too_large"
+	KindUnsupportedType     Kind = "unsupported_media_type"
+	KindUnprocessable       Kind = "unprocessable"
+	KindInsufficientStorage Kind = "insufficient_storage"
+)
+
+// Error is an error clients are told about, with its kind and a machine-readable code.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func NewError(kind Kind, code string, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// KindOf returns the kind of the first Error wrapped by err, KindInternal if there is none.
+func KindOf(err error) Kind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	return KindInternal
+}
+
 // Errors that can be returned by repositories.
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrAlreadyExists = errors.New("already exists")
+	ErrNotFound      = NewError(KindNotFound, "not_found", "not found")
+	ErrAlreadyExists = NewError(KindConflict, "already_exists", "already exists")
+	ErrModified      = NewError(KindPreconditionFailed, "modified", "modified")
+	ErrForbidden     = NewError(KindForbidden, "forbidden", "forbidden")
+	ErrExpired       = NewError(KindGone, "expired", "expired")
+	ErrTooLarge      = NewError(KindTooLarge, "too_large", "too large")
+	ErrWrongOffset   = NewError(KindConflict, "wrong_offset", "wrong offset")
+	ErrUploadLocked  = NewError(KindLocked, "upload_locked", "the upload is being written by another request")
+	ErrContentType   = NewError(KindUnsupportedType, "content_type_not_allowed", "content type not allowed")
+	ErrContentDiffer = NewError(KindUnprocessable, "content_differs", "content differs from the declared one")
+	ErrInvalidAccess = NewError(KindBadRequest, "invalid_access", "invalid access control")
+	ErrPurpose       = NewError(KindBadRequest, "unknown_purpose", "unknown upload purpose")
+	ErrQuota         = NewError(KindInsufficientStorage, "quota_exceeded", "storage quota exceeded")
+	ErrNotImage      = NewError(KindUnprocessable, "not_image", "not a supported image")
+	ErrInvalidSize   = NewError(KindBadRequest, "invalid_size", "size must not be negative")
+
+	ErrInvalidChecksum = NewError(KindBadRequest, "invalid_checksum", "invalid checksum")
+	ErrChecksum        = NewError(KindUnprocessable, "checksum_mismatch", "content does not match the checksum")
+
+	ErrInvalidPageSize = NewError(KindBadRequest, "invalid_page_size", "invalid page size")
+	ErrInvalidCursor   = NewError(KindBadRequest, "invalid_cursor", "invalid cursor")
+
+	ErrInvalidOwner  = NewError(KindBadRequest, "invalid_owner", "invalid reference owner")
+	ErrNotReferenced = NewError(KindNotFound, "not_referenced", "the file is not referenced by the owner")
 )
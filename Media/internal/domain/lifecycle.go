@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"regexp"
+	"time"
+)
+
+// ownerPattern matches the owners of references, like "post:0b6e7a0c-5bd4-4c8c-9a4d-2f0e3c7a1d55".
+var ownerPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Reference records that something outside Media uses a file, like the post a file is attached to.
+// Files are kept while they have references.
+type Reference struct {
+	FileID    uuid.UUID `json:"fileId"`
+	Owner     string    `json:"owner"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ValidateOwner checks the owner of a reference.
+func ValidateOwner(owner string) error {
+	if !ownerPattern.MatchString(owner) {
+		return ErrInvalidOwner
+	}
+	return nil
+}
+
+// LifecycleReport is what a garbage collection of files removed, or would remove on a dry run.
+type LifecycleReport struct {
+	DryRun bool `json:"dryRun"`
+	// Expired are the files without references past the retention of their purpose.
+	Expired []FileMeta `json:"expired"`
+	// Unindexed are the stored files without metadata, they are indexed as generic files.
+	Unindexed []uuid.UUID `json:"unindexed"`
+	// Freed is the size of the expired files, in bytes.
+	Freed int64 `json:"freed"`
+}
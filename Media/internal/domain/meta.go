@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"encoding/base64"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// Page sizes of file lists.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// FileMeta is the indexed metadata of a file.
+type FileMeta struct {
+	ID          uuid.UUID `json:"id"`
+	AuthorID    uuid.UUID `json:"authorId"`
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"contentType"`
+	Checksum    string    `json:"checksum"` // hex encoded SHA-256 of the content, empty if it is not known
+	Access      Access    `json:"access"`
+	Purpose     Purpose   `json:"purpose"` // selects the retention of the file once it has no references
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// MetaOf returns the metadata of a generic file created at the given time.
+func MetaOf(file File, createdAt time.Time) FileMeta {
+	return FileMeta{
+		ID:          file.ID,
+		AuthorID:    file.AuthorID,
+		Name:        file.Name,
+		Size:        file.Size,
+		ContentType: file.ContentType,
+		Checksum:    file.Checksum,
+		Access:      file.Access,
+		Purpose:     PurposeGeneric,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+}
+
+// CanRead reports whether a user can read the file, uuid.Nil is an anonymous user.
+func (m *FileMeta) CanRead(userID uuid.UUID) bool {
+	return m.Access.CanRead(m.AuthorID, userID)
+}
+
+// Cursor is a position in a list of files ordered by creation time and ID.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf returns the cursor of the metadata of a file.
+func CursorOf(meta FileMeta) Cursor {
+	return Cursor{CreatedAt: meta.CreatedAt, ID: meta.ID}
+}
+
+// String encodes the cursor as an opaque token for clients.
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "/" + c.ID.String()))
+}
+
+// ParseCursor decodes a cursor encoded by Cursor.String.
+func ParseCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	rawTime, rawID, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, rawTime)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// After reports whether c comes after other.
+func (c Cursor) After(other Cursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.After(other.CreatedAt)
+	}
+	return strings.Compare(c.ID.String(), other.ID.String()) > 0
+}
+
+// MetaQuery selects a page of files from the index, ordered by creation time and ID.
+type MetaQuery struct {
+	ReaderID uuid.UUID  // only files the reader can read are listed, uuid.Nil is an anonymous reader
+	AuthorID *uuid.UUID // files of the author only if set
+	Since    *time.Time // files created at or after it only if set
+	Until    *time.Time // files created before it only if set
+	Limit    int
+	After    *Cursor
+}
+
+// Validate checks the query and sets the default page size.
+func (q *MetaQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = DefaultPageSize
+	}
+	if q.Limit < 0 || q.Limit > MaxPageSize {
+		return ErrInvalidPageSize
+	}
+	return nil
+}
+
+// Matches reports whether the query selects the file, regardless of the page.
+func (q *MetaQuery) Matches(meta FileMeta) bool {
+	if !meta.CanRead(q.ReaderID) {
+		return false
+	}
+	if q.AuthorID != nil && meta.AuthorID != *q.AuthorID {
+		return false
+	}
+	if q.Since != nil && meta.CreatedAt.Before(*q.Since) {
+		return false
+	}
+	if q.Until != nil && !meta.CreatedAt.Before(*q.Until) {
+		return false
+	}
+	return true
+}
+
+// MetaPage is a page of files.
+type MetaPage struct {
+	Items []FileMeta
+	Next  *Cursor // cursor of the next page, nil on the last page
+}
+
+// NewMetaPage creates a page from up to limit+1 items, the extra item only tells whether there is a next page.
+func NewMetaPage(items []FileMeta, limit int) MetaPage {
+	if len(items) <= limit {
+		return MetaPage{Items: items}
+	}
+
+	items = items[:limit]
+	next := CursorOf(items[len(items)-1])
+	return MetaPage{Items: items, Next: &next}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"mime"
+	"strings"
+	"time"
+)
+
+// Purpose is what an uploaded file is used for, it selects the policy the upload is checked against.
+type Purpose string
+
+const (
+	PurposeGeneric    Purpose = "generic"
+	PurposeAvatar     Purpose = "avatar"
+	PurposeAttachment Purpose = "attachment" // attachment of a post
+)
+
+// Policy limits the files uploaded for a purpose.
+type Policy struct {
+	MaxSize      int64         // bytes, not limited if 0
+	ContentTypes []string      // allowed media types, "image/*" allows any image, any type is allowed if empty
+	Retention    time.Duration // files without references are removed after it, kept if 0
+}
+
+// AllowsSize reports whether a file of the given size can be uploaded.
+func (p Policy) AllowsSize(size int64) bool {
+	return p.MaxSize <= 0 || size <= p.MaxSize
+}
+
+// AllowsContentType reports whether a file of the given content type can be uploaded.
+func (p Policy) AllowsContentType(contentType string) bool {
+	if len(p.ContentTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range p.ContentTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage is the storage used by an author.
+type Usage struct {
+	Used  int64 `json:"used"`  // bytes, including the declared size of unfinished uploads
+	Quota int64 `json:"quota"` // bytes, not limited if 0
+}
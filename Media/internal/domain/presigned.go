@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PresignedUpload is an upload of a file straight to the storage with a presigned URL.
+// The file is created when the upload is finalized.
+type PresignedUpload struct {
+	ID          uuid.UUID `json:"id"`
+	AuthorID    uuid.UUID `json:"authorId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	Access      Access    `json:"access"`
+	Purpose     Purpose   `json:"purpose"`
+	Checksum    string    `json:"checksum"` // SHA-256 the content must have, empty if the client sent none
+	Reserved    int64     `json:"reserved"` // bytes of the quota of the author reserved for the upload
+}
+
+// Expired reports whether the upload is expired at the given time.
+func (u *PresignedUpload) Expired(now time.Time) bool {
+	return now.After(u.ExpiresAt)
+}
+
+// PresignedURL is a short-lived URL to access the storage directly.
+type PresignedURL struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers,omitempty"` // headers the request must be sent with
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+// ByteRange is an inclusive range of bytes of the content of a file.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// Length returns the number of bytes in the range.
+func (r ByteRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ReadOptions select the content of a file to read.
+type ReadOptions struct {
+	Range *ByteRange // the whole content is read if nil
+	ETag  string     // if set, reading fails with ErrModified when the file has another ETag
+}
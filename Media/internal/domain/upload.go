@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Upload is a resumable upload of a file.
+// The file gets the ID of the upload once all of its content is received.
+type Upload struct {
+	ID          uuid.UUID    `json:"id"`
+	AuthorID    uuid.UUID    `json:"authorId"`
+	Name        string       `json:"name"`
+	Size        int64        `json:"size"`
+	Offset      int64        `json:"offset"`      // number of bytes received
+	MultipartID string       `json:"multipartId"` // ID of the upload in the storage
+	Parts       []UploadPart `json:"parts"`
+	PendingSize int64        `json:"pendingSize"` // number of received bytes not stored in parts yet
+	CreatedAt   time.Time    `json:"createdAt"`
+	ExpiresAt   time.Time    `json:"expiresAt"`
+	Access      Access       `json:"access"`
+	Purpose     Purpose      `json:"purpose"`
+	ContentType string       `json:"contentType"` // detected from the first content, empty until it is received
+	Checksum    string       `json:"checksum"`    // SHA-256 the content must have, empty if the client sent none
+	Reserved    int64        `json:"reserved"`    // bytes of the quota of the author reserved for the upload
+}
+
+// UploadPart is a stored part of an upload.
+type UploadPart struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size"`
+}
+
+// GetID returns the ID of the upload.
+func (u *Upload) GetID() uuid.UUID {
+	return u.ID
+}
+
+// SetID sets the ID of the upload.
+func (u *Upload) SetID(id uuid.UUID) {
+	u.ID = id
+}
+
+// Done reports whether all of the content of the upload is received.
+func (u *Upload) Done() bool {
+	return u.Offset == u.Size
+}
+
+// Expired reports whether the upload is expired at the given time.
+func (u *Upload) Expired(now time.Time) bool {
+	return !u.ExpiresAt.IsZero() && now.After(u.ExpiresAt)
+}
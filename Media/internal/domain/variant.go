@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+)
+
+// Names of the variants of images.
+const (
+	VariantThumb    = "thumb"
+	VariantMedium   = "medium"
+	VariantOriginal = "original" // the image in its full size without its metadata
+)
+
+// VariantSpec describes a variant of images, images are scaled down to fit in its bounds.
+type VariantSpec struct {
+	Name      string
+	MaxWidth  int // pixels, not limited if 0
+	MaxHeight int // pixels, not limited if 0
+}
+
+// Variants are the variants made of uploaded images.
+var Variants = []VariantSpec{
+	{Name: VariantThumb, MaxWidth: 256, MaxHeight: 256},
+	{Name: VariantMedium, MaxWidth: 1280, MaxHeight: 1280},
+	{Name: VariantOriginal},
+}
+
+// LookupVariant returns the variant with the given name.
+func LookupVariant(name string) (VariantSpec, bool) {
+	for _, spec := range Variants {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return VariantSpec{}, false
+}
+
+// Fit returns the size of an image of the given size scaled down to fit in the bounds of the variant.
+func (s VariantSpec) Fit(width int, height int) (int, int) {
+	scale := 1.0
+	if s.MaxWidth > 0 && width > s.MaxWidth {
+		scale = min(scale, float64(s.MaxWidth)/float64(width))
+	}
+	if s.MaxHeight > 0 && height > s.MaxHeight {
+		scale = min(scale, float64(s.MaxHeight)/float64(height))
+	}
+	if scale == 1 {
+		return width, height
+	}
+	return max(int(float64(width)*scale+0.5), 1), max(int(float64(height)*scale+0.5), 1)
+}
+
+// Variant is a stored variant of an image file.
+type Variant struct {
+	Name         string    `json:"name"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	ContentType  string    `json:"contentType"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"lastModified"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVariantSpec_Fit(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          VariantSpec
+		width, height int
+		wantWidth     int
+		wantHeight    int
+	}{
+		{name: "not limited", spec: VariantSpec{}, width: 4000, height: 3000, wantWidth: 4000, wantHeight: 3000},
+		{name: "smaller than the bounds", spec: VariantSpec{MaxWidth: 256, MaxHeight: 256}, width: 100, height: 50, wantWidth: 100, wantHeight: 50},
+		{name: "at the bounds", spec: VariantSpec{MaxWidth: 256, MaxHeight: 256}, width: 256, height: 256, wantWidth: 256, wantHeight: 256},
+		{name: "landscape", spec: VariantSpec{MaxWidth: 256, MaxHeight: 256}, width: 1024, height: 512, wantWidth: 256, wantHeight: 128},
+		{name: "portrait", spec: VariantSpec{MaxWidth: 256, MaxHeight: 256}, width: 512, height: 1024, wantWidth: 128, wantHeight: 256},
+		{name: "the tighter bound wins", spec: VariantSpec{MaxWidth: 100, MaxHeight: 200}, width: 400, height: 400, wantWidth: 100, wantHeight: 100},
+		{name: "width only", spec: VariantSpec{MaxWidth: 100}, width: 400, height: 1000, wantWidth: 100, wantHeight: 250},
+		{name: "height only", spec: VariantSpec{MaxHeight: 100}, width: 1000, height: 400, wantWidth: 250, wantHeight: 100},
+		{name: "rounded", spec: VariantSpec{MaxWidth: 256, MaxHeight: 256}, width: 1000, height: 333, wantWidth: 256, wantHeight: 85},
+		{name: "at least a pixel", spec: VariantSpec{MaxWidth: 256, MaxHeight: 256}, width: 10000, height: 1, wantWidth: 256, wantHeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := tt.spec.Fit(tt.width, tt.height)
+
+			assert.Equal(t, tt.wantWidth, width)
+			assert.Equal(t, tt.wantHeight, height)
+		})
+	}
+}
+
+func TestLookupVariant(t *testing.T) {
+	for _, name := range []string{VariantThumb, VariantMedium, VariantOriginal} {
+		spec, ok := LookupVariant(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, name, spec.Name)
+	}
+
+	_, ok := LookupVariant("large")
+	assert.False(t, ok)
+}
@@ -0,0 +1,133 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"strconv"
+	"testing"
+)
+
+// exifSegment returns an APP1 segment with TIFF structured EXIF metadata of the orientation, and of GPS coordinates
+// after it if gps is set.
+func exifSegment(order binary.ByteOrder, orientation int, gps bool) []byte {
+	var tiff bytes.Buffer
+	if order == binary.LittleEndian {
+		tiff.WriteString("II")
+	} else {
+		tiff.WriteString("MM")
+	}
+	_ = binary.Write(&tiff, order, uint16(42))
+	_ = binary.Write(&tiff, order, uint32(8))
+
+	entries := []uint16{0x010F} // make, before the orientation
+	if orientation > 0 {
+		entries = append(entries, orientationTag)
+	}
+	if gps {
+		entries = append(entries, 0x8825) // pointer to the GPS metadata
+	}
+	_ = binary.Write(&tiff, order, uint16(len(entries)))
+	for _, tag := range entries {
+		_ = binary.Write(&tiff, order, tag)
+		_ = binary.Write(&tiff, order, uint16(3)) // short
+		_ = binary.Write(&tiff, order, uint32(1))
+		value := uint16(0)
+		if tag == orientationTag {
+			value = uint16(orientation)
+		}
+		_ = binary.Write(&tiff, order, value)
+		_ = binary.Write(&tiff, order, uint16(0))
+	}
+	_ = binary.Write(&tiff, order, uint32(0))
+	if gps {
+		tiff.WriteString("GPS 48.8584N 2.2945E")
+	}
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withSegments inserts segments after the start of a JPEG image.
+func withSegments(jpeg []byte, segments ...[]byte) []byte {
+	data := append([]byte{}, jpeg[:2]...)
+	for _, segment := range segments {
+		data = append(data, segment...)
+	}
+	return append(data, jpeg[2:]...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02, 0xFF, 0xD9}
+	app0 := []byte{0xFF, 0xE0, 0x00, 0x07, 'J', 'F', 'I', 'F', 0x00}
+	truncated := exifSegment(binary.BigEndian, 6, false)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "not a JPEG image", data: []byte("\x89PNG\r\n\x1a\n"), want: 1},
+		{name: "empty", data: nil, want: 1},
+		{name: "no EXIF metadata", data: jpeg, want: 1},
+		{name: "big endian", data: withSegments(jpeg, exifSegment(binary.BigEndian, 6, false)), want: 6},
+		{name: "little endian", data: withSegments(jpeg, exifSegment(binary.LittleEndian, 8, false)), want: 8},
+		{name: "after other segments", data: withSegments(jpeg, app0, exifSegment(binary.BigEndian, 3, true)), want: 3},
+		{name: "no orientation", data: withSegments(jpeg, exifSegment(binary.BigEndian, 0, true)), want: 1},
+		{name: "invalid orientation", data: withSegments(jpeg, exifSegment(binary.BigEndian, 9, false)), want: 1},
+		{name: "truncated segment", data: append([]byte{0xFF, 0xD8}, truncated[:len(truncated)-4]...), want: 1},
+		{name: "invalid byte order", data: withSegments(jpeg, bytes.Replace(exifSegment(binary.BigEndian, 6, false), []byte("MM"), []byte("XX"), 1)), want: 1},
+		{name: "after the image data", data: append(append([]byte{}, jpeg...), exifSegment(binary.BigEndian, 6, false)...), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exifOrientation(tt.data))
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// The pixels of a 3x2 image tell where they come from, red is x and green is y
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+
+	tests := []struct {
+		orientation int
+		width       int
+		height      int
+		topLeft     image.Point // where the top left pixel goes
+		topRight    image.Point // where the top right pixel goes
+	}{
+		{orientation: 0, width: 3, height: 2, topLeft: image.Pt(0, 0), topRight: image.Pt(2, 0)},
+		{orientation: 1, width: 3, height: 2, topLeft: image.Pt(0, 0), topRight: image.Pt(2, 0)},
+		{orientation: 2, width: 3, height: 2, topLeft: image.Pt(2, 0), topRight: image.Pt(0, 0)},
+		{orientation: 3, width: 3, height: 2, topLeft: image.Pt(2, 1), topRight: image.Pt(0, 1)},
+		{orientation: 4, width: 3, height: 2, topLeft: image.Pt(0, 1), topRight: image.Pt(2, 1)},
+		{orientation: 5, width: 2, height: 3, topLeft: image.Pt(0, 0), topRight: image.Pt(0, 2)},
+		{orientation: 6, width: 2, height: 3, topLeft: image.Pt(1, 0), topRight: image.Pt(1, 2)},
+		{orientation: 7, width: 2, height: 3, topLeft: image.Pt(1, 2), topRight: image.Pt(1, 0)},
+		{orientation: 8, width: 2, height: 3, topLeft: image.Pt(0, 2), topRight: image.Pt(0, 0)},
+		{orientation: 9, width: 3, height: 2, topLeft: image.Pt(0, 0), topRight: image.Pt(2, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.orientation), func(t *testing.T) {
+			dst := orient(src, tt.orientation)
+
+			assert.Equal(t, tt.width, dst.Bounds().Dx())
+			assert.Equal(t, tt.height, dst.Bounds().Dy())
+			assert.Equal(t, swapsAxes(tt.orientation), tt.width != 3)
+			assert.Equal(t, color.NRGBA{R: 0, G: 0, A: 255}, color.NRGBAModel.Convert(dst.At(tt.topLeft.X, tt.topLeft.Y)))
+			assert.Equal(t, color.NRGBA{R: 2, G: 0, A: 255}, color.NRGBAModel.Convert(dst.At(tt.topRight.X, tt.topRight.Y)))
+		})
+	}
+}
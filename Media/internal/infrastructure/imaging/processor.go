@@ -0,0 +1,154 @@
+// Package imaging makes the variants of images in pure Go, without external services.
+package imaging
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// DefaultQuality is the default quality of JPEG variants.
+const DefaultQuality = 85
+
+var _ usecases.ImageProcessorInterface = &Processor{}
+
+// Processor makes the variants of JPEG, PNG, GIF and WebP images.
+//
+// Variants are encoded again from the decoded pixels, so none of the metadata of the image is kept,
+// like the EXIF metadata with the location of photos. The EXIF orientation is applied to the pixels before.
+// Opaque images get JPEG variants, images with transparency get PNG variants, animations keep their first frame only.
+type Processor struct {
+	MaxPixels int // larger images are not decoded, not limited if 0
+	Quality   int // quality of JPEG variants, from 1 to 100
+}
+
+func (p *Processor) Supports(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch mediaType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+func (p *Processor) MakeVariants(content io.Reader, specs []domain.VariantSpec) ([]usecases.VariantImage, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// The size is checked before the pixels are allocated
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Join(domain.ErrNotImage, err)
+	}
+	if p.MaxPixels > 0 && config.Width*config.Height > p.MaxPixels {
+		return nil, domain.ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Join(domain.ErrNotImage, err)
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = exifOrientation(data)
+	}
+
+	encoder := p.encodeJPEG
+	if format == "png" || format == "gif" || !opaque(img) {
+		encoder = encodePNG
+	}
+
+	variants := make([]usecases.VariantImage, 0, len(specs))
+	for _, spec := range specs {
+		variant, err := p.makeVariant(img, orientation, spec, encoder)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// encoder encodes an image and returns its content type.
+type encoder func(w io.Writer, img image.Image) (string, error)
+
+func (p *Processor) makeVariant(img image.Image, orientation int, spec domain.VariantSpec, encode encoder) (usecases.VariantImage, error) {
+	// The bounds of the variant apply to the oriented image
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if swapsAxes(orientation) {
+		height, width = spec.Fit(height, width)
+	} else {
+		width, height = spec.Fit(width, height)
+	}
+
+	if width != img.Bounds().Dx() || height != img.Bounds().Dy() {
+		scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Src, nil)
+		img = scaled
+	}
+	img = orient(img, orientation)
+
+	var buf bytes.Buffer
+	contentType, err := encode(&buf, img)
+	if err != nil {
+		return usecases.VariantImage{}, err
+	}
+
+	return usecases.VariantImage{
+		Variant: domain.Variant{
+			Name:        spec.Name,
+			Width:       img.Bounds().Dx(),
+			Height:      img.Bounds().Dy(),
+			ContentType: contentType,
+			Size:        int64(buf.Len()),
+		},
+		Content: buf.Bytes(),
+	}, nil
+}
+
+func (p *Processor) encodeJPEG(w io.Writer, img image.Image) (string, error) {
+	quality := p.Quality
+	if quality <= 0 {
+		quality = DefaultQuality
+	}
+	return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+func encodePNG(w io.Writer, img image.Image) (string, error) {
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	return "image/png", encoder.Encode(w, img)
+}
+
+// opaque reports whether an image has no transparent pixels.
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// NewProcessor creates a new Processor, maxPixels is not limited if 0.
+func NewProcessor(maxPixels int, quality int) *Processor {
+	return &Processor{
+		MaxPixels: maxPixels,
+		Quality:   quality,
+	}
+}
@@ -0,0 +1,140 @@
+package imaging
+
+import (
+	"Media/internal/domain"
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testImage returns an image of the given size, with transparent pixels if transparent is set.
+func testImage(width int, height int, transparent bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			alpha := uint8(255)
+			if transparent && x < width/2 {
+				alpha = 0
+			}
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: alpha})
+		}
+	}
+	return img
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+func encodeTestPNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestProcessor_Supports(t *testing.T) {
+	p := NewProcessor(0, 0)
+
+	assert.True(t, p.Supports("image/jpeg"))
+	assert.True(t, p.Supports("image/png"))
+	assert.True(t, p.Supports("image/gif"))
+	assert.True(t, p.Supports("image/webp"))
+	assert.True(t, p.Supports("image/png; charset=binary"))
+	assert.False(t, p.Supports("image/svg+xml"))
+	assert.False(t, p.Supports("text/plain; charset=utf-8"))
+	assert.False(t, p.Supports(""))
+}
+
+func TestProcessor_MakeVariants(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     []byte
+		contentType string
+		sizes       map[string][2]int
+	}{
+		{
+			name:        "opaque image",
+			content:     encodeTestPNG(t, testImage(2000, 1000, false)),
+			contentType: "image/png",
+			sizes:       map[string][2]int{domain.VariantThumb: {256, 128}, domain.VariantMedium: {1280, 640}, domain.VariantOriginal: {2000, 1000}},
+		},
+		{
+			name:        "photo",
+			content:     encodeTestJPEG(t, testImage(600, 800, false)),
+			contentType: "image/jpeg",
+			sizes:       map[string][2]int{domain.VariantThumb: {192, 256}, domain.VariantMedium: {600, 800}, domain.VariantOriginal: {600, 800}},
+		},
+		{
+			name:        "transparent image",
+			content:     encodeTestPNG(t, testImage(300, 300, true)),
+			contentType: "image/png",
+			sizes:       map[string][2]int{domain.VariantThumb: {256, 256}, domain.VariantMedium: {300, 300}, domain.VariantOriginal: {300, 300}},
+		},
+		{
+			name:        "rotated photo",
+			content:     withSegments(encodeTestJPEG(t, testImage(800, 600, false)), exifSegment(binary.BigEndian, 6, false)),
+			contentType: "image/jpeg",
+			sizes:       map[string][2]int{domain.VariantThumb: {192, 256}, domain.VariantMedium: {600, 800}, domain.VariantOriginal: {600, 800}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := NewProcessor(0, 0).MakeVariants(bytes.NewReader(tt.content), domain.Variants)
+			require.NoError(t, err)
+			require.Len(t, variants, len(domain.Variants))
+
+			for i, variant := range variants {
+				assert.Equal(t, domain.Variants[i].Name, variant.Name)
+				assert.Equal(t, tt.contentType, variant.ContentType)
+				assert.Equal(t, int64(len(variant.Content)), variant.Size)
+
+				size := tt.sizes[variant.Name]
+				assert.Equal(t, size[0], variant.Width, variant.Name)
+				assert.Equal(t, size[1], variant.Height, variant.Name)
+
+				config, format, err := image.DecodeConfig(bytes.NewReader(variant.Content))
+				require.NoError(t, err)
+				assert.Equal(t, strings.TrimPrefix(tt.contentType, "image/"), format)
+				assert.Equal(t, size[0], config.Width)
+				assert.Equal(t, size[1], config.Height)
+			}
+		})
+	}
+}
+
+func TestProcessor_MakeVariants_StripsMetadata(t *testing.T) {
+	content := withSegments(encodeTestJPEG(t, testImage(64, 48, false)), exifSegment(binary.LittleEndian, 1, true))
+	require.Contains(t, string(content), "GPS")
+
+	variants, err := NewProcessor(0, 0).MakeVariants(bytes.NewReader(content), domain.Variants)
+	require.NoError(t, err)
+
+	for _, variant := range variants {
+		assert.NotContains(t, string(variant.Content), "Exif", variant.Name)
+		assert.NotContains(t, string(variant.Content), "GPS", variant.Name)
+		assert.Equal(t, 1, exifOrientation(variant.Content), variant.Name)
+	}
+}
+
+func TestProcessor_MakeVariants_Errors(t *testing.T) {
+	_, err := NewProcessor(0, 0).MakeVariants(strings.NewReader("hello world"), domain.Variants)
+	assert.ErrorIs(t, err, domain.ErrNotImage)
+
+	// Truncated images are described but cannot be decoded
+	content := encodeTestPNG(t, testImage(64, 64, false))
+	_, err = NewProcessor(0, 0).MakeVariants(bytes.NewReader(content[:len(content)/2]), domain.Variants)
+	assert.ErrorIs(t, err, domain.ErrNotImage)
+
+	_, err = NewProcessor(100*100-1, 0).MakeVariants(bytes.NewReader(encodeTestPNG(t, testImage(100, 100, false))), domain.Variants)
+	assert.ErrorIs(t, err, domain.ErrTooLarge)
+}
@@ -0,0 +1,376 @@
+// Package conformance checks that the storage backends of Media behave the same.
+// Each backend runs the suite from its own tests.
+package conformance
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Backend is the set of repositories of a storage backend.
+type Backend struct {
+	Files    usecases.FileRepositoryInterface
+	Blobs    usecases.BlobRepositoryInterface
+	Uploads  usecases.UploadRepositoryInterface
+	Usage    usecases.UsageRepositoryInterface
+	Variants usecases.VariantRepositoryInterface
+}
+
+// Run runs the suite against backends made by setup, each test gets a new backend.
+func Run(t *testing.T, setup func(t *testing.T) Backend) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, backend Backend)
+	}{
+		{"CreateFile", testCreateFile},
+		{"GetFile_Range", testGetFileRange},
+		{"GetFile_Modified", testGetFileModified},
+		{"NotFound", testNotFound},
+		{"UpdateFile", testUpdateFile},
+		{"DeleteFile", testDeleteFile},
+		{"GetFilesModifiedBefore", testGetFilesModifiedBefore},
+		{"LinkFile", testLinkFile},
+		{"Blobs_LastReference", testBlobsLastReference},
+		{"Uploads", testUploads},
+		{"Uploads_Expired", testUploadsExpired},
+		{"Usage", testUsage},
+		{"Variants", testVariants},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, setup(t))
+		})
+	}
+}
+
+func newFile(content string) domain.File {
+	return domain.File{
+		ID:          uuid.New(),
+		AuthorID:    uuid.New(),
+		Name:        "notes.txt",
+		Size:        int64(len(content)),
+		ContentType: "text/plain; charset=utf-8",
+		Access:      domain.Access{Visibility: domain.VisibilityShared, SharedWith: []uuid.UUID{uuid.New()}},
+	}
+}
+
+func createFile(t *testing.T, backend Backend, content string) domain.File {
+	file := newFile(content)
+	require.NoError(t, backend.Files.CreateFile(context.Background(), file, strings.NewReader(content)))
+	return file
+}
+
+func readFile(t *testing.T, backend Backend, id uuid.UUID, opts domain.ReadOptions) string {
+	content, err := backend.Files.GetFile(context.Background(), id, opts)
+	require.NoError(t, err)
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func testCreateFile(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	for _, size := range []int64{11, -1} {
+		file := newFile("hello world")
+		file.Size = size
+		require.NoError(t, backend.Files.CreateFile(ctx, file, strings.NewReader("hello world")))
+
+		found, err := backend.Files.StatFile(ctx, file.ID)
+		require.NoError(t, err)
+		assert.Equal(t, file.ID, found.ID)
+		assert.Equal(t, file.AuthorID, found.AuthorID)
+		assert.Equal(t, file.Name, found.Name)
+		assert.Equal(t, int64(11), found.Size)
+		assert.Equal(t, file.ContentType, found.ContentType)
+		assert.Equal(t, file.Access, found.Access)
+		assert.NotEmpty(t, found.ETag)
+		assert.False(t, found.LastModified.IsZero())
+		assert.Empty(t, found.Checksum)
+
+		assert.Equal(t, "hello world", readFile(t, backend, file.ID, domain.ReadOptions{}))
+	}
+}
+
+func testGetFileRange(t *testing.T, backend Backend) {
+	file := createFile(t, backend, "hello world")
+
+	content := readFile(t, backend, file.ID, domain.ReadOptions{Range: &domain.ByteRange{Start: 6, End: 10}})
+	assert.Equal(t, "world", content)
+}
+
+func testGetFileModified(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	file := createFile(t, backend, "hello world")
+
+	found, err := backend.Files.StatFile(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", readFile(t, backend, file.ID, domain.ReadOptions{ETag: found.ETag}))
+
+	_, err = backend.Files.GetFile(ctx, file.ID, domain.ReadOptions{ETag: "0123456789abcdef0123456789abcdef"})
+	assert.ErrorIs(t, err, domain.ErrModified)
+}
+
+func testNotFound(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	_, err := backend.Files.StatFile(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = backend.Files.GetFile(ctx, id, domain.ReadOptions{})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = backend.Files.UpdateFile(ctx, domain.File{ID: id, Name: "missing.txt"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	err = backend.Files.LinkFile(ctx, id, checksum(""))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testUpdateFile(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	file := createFile(t, backend, "hello world")
+
+	file.Access = domain.Access{Visibility: domain.VisibilityPublic}
+	require.NoError(t, backend.Files.UpdateFile(ctx, file))
+
+	found, err := backend.Files.StatFile(ctx, file.ID)
+	require.NoError(t, err)
+	assert.Equal(t, file.Access, found.Access)
+	assert.Equal(t, file.Name, found.Name)
+	assert.Equal(t, file.ContentType, found.ContentType)
+	assert.Equal(t, "hello world", readFile(t, backend, file.ID, domain.ReadOptions{}))
+}
+
+func testDeleteFile(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	file := createFile(t, backend, "hello world")
+
+	require.NoError(t, backend.Files.DeleteFile(ctx, file.ID))
+
+	_, err := backend.Files.StatFile(ctx, file.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Deleting is idempotent
+	assert.NoError(t, backend.Files.DeleteFile(ctx, file.ID))
+}
+
+func testGetFilesModifiedBefore(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	plain := createFile(t, backend, "hello world")
+	linked := createFile(t, backend, "linked")
+	require.NoError(t, backend.Blobs.AddReference(ctx, checksum("linked"), linked.ID))
+	require.NoError(t, backend.Files.LinkFile(ctx, linked.ID, checksum("linked")))
+
+	// Blobs and other objects of the storage are not files
+	ids, err := backend.Files.GetFilesModifiedBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{plain.ID, linked.ID}, ids)
+
+	ids, err = backend.Files.GetFilesModifiedBefore(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	require.NoError(t, backend.Files.DeleteFile(ctx, plain.ID))
+	ids, err = backend.Files.GetFilesModifiedBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{linked.ID}, ids)
+}
+
+func testLinkFile(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	first := createFile(t, backend, "hello world")
+	second := createFile(t, backend, "hello world")
+	sum := checksum("hello world")
+
+	for _, file := range []domain.File{first, second} {
+		require.NoError(t, backend.Blobs.AddReference(ctx, sum, file.ID))
+		require.NoError(t, backend.Files.LinkFile(ctx, file.ID, sum))
+	}
+
+	for _, file := range []domain.File{first, second} {
+		found, err := backend.Files.StatFile(ctx, file.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sum, found.Checksum)
+		assert.Equal(t, int64(11), found.Size)
+		assert.Equal(t, file.Name, found.Name)
+		assert.Equal(t, file.Access, found.Access)
+
+		assert.Equal(t, "hello world", readFile(t, backend, file.ID, domain.ReadOptions{ETag: found.ETag}))
+		assert.Equal(t, "hello", readFile(t, backend, file.ID, domain.ReadOptions{Range: &domain.ByteRange{Start: 0, End: 4}}))
+	}
+
+	// Linked files keep their blob when their metadata changes
+	first.Access = domain.Access{Visibility: domain.VisibilityPrivate}
+	first.Checksum = sum
+	require.NoError(t, backend.Files.UpdateFile(ctx, first))
+	found, err := backend.Files.StatFile(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sum, found.Checksum)
+	assert.Equal(t, "hello world", readFile(t, backend, first.ID, domain.ReadOptions{}))
+}
+
+func testBlobsLastReference(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	sum := checksum("hello world")
+
+	first := createFile(t, backend, "hello world")
+	require.NoError(t, backend.Blobs.AddReference(ctx, sum, first.ID))
+	require.NoError(t, backend.Files.LinkFile(ctx, first.ID, sum))
+	second := createFile(t, backend, "hello world")
+	require.NoError(t, backend.Blobs.AddReference(ctx, sum, second.ID))
+	require.NoError(t, backend.Files.LinkFile(ctx, second.ID, sum))
+
+	// The blob outlives the first file
+	require.NoError(t, backend.Files.DeleteFile(ctx, first.ID))
+	require.NoError(t, backend.Blobs.RemoveReference(ctx, sum))
+	assert.Equal(t, "hello world", readFile(t, backend, second.ID, domain.ReadOptions{}))
+
+	// A blob removed with its last reference is created again from the next file with its content
+	require.NoError(t, backend.Files.DeleteFile(ctx, second.ID))
+	require.NoError(t, backend.Blobs.RemoveReference(ctx, sum))
+	third := createFile(t, backend, "hello world")
+	require.NoError(t, backend.Blobs.AddReference(ctx, sum, third.ID))
+	require.NoError(t, backend.Files.LinkFile(ctx, third.ID, sum))
+	assert.Equal(t, "hello world", readFile(t, backend, third.ID, domain.ReadOptions{}))
+}
+
+func newUpload(size int64) *domain.Upload {
+	now := time.Now().UTC()
+	return &domain.Upload{
+		ID:          uuid.New(),
+		AuthorID:    uuid.New(),
+		Name:        "upload.txt",
+		Size:        size,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+		Access:      domain.Access{Visibility: domain.VisibilityPrivate},
+		Purpose:     domain.PurposeGeneric,
+		ContentType: "text/plain; charset=utf-8",
+	}
+}
+
+func testUploads(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	upload := newUpload(11)
+	require.NoError(t, backend.Uploads.CreateUpload(ctx, upload))
+
+	require.NoError(t, backend.Uploads.AppendUpload(ctx, upload, strings.NewReader("hello ")))
+	assert.Equal(t, int64(6), upload.Offset)
+
+	found, err := backend.Uploads.GetUpload(ctx, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), found.Offset)
+	assert.Equal(t, upload.Name, found.Name)
+
+	require.NoError(t, backend.Uploads.AppendUpload(ctx, found, strings.NewReader("world")))
+	require.True(t, found.Done())
+	require.NoError(t, backend.Uploads.CompleteUpload(ctx, found))
+
+	_, err = backend.Uploads.GetUpload(ctx, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	file, err := backend.Files.StatFile(ctx, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, upload.AuthorID, file.AuthorID)
+	assert.Equal(t, upload.Name, file.Name)
+	assert.Equal(t, int64(11), file.Size)
+	assert.Equal(t, upload.ContentType, file.ContentType)
+	assert.Equal(t, "hello world", readFile(t, backend, upload.ID, domain.ReadOptions{}))
+}
+
+func testUploadsExpired(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	expired, active := newUpload(11), newUpload(11)
+	expired.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+	require.NoError(t, backend.Uploads.CreateUpload(ctx, expired))
+	require.NoError(t, backend.Uploads.CreateUpload(ctx, active))
+	require.NoError(t, backend.Uploads.AppendUpload(ctx, expired, strings.NewReader("hello ")))
+
+	uploads, err := backend.Uploads.GetExpiredUploads(ctx, time.Now())
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for _, upload := range uploads {
+		ids = append(ids, upload.ID)
+	}
+	assert.Contains(t, ids, expired.ID)
+	assert.NotContains(t, ids, active.ID)
+
+	require.NoError(t, backend.Uploads.DeleteUpload(ctx, expired))
+	_, err = backend.Uploads.GetUpload(ctx, expired.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testUsage(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	author := uuid.New()
+
+	used, err := backend.Usage.GetUsage(ctx, author)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), used)
+
+	require.NoError(t, backend.Usage.AddUsage(ctx, author, 100))
+	require.NoError(t, backend.Usage.AddUsage(ctx, author, -30))
+	used, err = backend.Usage.GetUsage(ctx, author)
+	require.NoError(t, err)
+	assert.Equal(t, int64(70), used)
+
+	// Usage never goes below zero
+	require.NoError(t, backend.Usage.AddUsage(ctx, author, -100))
+	used, err = backend.Usage.GetUsage(ctx, author)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), used)
+}
+
+func testVariants(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	fileID := uuid.New()
+	image := usecases.VariantImage{
+		Variant: domain.Variant{Name: domain.VariantThumb, Width: 4, Height: 3, ContentType: "image/jpeg"},
+		Content: []byte("not really a jpeg"),
+	}
+	require.NoError(t, backend.Variants.PutVariant(ctx, fileID, image))
+
+	variant, err := backend.Variants.StatVariant(ctx, fileID, domain.VariantThumb)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VariantThumb, variant.Name)
+	assert.Equal(t, 4, variant.Width)
+	assert.Equal(t, 3, variant.Height)
+	assert.Equal(t, "image/jpeg", variant.ContentType)
+	assert.Equal(t, int64(len(image.Content)), variant.Size)
+	assert.NotEmpty(t, variant.ETag)
+
+	content, err := backend.Variants.GetVariant(ctx, fileID, domain.VariantThumb, domain.ReadOptions{Range: &domain.ByteRange{Start: 0, End: 2}})
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	assert.True(t, bytes.Equal([]byte("not"), data))
+
+	_, err = backend.Variants.StatVariant(ctx, fileID, domain.VariantMedium)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	require.NoError(t, backend.Variants.DeleteVariants(ctx, fileID))
+	_, err = backend.Variants.StatVariant(ctx, fileID, domain.VariantThumb)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
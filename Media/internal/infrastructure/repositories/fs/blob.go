@@ -0,0 +1,121 @@
+package fsRepo
+
+import (
+	"Media/internal/usecases"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var _ usecases.BlobRepositoryInterface = &BlobRepository{}
+
+// BlobRepository stores blobs as files named after their checksum, with the number of files referencing them in a counter file.
+//
+// Updates are serialized within the process only, so the directory must not be shared by several instances.
+type BlobRepository struct {
+	root   string
+	mu     sync.Mutex
+	logger *slog.Logger
+}
+
+func (b *BlobRepository) AddReference(ctx context.Context, checksum string, fileID uuid.UUID) error {
+	const op = "BlobRepository.AddReference"
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	refs, err := readCounter(refsPath(b.root, checksum))
+	if err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	if refs == 0 {
+		if err := b.createBlob(checksum, fileID); err != nil {
+			b.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+
+	if err := writeCounter(b.root, refsPath(b.root, checksum), refs+1); err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (b *BlobRepository) RemoveReference(ctx context.Context, checksum string) error {
+	const op = "BlobRepository.RemoveReference"
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	refs, err := readCounter(refsPath(b.root, checksum))
+	if err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	if refs > 1 {
+		if err := writeCounter(b.root, refsPath(b.root, checksum), refs-1); err != nil {
+			b.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+		return nil
+	}
+
+	for _, path := range []string{blobPath(b.root, checksum), refsPath(b.root, checksum)} {
+		if err := removeIfExists(path); err != nil {
+			b.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+// createBlob links the content of a file to the blob with its checksum, unless the blob exists.
+// The content is copied if it cannot be linked.
+func (b *BlobRepository) createBlob(checksum string, fileID uuid.UUID) error {
+	path := blobPath(b.root, checksum)
+	if _, err := os.Stat(path); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	source := contentPath(b.root, fileID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.Link(source, path); err == nil {
+		return nil
+	}
+
+	content, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	_, err = writeAtomic(b.root, path, content)
+	return err
+}
+
+func blobPath(root string, checksum string) string {
+	return filepath.Join(root, blobsDir, checksum)
+}
+
+func refsPath(root string, checksum string) string {
+	return filepath.Join(root, refsDir, checksum)
+}
+
+// NewBlobRepository creates a new BlobRepository storing blobs under root, next to the files of a FileRepository.
+func NewBlobRepository(root string, logger *slog.Logger) *BlobRepository {
+	return &BlobRepository{
+		root:   root,
+		logger: logger,
+	}
+}
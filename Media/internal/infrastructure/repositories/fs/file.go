@@ -0,0 +1,222 @@
+package fsRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var _ usecases.FileRepositoryInterface = &FileRepository{}
+
+// FileRepository stores files in a directory, with the content of each file next to a JSON sidecar with its metadata.
+//
+// Files are written atomically: the content is written first, and the file exists once its sidecar is renamed in place.
+// Files linked to a blob have no content of their own.
+type FileRepository struct {
+	root   string
+	logger *slog.Logger
+}
+
+func (f *FileRepository) CreateFile(ctx context.Context, file domain.File, content io.Reader) error {
+	const op = "FileRepository.CreateFile"
+
+	if file.Size >= 0 {
+		content = io.LimitReader(content, file.Size)
+	}
+	size, err := writeAtomic(f.root, contentPath(f.root, file.ID), content)
+	if err != nil {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	if file.Size >= 0 && size != file.Size {
+		f.logger.Error(op, slog.Any("error", io.ErrUnexpectedEOF.Error()))
+		if err := removeIfExists(contentPath(f.root, file.ID)); err != nil {
+			f.logger.Error(op, slog.Any("error", err.Error()))
+		}
+		return io.ErrUnexpectedEOF
+	}
+
+	file.Size = size
+	file.ContentType = contentType(file.ContentType)
+	file.ETag = newETag()
+	file.LastModified = time.Now().UTC()
+	file.Checksum = ""
+	if err := writeSidecar(f.root, sidecarPath(f.root, file.ID), file); err != nil {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (f *FileRepository) StatFile(ctx context.Context, id uuid.UUID) (domain.File, error) {
+	const op = "FileRepository.StatFile"
+
+	var file domain.File
+	if err := readSidecar(sidecarPath(f.root, id), &file); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			f.logger.Error(op, slog.Any("error", err.Error()))
+		}
+		return domain.File{}, err
+	}
+
+	// The content of linked files is described by their blob, which never changes
+	if file.Checksum != "" {
+		info, err := os.Stat(blobPath(f.root, file.Checksum))
+		if err != nil {
+			f.logger.Error(op, slog.Any("error", err.Error()))
+			return domain.File{}, err
+		}
+		file.Size = info.Size()
+		file.ETag = file.Checksum
+	}
+
+	return file, nil
+}
+
+// UpdateFile replaces the name and the access control of a file.
+func (f *FileRepository) UpdateFile(ctx context.Context, file domain.File) error {
+	const op = "FileRepository.UpdateFile"
+
+	var stored domain.File
+	if err := readSidecar(sidecarPath(f.root, file.ID), &stored); err != nil {
+		return err
+	}
+
+	stored.Name = file.Name
+	stored.AuthorID = file.AuthorID
+	stored.Access = file.Access
+	if file.ContentType != "" {
+		stored.ContentType = file.ContentType
+	}
+	if err := writeSidecar(f.root, sidecarPath(f.root, file.ID), stored); err != nil {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// LinkFile saves the checksum of the blob of a file in its sidecar, and removes its own content.
+func (f *FileRepository) LinkFile(ctx context.Context, id uuid.UUID, checksum string) error {
+	const op = "FileRepository.LinkFile"
+
+	var file domain.File
+	if err := readSidecar(sidecarPath(f.root, id), &file); err != nil {
+		return err
+	}
+
+	file.Checksum = checksum
+	if err := writeSidecar(f.root, sidecarPath(f.root, id), file); err != nil {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	if err := removeIfExists(contentPath(f.root, id)); err != nil {
+		// The file is read from its blob already, the content only takes space
+		f.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return nil
+}
+
+func (f *FileRepository) GetFile(ctx context.Context, id uuid.UUID, opts domain.ReadOptions) (io.ReadCloser, error) {
+	const op = "FileRepository.GetFile"
+
+	file, err := f.StatFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	path := contentPath(f.root, id)
+	if file.Checksum != "" {
+		path = blobPath(f.root, file.Checksum)
+	}
+
+	content, err := openContent(path, file.ETag, opts)
+	if err != nil && !errors.Is(err, domain.ErrModified) && !errors.Is(err, domain.ErrNotFound) {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return content, err
+}
+
+func (f *FileRepository) DeleteFile(ctx context.Context, id uuid.UUID) error {
+	const op = "FileRepository.DeleteFile"
+
+	// The file is gone with its sidecar, the content is removed after it
+	for _, path := range []string{sidecarPath(f.root, id), contentPath(f.root, id)} {
+		if err := removeIfExists(path); err != nil {
+			f.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFilesModifiedBefore lists the sidecars of the files, which are written last.
+func (f *FileRepository) GetFilesModifiedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	const op = "FileRepository.GetFilesModifiedBefore"
+
+	entries, err := os.ReadDir(filepath.Join(f.root, filesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), sidecarSuffix) {
+			continue
+		}
+		id, err := uuid.Parse(strings.TrimSuffix(entry.Name(), sidecarSuffix))
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			// Deleted while listing
+			continue
+		}
+		if err != nil {
+			f.logger.Error(op, slog.Any("error", err.Error()))
+			return nil, err
+		}
+		if info.ModTime().Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func contentPath(root string, id uuid.UUID) string {
+	return filepath.Join(root, filesDir, id.String())
+}
+
+func sidecarPath(root string, id uuid.UUID) string {
+	return contentPath(root, id) + sidecarSuffix
+}
+
+// contentType returns the content type files are stored with, application/octet-stream if it is not known.
+func contentType(contentType string) string {
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
+
+// NewFileRepository creates a new FileRepository storing files under root.
+func NewFileRepository(root string, logger *slog.Logger) *FileRepository {
+	return &FileRepository{
+		root:   root,
+		logger: logger,
+	}
+}
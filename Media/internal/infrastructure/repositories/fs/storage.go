@@ -0,0 +1,137 @@
+package fsRepo
+
+import (
+	"Media/internal/domain"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Directories of the storage root, named like the prefixes of the MinIO objects.
+const (
+	filesDir    = "files"
+	blobsDir    = "blobs"
+	refsDir     = "refs"
+	usageDir    = "usage"
+	variantsDir = "variants"
+	uploadsDir  = "uploads"
+	tmpDir      = "tmp"
+)
+
+// sidecarSuffix is the suffix of the JSON files with the metadata of content next to it.
+const sidecarSuffix = ".json"
+
+// writeAtomic writes content to a temporary file and renames it to path, so readers never see a partial file.
+// It returns the number of bytes written.
+func writeAtomic(root string, path string, content io.Reader) (int64, error) {
+	if err := os.MkdirAll(filepath.Join(root, tmpDir), 0o755); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(root, tmpDir), "write-*")
+	if err != nil {
+		return 0, err
+	}
+	// Removing fails once the file is renamed
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, content)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return n, err
+	}
+
+	return n, os.Rename(tmp.Name(), path)
+}
+
+// writeSidecar replaces a JSON file atomically.
+func writeSidecar(root string, path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = writeAtomic(root, path, strings.NewReader(string(data)))
+	return err
+}
+
+// readSidecar reads a JSON file, it fails with domain.ErrNotFound if the file does not exist.
+func readSidecar(path string, value any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// readCounter returns the number kept as text in a file, 0 if the file does not exist.
+func readCounter(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// writeCounter replaces the number kept in a file.
+func writeCounter(root string, path string, value int64) error {
+	_, err := writeAtomic(root, path, strings.NewReader(strconv.FormatInt(value, 10)))
+	return err
+}
+
+// removeIfExists removes a file, files that do not exist are already removed.
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// openContent opens a file, or a part of it, if etag matches the ETag of its content.
+func openContent(path string, etag string, opts domain.ReadOptions) (io.ReadCloser, error) {
+	if opts.ETag != "" && opts.ETag != etag {
+		return nil, domain.ErrModified
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.Range == nil {
+		return file, nil
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.NewSectionReader(file, opts.Range.Start, opts.Range.Length()), file}, nil
+}
+
+// newETag returns an ETag for content written once, content is never changed in place.
+func newETag() string {
+	id := uuid.New()
+	return hex.EncodeToString(id[:])
+}
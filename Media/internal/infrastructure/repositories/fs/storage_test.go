@@ -0,0 +1,22 @@
+package fsRepo
+
+import (
+	"Media/internal/infrastructure/repositories/conformance"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Backend {
+		root := t.TempDir()
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		return conformance.Backend{
+			Files:    NewFileRepository(root, logger),
+			Blobs:    NewBlobRepository(root, logger),
+			Uploads:  NewUploadRepository(root, logger),
+			Usage:    NewUsageRepository(root, logger),
+			Variants: NewVariantRepository(root, logger),
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package fsRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const pendingSuffix = ".part"
+
+var _ usecases.UploadRepositoryInterface = &UploadRepository{}
+
+// UploadRepository stores resumable uploads as files the received content is appended to.
+//
+// The state of an upload is saved in a JSON sidecar, so uploads survive restarts.
+// Content past the saved offset was not acknowledged, so it is cut before more content is appended.
+type UploadRepository struct {
+	root   string
+	logger *slog.Logger
+}
+
+func (u *UploadRepository) CreateUpload(ctx context.Context, upload *domain.Upload) error {
+	return u.save(upload)
+}
+
+func (u *UploadRepository) GetUpload(ctx context.Context, id uuid.UUID) (*domain.Upload, error) {
+	const op = "UploadRepository.GetUpload"
+
+	var upload domain.Upload
+	if err := readSidecar(u.infoPath(id), &upload); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (u *UploadRepository) AppendUpload(ctx context.Context, upload *domain.Upload, content io.Reader) error {
+	const op = "UploadRepository.AppendUpload"
+
+	if err := os.MkdirAll(filepath.Join(u.root, uploadsDir), 0o755); err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	pending, err := os.OpenFile(u.pendingPath(upload.ID), os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	defer func(pending *os.File) {
+		if err := pending.Close(); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}(pending)
+
+	if err := pending.Truncate(upload.Offset); err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	if _, err := pending.Seek(upload.Offset, io.SeekStart); err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	n, readErr := io.Copy(pending, content)
+	if n > 0 {
+		if err := pending.Sync(); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+		upload.Offset += n
+		if err := u.save(upload); err != nil {
+			return err
+		}
+	}
+
+	if readErr != nil {
+		u.logger.Error(op, slog.Any("error", readErr.Error()))
+		return readErr
+	}
+	return nil
+}
+
+func (u *UploadRepository) CompleteUpload(ctx context.Context, upload *domain.Upload) error {
+	const op = "UploadRepository.CompleteUpload"
+
+	path := contentPath(u.root, upload.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	if upload.Offset == 0 {
+		// Empty files receive no content
+		if _, err := writeAtomic(u.root, path, bytes.NewReader(nil)); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	} else if err := os.Rename(u.pendingPath(upload.ID), path); err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	file := domain.File{
+		ID:           upload.ID,
+		AuthorID:     upload.AuthorID,
+		Name:         upload.Name,
+		Size:         upload.Size,
+		ContentType:  contentType(upload.ContentType),
+		ETag:         newETag(),
+		LastModified: time.Now().UTC(),
+		Access:       upload.Access,
+	}
+	if err := writeSidecar(u.root, sidecarPath(u.root, upload.ID), file); err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	return u.remove(upload.ID)
+}
+
+func (u *UploadRepository) DeleteUpload(ctx context.Context, upload *domain.Upload) error {
+	return u.remove(upload.ID)
+}
+
+func (u *UploadRepository) GetExpiredUploads(ctx context.Context, now time.Time) ([]*domain.Upload, error) {
+	const op = "UploadRepository.GetExpiredUploads"
+
+	entries, err := os.ReadDir(filepath.Join(u.root, uploadsDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	var expired []*domain.Upload
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), sidecarSuffix) {
+			continue
+		}
+
+		id, err := uuid.Parse(strings.TrimSuffix(entry.Name(), sidecarSuffix))
+		if err != nil {
+			continue
+		}
+
+		upload, err := u.GetUpload(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Completed while listing
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if upload.Expired(now) {
+			expired = append(expired, upload)
+		}
+	}
+
+	return expired, nil
+}
+
+// save saves the state of an upload.
+func (u *UploadRepository) save(upload *domain.Upload) error {
+	if err := writeSidecar(u.root, u.infoPath(upload.ID), upload); err != nil {
+		u.logger.Error("UploadRepository.save", slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// remove removes the state and the received content of an upload.
+func (u *UploadRepository) remove(id uuid.UUID) error {
+	for _, path := range []string{u.pendingPath(id), u.infoPath(id)} {
+		if err := removeIfExists(path); err != nil {
+			u.logger.Error("UploadRepository.remove", slog.Any("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UploadRepository) infoPath(id uuid.UUID) string {
+	return filepath.Join(u.root, uploadsDir, id.String()+sidecarSuffix)
+}
+
+func (u *UploadRepository) pendingPath(id uuid.UUID) string {
+	return filepath.Join(u.root, uploadsDir, id.String()+pendingSuffix)
+}
+
+// NewUploadRepository creates a new UploadRepository creating the files of done uploads under root, like a FileRepository.
+func NewUploadRepository(root string, logger *slog.Logger) *UploadRepository {
+	return &UploadRepository{
+		root:   root,
+		logger: logger,
+	}
+}
@@ -0,0 +1,57 @@
+package fsRepo
+
+import (
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"path/filepath"
+	"sync"
+)
+
+var _ usecases.UsageRepositoryInterface = &UsageRepository{}
+
+// UsageRepository keeps the number of bytes stored by each author in a counter file.
+//
+// Updates are serialized within the process only, so the directory must not be shared by several instances.
+type UsageRepository struct {
+	root   string
+	mu     sync.Mutex
+	logger *slog.Logger
+}
+
+func (u *UsageRepository) GetUsage(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	used, err := readCounter(u.usagePath(authorID))
+	if err != nil {
+		u.logger.Error("UsageRepository.GetUsage", slog.Any("error", err.Error()))
+		return 0, err
+	}
+	return used, nil
+}
+
+func (u *UsageRepository) AddUsage(ctx context.Context, authorID uuid.UUID, delta int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	used, err := u.GetUsage(ctx, authorID)
+	if err != nil {
+		return err
+	}
+
+	if err := writeCounter(u.root, u.usagePath(authorID), max(used+delta, 0)); err != nil {
+		u.logger.Error("UsageRepository.AddUsage", slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (u *UsageRepository) usagePath(authorID uuid.UUID) string {
+	return filepath.Join(u.root, usageDir, authorID.String())
+}
+
+func NewUsageRepository(root string, logger *slog.Logger) *UsageRepository {
+	return &UsageRepository{
+		root:   root,
+		logger: logger,
+	}
+}
@@ -0,0 +1,91 @@
+package fsRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ usecases.VariantRepositoryInterface = &VariantRepository{}
+
+// VariantRepository stores the variants of an image in a directory named after the image, each with a JSON sidecar.
+type VariantRepository struct {
+	root   string
+	logger *slog.Logger
+}
+
+func (v *VariantRepository) PutVariant(ctx context.Context, fileID uuid.UUID, image usecases.VariantImage) error {
+	const op = "VariantRepository.PutVariant"
+
+	path := v.variantPath(fileID, image.Name)
+	size, err := writeAtomic(v.root, path, bytes.NewReader(image.Content))
+	if err != nil {
+		v.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	variant := image.Variant
+	variant.Size = size
+	variant.ETag = newETag()
+	variant.LastModified = time.Now().UTC()
+	if err := writeSidecar(v.root, path+sidecarSuffix, variant); err != nil {
+		v.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (v *VariantRepository) StatVariant(ctx context.Context, fileID uuid.UUID, name string) (domain.Variant, error) {
+	const op = "VariantRepository.StatVariant"
+
+	var variant domain.Variant
+	if err := readSidecar(v.variantPath(fileID, name)+sidecarSuffix, &variant); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			v.logger.Error(op, slog.Any("error", err.Error()))
+		}
+		return domain.Variant{}, err
+	}
+	return variant, nil
+}
+
+func (v *VariantRepository) GetVariant(ctx context.Context, fileID uuid.UUID, name string, opts domain.ReadOptions) (io.ReadCloser, error) {
+	const op = "VariantRepository.GetVariant"
+
+	variant, err := v.StatVariant(ctx, fileID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := openContent(v.variantPath(fileID, name), variant.ETag, opts)
+	if err != nil && !errors.Is(err, domain.ErrModified) && !errors.Is(err, domain.ErrNotFound) {
+		v.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return content, err
+}
+
+func (v *VariantRepository) DeleteVariants(ctx context.Context, fileID uuid.UUID) error {
+	if err := os.RemoveAll(filepath.Join(v.root, variantsDir, fileID.String())); err != nil {
+		v.logger.Error("VariantRepository.DeleteVariants", slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (v *VariantRepository) variantPath(fileID uuid.UUID, name string) string {
+	return filepath.Join(v.root, variantsDir, fileID.String(), filepath.Base(name))
+}
+
+func NewVariantRepository(root string, logger *slog.Logger) *VariantRepository {
+	return &VariantRepository{
+		root:   root,
+		logger: logger,
+	}
+}
@@ -0,0 +1,51 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"sync"
+)
+
+var _ usecases.BlobRepositoryInterface = &BlobRepository{}
+
+// BlobRepository counts the references to the blobs of a FileRepository.
+type BlobRepository struct {
+	m     sync.Mutex
+	files *FileRepository
+	refs  map[string]int64
+}
+
+func (b *BlobRepository) AddReference(ctx context.Context, checksum string, fileID uuid.UUID) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.refs[checksum] == 0 {
+		if err := b.files.createBlob(checksum, fileID); err != nil {
+			return err
+		}
+	}
+	b.refs[checksum]++
+	return nil
+}
+
+func (b *BlobRepository) RemoveReference(ctx context.Context, checksum string) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.refs[checksum] > 1 {
+		b.refs[checksum]--
+		return nil
+	}
+	delete(b.refs, checksum)
+	b.files.removeBlob(checksum)
+	return nil
+}
+
+// NewBlobRepository creates a new BlobRepository for the blobs of files.
+func NewBlobRepository(files *FileRepository) *BlobRepository {
+	return &BlobRepository{
+		files: files,
+		refs:  make(map[string]int64),
+	}
+}
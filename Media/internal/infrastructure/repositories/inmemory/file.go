@@ -0,0 +1,195 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ usecases.FileRepositoryInterface = &FileRepository{}
+
+// storedFile is a file with its own content, or with the checksum of its blob.
+type storedFile struct {
+	file    domain.File
+	content []byte
+}
+
+// FileRepository keeps files and their blobs in memory.
+// Stored content is never changed, so readers share it without copies.
+type FileRepository struct {
+	m     sync.RWMutex
+	files map[uuid.UUID]storedFile
+	blobs map[string][]byte
+}
+
+func (f *FileRepository) CreateFile(ctx context.Context, file domain.File, content io.Reader) error {
+	if file.Size >= 0 {
+		content = io.LimitReader(content, file.Size)
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if file.Size >= 0 && int64(len(data)) != file.Size {
+		return io.ErrUnexpectedEOF
+	}
+
+	file.Size = int64(len(data))
+	file.Checksum = ""
+	f.put(file, data)
+	return nil
+}
+
+func (f *FileRepository) StatFile(ctx context.Context, id uuid.UUID) (domain.File, error) {
+	f.m.RLock()
+	defer f.m.RUnlock()
+
+	stored, ok := f.files[id]
+	if !ok {
+		return domain.File{}, domain.ErrNotFound
+	}
+	return stored.file, nil
+}
+
+func (f *FileRepository) GetFile(ctx context.Context, id uuid.UUID, opts domain.ReadOptions) (io.ReadCloser, error) {
+	f.m.RLock()
+	defer f.m.RUnlock()
+
+	stored, ok := f.files[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if opts.ETag != "" && opts.ETag != stored.file.ETag {
+		return nil, domain.ErrModified
+	}
+
+	content := stored.content
+	if stored.file.Checksum != "" {
+		content = f.blobs[stored.file.Checksum]
+	}
+	return readContent(content, opts.Range), nil
+}
+
+// UpdateFile replaces the name and the access control of a file.
+func (f *FileRepository) UpdateFile(ctx context.Context, file domain.File) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	stored, ok := f.files[file.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	stored.file.Name = file.Name
+	stored.file.AuthorID = file.AuthorID
+	stored.file.Access = file.Access
+	stored.file.Access.SharedWith = slices.Clone(file.Access.SharedWith)
+	if file.ContentType != "" {
+		stored.file.ContentType = file.ContentType
+	}
+	f.files[file.ID] = stored
+	return nil
+}
+
+// LinkFile drops the content of a file for the blob with the given checksum, which must exist.
+func (f *FileRepository) LinkFile(ctx context.Context, id uuid.UUID, checksum string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	stored, ok := f.files[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	blob, ok := f.blobs[checksum]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	stored.file.Checksum = checksum
+	stored.file.Size = int64(len(blob))
+	stored.file.ETag = checksum
+	stored.content = nil
+	f.files[id] = stored
+	return nil
+}
+
+func (f *FileRepository) DeleteFile(ctx context.Context, id uuid.UUID) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	delete(f.files, id)
+	return nil
+}
+
+func (f *FileRepository) GetFilesModifiedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	f.m.RLock()
+	defer f.m.RUnlock()
+
+	var ids []uuid.UUID
+	for id, stored := range f.files {
+		if stored.file.LastModified.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// put stores a new file with its content.
+func (f *FileRepository) put(file domain.File, content []byte) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if file.ContentType == "" {
+		file.ContentType = "application/octet-stream"
+	}
+	file.ETag = uuid.NewString()
+	file.LastModified = time.Now().UTC()
+	file.Access.SharedWith = slices.Clone(file.Access.SharedWith)
+	f.files[file.ID] = storedFile{file: file, content: content}
+}
+
+// createBlob shares the content of a file as the blob with the given checksum, unless the blob exists.
+func (f *FileRepository) createBlob(checksum string, id uuid.UUID) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if _, ok := f.blobs[checksum]; ok {
+		return nil
+	}
+	stored, ok := f.files[id]
+	if !ok || stored.file.Checksum != "" {
+		return domain.ErrNotFound
+	}
+	f.blobs[checksum] = stored.content
+	return nil
+}
+
+// removeBlob removes the blob with the given checksum.
+func (f *FileRepository) removeBlob(checksum string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	delete(f.blobs, checksum)
+}
+
+// readContent returns a reader of content, or of the part of it in a range.
+func readContent(content []byte, r *domain.ByteRange) io.ReadCloser {
+	if r != nil {
+		start := min(r.Start, int64(len(content)))
+		content = content[start:min(r.End+1, int64(len(content)))]
+	}
+	return io.NopCloser(bytes.NewReader(content))
+}
+
+func NewFileRepository() *FileRepository {
+	return &FileRepository{
+		files: make(map[uuid.UUID]storedFile),
+		blobs: make(map[string][]byte),
+	}
+}
@@ -0,0 +1,154 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	_ usecases.MetaRepositoryInterface      = &MetaRepository{}
+	_ usecases.ReferenceRepositoryInterface = &MetaRepository{}
+)
+
+// MetaRepository indexes the metadata of files and the references to them in memory.
+type MetaRepository struct {
+	m          sync.RWMutex
+	metas      map[uuid.UUID]domain.FileMeta
+	references map[uuid.UUID]map[string]domain.Reference // by file and owner
+	released   map[uuid.UUID]time.Time                   // when files lost their last reference
+}
+
+func (r *MetaRepository) SaveMeta(ctx context.Context, meta domain.FileMeta) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	meta.Access.SharedWith = slices.Clone(meta.Access.SharedWith)
+	r.metas[meta.ID] = meta
+	return nil
+}
+
+func (r *MetaRepository) GetMeta(ctx context.Context, id uuid.UUID) (domain.FileMeta, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	meta, ok := r.metas[id]
+	if !ok {
+		return domain.FileMeta{}, domain.ErrNotFound
+	}
+	return meta, nil
+}
+
+func (r *MetaRepository) ListMeta(ctx context.Context, query domain.MetaQuery) (domain.MetaPage, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	var items []domain.FileMeta
+	for _, meta := range r.metas {
+		if !query.Matches(meta) {
+			continue
+		}
+		if query.After != nil && !domain.CursorOf(meta).After(*query.After) {
+			continue
+		}
+		items = append(items, meta)
+	}
+
+	slices.SortFunc(items, func(a, b domain.FileMeta) int {
+		switch {
+		case domain.CursorOf(a).After(domain.CursorOf(b)):
+			return 1
+		case domain.CursorOf(b).After(domain.CursorOf(a)):
+			return -1
+		}
+		return 0
+	})
+
+	return domain.NewMetaPage(items[:min(len(items), query.Limit+1)], query.Limit), nil
+}
+
+func (r *MetaRepository) DeleteMeta(ctx context.Context, id uuid.UUID) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.metas[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.metas, id)
+	delete(r.references, id)
+	delete(r.released, id)
+	return nil
+}
+
+func (r *MetaRepository) AddReference(ctx context.Context, ref domain.Reference) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.metas[ref.FileID]; !ok {
+		return domain.ErrNotFound
+	}
+	if r.references[ref.FileID] == nil {
+		r.references[ref.FileID] = make(map[string]domain.Reference)
+	}
+	if _, ok := r.references[ref.FileID][ref.Owner]; !ok {
+		r.references[ref.FileID][ref.Owner] = ref
+	}
+	return nil
+}
+
+func (r *MetaRepository) RemoveReference(ctx context.Context, fileID uuid.UUID, owner string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.references[fileID][owner]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.references[fileID], owner)
+	if len(r.references[fileID]) == 0 {
+		delete(r.references, fileID)
+		r.released[fileID] = time.Now().UTC()
+	}
+	return nil
+}
+
+func (r *MetaRepository) GetUnreferenced(ctx context.Context, purpose domain.Purpose, before time.Time, limit int) ([]domain.FileMeta, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	var items []domain.FileMeta
+	for id, meta := range r.metas {
+		if meta.Purpose != purpose || len(r.references[id]) > 0 {
+			continue
+		}
+		if !r.unreferencedSince(meta).Before(before) {
+			continue
+		}
+		items = append(items, meta)
+	}
+
+	slices.SortFunc(items, func(a, b domain.FileMeta) int {
+		return r.unreferencedSince(a).Compare(r.unreferencedSince(b))
+	})
+	return items[:min(len(items), limit)], nil
+}
+
+// unreferencedSince returns when a file without references lost its last one, or its creation time if it never had any.
+func (r *MetaRepository) unreferencedSince(meta domain.FileMeta) time.Time {
+	if released, ok := r.released[meta.ID]; ok {
+		return released
+	}
+	return meta.CreatedAt
+}
+
+// NewMetaRepository creates a new MetaRepository.
+func NewMetaRepository() *MetaRepository {
+	return &MetaRepository{
+		metas:      make(map[uuid.UUID]domain.FileMeta),
+		references: make(map[uuid.UUID]map[string]domain.Reference),
+		released:   make(map[uuid.UUID]time.Time),
+	}
+}
@@ -0,0 +1,19 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/infrastructure/repositories/conformance"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Backend {
+		files := NewFileRepository()
+		return conformance.Backend{
+			Files:    files,
+			Blobs:    NewBlobRepository(files),
+			Uploads:  NewUploadRepository(files),
+			Usage:    NewUsageRepository(),
+			Variants: NewVariantRepository(),
+		}
+	})
+}
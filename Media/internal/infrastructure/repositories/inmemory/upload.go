@@ -0,0 +1,116 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ usecases.UploadRepositoryInterface = &UploadRepository{}
+
+// pendingUpload is an upload with its received content.
+type pendingUpload struct {
+	upload  domain.Upload
+	content []byte
+}
+
+// UploadRepository keeps resumable uploads in memory, and creates the files of done uploads in a FileRepository.
+type UploadRepository struct {
+	m       sync.Mutex
+	files   *FileRepository
+	uploads map[uuid.UUID]*pendingUpload
+}
+
+func (u *UploadRepository) CreateUpload(ctx context.Context, upload *domain.Upload) error {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.uploads[upload.ID] = &pendingUpload{upload: *upload}
+	return nil
+}
+
+func (u *UploadRepository) GetUpload(ctx context.Context, id uuid.UUID) (*domain.Upload, error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	pending, ok := u.uploads[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	upload := pending.upload
+	return &upload, nil
+}
+
+func (u *UploadRepository) AppendUpload(ctx context.Context, upload *domain.Upload, content io.Reader) error {
+	// The content is read before locking, so slow clients do not hold up other uploads
+	data, readErr := io.ReadAll(content)
+
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	pending, ok := u.uploads[upload.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	pending.content = append(pending.content[:upload.Offset:upload.Offset], data...)
+	upload.Offset += int64(len(data))
+	pending.upload = *upload
+	return readErr
+}
+
+func (u *UploadRepository) CompleteUpload(ctx context.Context, upload *domain.Upload) error {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	pending, ok := u.uploads[upload.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+
+	u.files.put(domain.File{
+		ID:          upload.ID,
+		AuthorID:    upload.AuthorID,
+		Name:        upload.Name,
+		Size:        upload.Size,
+		ContentType: upload.ContentType,
+		Access:      upload.Access,
+	}, slices.Clip(pending.content))
+	delete(u.uploads, upload.ID)
+	return nil
+}
+
+func (u *UploadRepository) DeleteUpload(ctx context.Context, upload *domain.Upload) error {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	delete(u.uploads, upload.ID)
+	return nil
+}
+
+func (u *UploadRepository) GetExpiredUploads(ctx context.Context, now time.Time) ([]*domain.Upload, error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	var expired []*domain.Upload
+	for _, pending := range u.uploads {
+		if pending.upload.Expired(now) {
+			upload := pending.upload
+			expired = append(expired, &upload)
+		}
+	}
+	return expired, nil
+}
+
+// NewUploadRepository creates a new UploadRepository creating the files of done uploads in files.
+func NewUploadRepository(files *FileRepository) *UploadRepository {
+	return &UploadRepository{
+		files:   files,
+		uploads: make(map[uuid.UUID]*pendingUpload),
+	}
+}
@@ -0,0 +1,37 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"sync"
+)
+
+var _ usecases.UsageRepositoryInterface = &UsageRepository{}
+
+// UsageRepository keeps the number of bytes stored by each author in memory.
+type UsageRepository struct {
+	m     sync.Mutex
+	usage map[uuid.UUID]int64
+}
+
+func (u *UsageRepository) GetUsage(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	return u.usage[authorID], nil
+}
+
+func (u *UsageRepository) AddUsage(ctx context.Context, authorID uuid.UUID, delta int64) error {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.usage[authorID] = max(u.usage[authorID]+delta, 0)
+	return nil
+}
+
+func NewUsageRepository() *UsageRepository {
+	return &UsageRepository{
+		usage: make(map[uuid.UUID]int64),
+	}
+}
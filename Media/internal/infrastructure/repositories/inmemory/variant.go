@@ -0,0 +1,74 @@
+package inmemoryRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"sync"
+	"time"
+)
+
+var _ usecases.VariantRepositoryInterface = &VariantRepository{}
+
+// VariantRepository keeps the variants of images in memory.
+type VariantRepository struct {
+	m        sync.RWMutex
+	variants map[uuid.UUID]map[string]usecases.VariantImage
+}
+
+func (v *VariantRepository) PutVariant(ctx context.Context, fileID uuid.UUID, image usecases.VariantImage) error {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	image.Size = int64(len(image.Content))
+	image.ETag = uuid.NewString()
+	image.LastModified = time.Now().UTC()
+
+	if v.variants[fileID] == nil {
+		v.variants[fileID] = make(map[string]usecases.VariantImage)
+	}
+	v.variants[fileID][image.Name] = image
+	return nil
+}
+
+func (v *VariantRepository) StatVariant(ctx context.Context, fileID uuid.UUID, name string) (domain.Variant, error) {
+	v.m.RLock()
+	defer v.m.RUnlock()
+
+	image, ok := v.variants[fileID][name]
+	if !ok {
+		return domain.Variant{}, domain.ErrNotFound
+	}
+	return image.Variant, nil
+}
+
+func (v *VariantRepository) GetVariant(ctx context.Context, fileID uuid.UUID, name string, opts domain.ReadOptions) (io.ReadCloser, error) {
+	v.m.RLock()
+	defer v.m.RUnlock()
+
+	image, ok := v.variants[fileID][name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if opts.ETag != "" && opts.ETag != image.ETag {
+		return nil, domain.ErrModified
+	}
+
+	return readContent(image.Content, opts.Range), nil
+}
+
+func (v *VariantRepository) DeleteVariants(ctx context.Context, fileID uuid.UUID) error {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	delete(v.variants, fileID)
+	return nil
+}
+
+func NewVariantRepository() *VariantRepository {
+	return &VariantRepository{
+		variants: make(map[uuid.UUID]map[string]usecases.VariantImage),
+	}
+}
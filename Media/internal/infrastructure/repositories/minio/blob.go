@@ -0,0 +1,195 @@
+package minioRepo
+
+import (
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"log/slog"
+	"sync"
+)
+
+// Prefixes of the objects with the content of files and the number of files referencing it.
+const (
+	BlobsPrefix = "blobs/"
+	RefsPrefix  = "refs/"
+)
+
+var _ usecases.BlobRepositoryInterface = &BlobRepository{}
+
+// RefCounter keeps the number of files referencing each blob.
+type RefCounter interface {
+	// UpdateRefs calls update with the number of files referencing a blob, 0 if it has none,
+	// and saves the number update returns unless it fails.
+	// Updates of a blob are serialized until update returns, so update can create or remove the blob.
+	UpdateRefs(ctx context.Context, checksum string, update func(refs int64) (int64, error)) error
+}
+
+// BlobRepository stores the content of files once per checksum, under the checksum.
+//
+// The number of files referencing a blob is kept by a RefCounter, like the index of files in Postgres,
+// so instances of the service sharing the bucket update it atomically. Without one, it is kept in a small
+// object next to the blob and updates are serialized within the process only.
+// Blobs are created and removed while their number is locked,
+// so a blob is never removed while a file with the same content is being linked to it.
+type BlobRepository struct {
+	bucketName string
+	client     *minio.Client
+	refs       RefCounter
+	logger     *slog.Logger
+}
+
+func (b *BlobRepository) AddReference(ctx context.Context, checksum string, fileID uuid.UUID) error {
+	return b.refs.UpdateRefs(ctx, checksum, func(refs int64) (int64, error) {
+		if refs == 0 {
+			if err := b.createBlob(ctx, checksum, fileID); err != nil {
+				return 0, err
+			}
+		}
+		return refs + 1, nil
+	})
+}
+
+func (b *BlobRepository) RemoveReference(ctx context.Context, checksum string) error {
+	const op = "BlobRepository.RemoveReference"
+
+	return b.refs.UpdateRefs(ctx, checksum, func(refs int64) (int64, error) {
+		if refs > 1 {
+			return refs - 1, nil
+		}
+		if err := b.client.RemoveObject(ctx, b.bucketName, blobKey(checksum), minio.RemoveObjectOptions{}); err != nil {
+			b.logger.Error(op, slog.Any("error", err.Error()))
+			return 0, err
+		}
+		return 0, nil
+	})
+}
+
+// createBlob copies the content of a file to the blob with its checksum on the server side, unless the blob exists.
+// Blobs may exist without references when removing them was interrupted.
+func (b *BlobRepository) createBlob(ctx context.Context, checksum string, fileID uuid.UUID) error {
+	const op = "BlobRepository.createBlob"
+
+	_, err := b.client.StatObject(ctx, b.bucketName, blobKey(checksum), minio.StatObjectOptions{})
+	if err == nil {
+		return nil
+	}
+	if minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	// Blobs are shared, so they do not keep the metadata of the file they are created from
+	_, err = b.client.ComposeObject(ctx,
+		minio.CopyDestOptions{
+			Bucket:          b.bucketName,
+			Object:          blobKey(checksum),
+			ReplaceMetadata: true,
+			UserMetadata:    map[string]string{"Content-Type": "application/octet-stream"},
+		},
+		minio.CopySrcOptions{
+			Bucket: b.bucketName,
+			Object: fileID.String(),
+		},
+	)
+	if err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return toDomainError(err)
+	}
+	return nil
+}
+
+func blobKey(checksum string) string {
+	return BlobsPrefix + checksum
+}
+
+func refsKey(checksum string) string {
+	return RefsPrefix + checksum
+}
+
+// objectRefCounter keeps the number of files referencing each blob as text in a small object.
+// Updates are serialized within the process only, like the usage of authors.
+type objectRefCounter struct {
+	bucketName string
+	client     *minio.Client
+	mu         sync.Mutex
+	logger     *slog.Logger
+}
+
+func (c *objectRefCounter) UpdateRefs(ctx context.Context, checksum string, update func(refs int64) (int64, error)) error {
+	const op = "objectRefCounter.UpdateRefs"
+	logger := c.logger.With("op", op)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refs, err := readCounter(ctx, c.client, c.bucketName, refsKey(checksum), logger)
+	if err != nil {
+		return err
+	}
+
+	refs, err = update(refs)
+	if err != nil {
+		return err
+	}
+
+	if refs > 0 {
+		return writeCounter(ctx, c.client, c.bucketName, refsKey(checksum), refs, logger)
+	}
+	if err := c.client.RemoveObject(ctx, c.bucketName, refsKey(checksum), minio.RemoveObjectOptions{}); err != nil {
+		logger.Error("error while removing counter", slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// seededRefCounter takes over the numbers an objectRefCounter kept before, when their blobs are first updated.
+type seededRefCounter struct {
+	RefCounter
+	bucketName string
+	client     *minio.Client
+	logger     *slog.Logger
+}
+
+func (c *seededRefCounter) UpdateRefs(ctx context.Context, checksum string, update func(refs int64) (int64, error)) error {
+	const op = "seededRefCounter.UpdateRefs"
+	logger := c.logger.With("op", op)
+
+	return c.RefCounter.UpdateRefs(ctx, checksum, func(refs int64) (int64, error) {
+		if refs > 0 {
+			return update(refs)
+		}
+
+		seed, err := readCounter(ctx, c.client, c.bucketName, refsKey(checksum), logger)
+		if err != nil {
+			return 0, err
+		}
+		refs, err = update(seed)
+		if err != nil || seed == 0 {
+			return refs, err
+		}
+
+		// The number is not taken over twice
+		if err := c.client.RemoveObject(ctx, c.bucketName, refsKey(checksum), minio.RemoveObjectOptions{}); err != nil {
+			logger.Error("error while removing counter", slog.Any("error", err.Error()))
+			return 0, err
+		}
+		return refs, nil
+	})
+}
+
+// NewBlobRepository creates a new BlobRepository, the numbers of references are kept in the bucket if refs is nil.
+func NewBlobRepository(client *minio.Client, bucketName string, refs RefCounter, logger *slog.Logger) *BlobRepository {
+	if refs == nil {
+		refs = &objectRefCounter{bucketName: bucketName, client: client, logger: logger}
+	} else {
+		refs = &seededRefCounter{RefCounter: refs, bucketName: bucketName, client: client, logger: logger}
+	}
+
+	return &BlobRepository{
+		bucketName: bucketName,
+		client:     client,
+		refs:       refs,
+		logger:     logger,
+	}
+}
@@ -0,0 +1,48 @@
+package minioRepo
+
+import (
+	"context"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// readCounter returns the number kept as text in a small object, 0 if the object does not exist.
+func readCounter(ctx context.Context, client *minio.Client, bucketName string, key string, logger *slog.Logger) (int64, error) {
+	object, err := client.GetObject(ctx, bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		logger.Error("error while getting counter", slog.Any("error", err.Error()))
+		return 0, err
+	}
+	defer func(object *minio.Object) {
+		if err := object.Close(); err != nil {
+			logger.Error("error while closing counter", slog.Any("error", err.Error()))
+		}
+	}(object)
+
+	content, err := io.ReadAll(object)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return 0, nil
+		}
+		logger.Error("error while reading counter", slog.Any("error", err.Error()))
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(content), 10, 64)
+}
+
+// writeCounter replaces the number kept in a small object.
+func writeCounter(ctx context.Context, client *minio.Client, bucketName string, key string, value int64, logger *slog.Logger) error {
+	content := strconv.FormatInt(value, 10)
+	_, err := client.PutObject(ctx, bucketName, key, strings.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		logger.Error("error while writing counter", slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
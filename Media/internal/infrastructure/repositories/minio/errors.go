@@ -0,0 +1,18 @@
+package minioRepo
+
+import (
+	"Media/internal/domain"
+	"github.com/minio/minio-go/v7"
+)
+
+// toDomainError returns the domain error of the MinIO errors clients are told about, and err otherwise.
+// Missing objects are not found, and objects that changed since they were checked are modified.
+func toDomainError(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchUpload":
+		return domain.ErrNotFound
+	case "PreconditionFailed":
+		return domain.ErrModified
+	}
+	return err
+}
@@ -3,37 +3,51 @@ package minioRepo
 import (
 	"Media/internal/domain"
 	"Media/internal/usecases"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
+	"io"
 	"log/slog"
 	"strings"
+	"time"
 	"unicode"
 )
 
 const (
-	NameKey     = "Name"
-	AuthorIDKey = "Authorid"
+	NameKey       = "Name"
+	AuthorIDKey   = "Authorid"
+	VisibilityKey = "Visibility"
+	SharedWithKey = "Sharedwith"
+	ChecksumKey   = "Checksum"
 )
 
+// PartSize is the size of the parts of multipart uploads.
+// Uploads of unknown size buffer one part in memory, and are limited to 10000 parts.
+const PartSize = 16 << 20
+
 var _ usecases.FileRepositoryInterface = &FileRepository{}
 
+// FileRepository stores each file as an object with its ID as the key and its metadata as user metadata.
+//
+// Files linked to a blob keep an empty object with the checksum of the blob,
+// files stored before blobs keep their content in their object.
 type FileRepository struct {
 	bucketName string
 	client     *minio.Client
 	logger     *slog.Logger
 }
 
-func (f *FileRepository) CreateFile(ctx context.Context, file domain.File) error {
-	_, err := f.client.PutObject(ctx, f.bucketName, file.ID.String(), strings.NewReader(string(file.Content)), int64(len(file.Content)), minio.PutObjectOptions{
-		ContentType: "application/octet-stream",
-		UserMetadata: map[string]string{
-			NameKey:     cleanName(file.Name),
-			AuthorIDKey: file.AuthorID.String(),
-		},
+func (f *FileRepository) CreateFile(ctx context.Context, file domain.File, content io.Reader) error {
+	_, err := f.client.PutObject(ctx, f.bucketName, file.ID.String(), content, file.Size, minio.PutObjectOptions{
+		ContentType:  contentType(file.ContentType),
+		PartSize:     PartSize,
+		UserMetadata: fileMetadata(file.Name, file.AuthorID, file.Access),
 	})
 	if err != nil {
 		f.logger.Error("FileRepository.CreateFile", slog.Any("error", err.Error()))
@@ -42,39 +56,23 @@ func (f *FileRepository) CreateFile(ctx context.Context, file domain.File) error
 	return nil
 }
 
-func (f *FileRepository) GetFile(ctx context.Context, id uuid.UUID) (domain.File, error) {
-	const op = "FileRepository.GetFile"
+func (f *FileRepository) StatFile(ctx context.Context, id uuid.UUID) (domain.File, error) {
+	const op = "FileRepository.StatFile"
 	logger := f.logger.With("op", op)
 
-	object, err := f.client.GetObject(ctx, f.bucketName, id.String(), minio.GetObjectOptions{})
+	info, err := f.client.StatObject(ctx, f.bucketName, id.String(), minio.StatObjectOptions{})
 	if err != nil {
-		logger.Error("error while getting object", slog.Any("error", err.Error()))
-		return domain.File{}, err
-	}
-	defer func(object *minio.Object) {
-		err := object.Close()
-		if err != nil {
-			logger.Error("error while closing object", slog.Any("error", err.Error()))
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return domain.File{}, domain.ErrNotFound
 		}
-	}(object)
-
-	info, err := object.Stat()
-	if err != nil {
 		logger.Error("error while getting object info", slog.Any("error", err.Error()))
 		return domain.File{}, err
 	}
 
-	content := make([]byte, info.Size)
-	_, err = object.Read(content)
-	if err != nil && err.Error() != "EOF" {
-		logger.Error("error while reading object", slog.Any("error", err.Error()))
-		return domain.File{}, err
-	}
-
 	rawAuthorID := info.UserMetadata[AuthorIDKey]
 	if rawAuthorID == "" {
 		logger.Error("AuthorID not found")
-		return domain.File{}, err
+		return domain.File{}, fmt.Errorf("%s: author id not found in metadata of %s", op, id)
 	}
 
 	authorID, err := uuid.Parse(rawAuthorID)
@@ -83,13 +81,116 @@ func (f *FileRepository) GetFile(ctx context.Context, id uuid.UUID) (domain.File
 		return domain.File{}, err
 	}
 
-	return domain.File{
-		ID:       id,
-		AuthorID: authorID,
-		Name:     info.UserMetadata[NameKey],
-		Size:     info.Size,
-		Content:  content,
-	}, nil
+	file := domain.File{
+		ID:           id,
+		AuthorID:     authorID,
+		Name:         info.UserMetadata[NameKey],
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+		Access:       parseAccess(info.UserMetadata),
+		Checksum:     info.UserMetadata[ChecksumKey],
+	}
+
+	// The content of linked files is described by their blob
+	if file.Checksum != "" {
+		blob, err := f.client.StatObject(ctx, f.bucketName, blobKey(file.Checksum), minio.StatObjectOptions{})
+		if err != nil {
+			// A linked file without its blob is broken, so a missing blob is logged too
+			logger.Error("error while getting blob info", slog.Any("error", err.Error()))
+			return domain.File{}, toDomainError(err)
+		}
+		file.Size = blob.Size
+		file.ETag = blob.ETag
+	}
+
+	return file, nil
+}
+
+// UpdateFile replaces the name and the access control of a file.
+func (f *FileRepository) UpdateFile(ctx context.Context, file domain.File) error {
+	const op = "FileRepository.UpdateFile"
+
+	metadata := fileMetadata(file.Name, file.AuthorID, file.Access)
+	if file.ContentType != "" {
+		metadata["Content-Type"] = file.ContentType
+	}
+	if file.Checksum != "" {
+		metadata[ChecksumKey] = file.Checksum
+	}
+
+	// Metadata of objects cannot be changed, so the object is copied onto itself on the server
+	_, err := f.client.ComposeObject(ctx,
+		minio.CopyDestOptions{
+			Bucket:          f.bucketName,
+			Object:          file.ID.String(),
+			ReplaceMetadata: true,
+			UserMetadata:    metadata,
+		},
+		minio.CopySrcOptions{
+			Bucket: f.bucketName,
+			Object: file.ID.String(),
+		},
+	)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return domain.ErrNotFound
+		}
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (f *FileRepository) GetFile(ctx context.Context, id uuid.UUID, opts domain.ReadOptions) (io.ReadCloser, error) {
+	const op = "FileRepository.GetFile"
+	logger := f.logger.With("op", op)
+
+	info, err := f.client.StatObject(ctx, f.bucketName, id.String(), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, domain.ErrNotFound
+		}
+		logger.Error("error while getting object info", slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	key := id.String()
+	if checksum := info.UserMetadata[ChecksumKey]; checksum != "" {
+		key = blobKey(checksum)
+	}
+	return getObject(ctx, f.client, f.bucketName, key, opts, logger)
+}
+
+// LinkFile replaces the object of a file with an empty one with the checksum of its blob and the same metadata.
+func (f *FileRepository) LinkFile(ctx context.Context, id uuid.UUID, checksum string) error {
+	const op = "FileRepository.LinkFile"
+
+	info, err := f.client.StatObject(ctx, f.bucketName, id.String(), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return domain.ErrNotFound
+		}
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	metadata := make(map[string]string, len(info.UserMetadata)+1)
+	for key, value := range info.UserMetadata {
+		metadata[key] = value
+	}
+	metadata[ChecksumKey] = checksum
+
+	_, err = f.client.PutObject(ctx, f.bucketName, id.String(), bytes.NewReader(nil), 0, minio.PutObjectOptions{
+		ContentType:  contentType(info.ContentType),
+		UserMetadata: metadata,
+	})
+	if err != nil {
+		f.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
 }
 
 func (f *FileRepository) DeleteFile(ctx context.Context, id uuid.UUID) error {
@@ -101,6 +202,28 @@ func (f *FileRepository) DeleteFile(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// GetFilesModifiedBefore lists the objects at the root of the bucket, the other objects are under prefixes.
+func (f *FileRepository) GetFilesModifiedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	const op = "FileRepository.GetFilesModifiedBefore"
+
+	var ids []uuid.UUID
+	for object := range f.client.ListObjects(ctx, f.bucketName, minio.ListObjectsOptions{}) {
+		if object.Err != nil {
+			f.logger.Error(op, slog.Any("error", object.Err.Error()))
+			return nil, object.Err
+		}
+
+		id, err := uuid.Parse(object.Key)
+		if err != nil {
+			continue
+		}
+		if object.LastModified.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 func NewFileRepository(client *minio.Client, bucketName string, logger *slog.Logger) *FileRepository {
 	return &FileRepository{
 		bucketName: bucketName,
@@ -109,9 +232,89 @@ func NewFileRepository(client *minio.Client, bucketName string, logger *slog.Log
 	}
 }
 
+// contentType returns the content type objects are stored with, application/octet-stream if it is not known.
+func contentType(contentType string) string {
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
+
+// fileMetadata returns the user metadata of the object of a file.
+func fileMetadata(name string, authorID uuid.UUID, access domain.Access) map[string]string {
+	metadata := map[string]string{
+		NameKey:       cleanName(name),
+		AuthorIDKey:   authorID.String(),
+		VisibilityKey: string(access.Visibility),
+	}
+
+	if len(access.SharedWith) > 0 {
+		ids := make([]string, 0, len(access.SharedWith))
+		for _, id := range access.SharedWith {
+			ids = append(ids, id.String())
+		}
+		metadata[SharedWithKey] = strings.Join(ids, ",")
+	}
+
+	return metadata
+}
+
+// parseAccess reads the access control of a file from the user metadata of its object.
+// Objects stored before files had access control have no visibility, and stay public.
+func parseAccess(metadata map[string]string) domain.Access {
+	access := domain.Access{Visibility: domain.Visibility(metadata[VisibilityKey])}
+	if access.Visibility == "" {
+		access.Visibility = domain.VisibilityPublic
+	}
+
+	for _, rawID := range strings.Split(metadata[SharedWithKey], ",") {
+		if id, err := uuid.Parse(rawID); err == nil {
+			access.SharedWith = append(access.SharedWith, id)
+		}
+	}
+
+	return access
+}
+
 func cleanName(name string) string {
 	name = strings.ReplaceAll(name, " ", "_")
 	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
 	result, _, _ := transform.String(t, name)
 	return result
 }
+
+// getObject returns the content of an object, or a part of it, if its ETag matches.
+func getObject(ctx context.Context, client *minio.Client, bucketName string, key string, opts domain.ReadOptions, logger *slog.Logger) (io.ReadCloser, error) {
+	getOpts := minio.GetObjectOptions{}
+	if opts.Range != nil {
+		if err := getOpts.SetRange(opts.Range.Start, opts.Range.End); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ETag != "" {
+		if err := getOpts.SetMatchETag(opts.ETag); err != nil {
+			return nil, err
+		}
+	}
+
+	object, err := client.GetObject(ctx, bucketName, key, getOpts)
+	if err != nil {
+		logger.Error("error while getting object", slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	// The object is requested lazily, Stat sends the request to report errors before the content is read
+	if _, err := object.Stat(); err != nil {
+		if closeErr := object.Close(); closeErr != nil {
+			logger.Error("error while closing object", slog.Any("error", closeErr.Error()))
+		}
+
+		if err := toDomainError(err); errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrModified) {
+			return nil, err
+		}
+		logger.Error("error while getting object info", slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	return object, nil
+}
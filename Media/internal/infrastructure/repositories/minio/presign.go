@@ -0,0 +1,258 @@
+package minioRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"log/slog"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PresignedPrefix is the prefix of the objects of presigned uploads.
+const PresignedPrefix = "presigned/"
+
+var _ usecases.PresignRepositoryInterface = &PresignRepository{}
+
+// PresignRepository issues presigned URLs for MinIO.
+//
+// Content is uploaded to a staging object with the state of the upload next to it,
+// and is copied to the file on the server side when the upload is finalized.
+type PresignRepository struct {
+	bucketName string
+	client     *minio.Client
+	signer     *minio.Client // client with the endpoint used by clients of the service
+	logger     *slog.Logger
+}
+
+func (p *PresignRepository) PresignUpload(ctx context.Context, upload *domain.PresignedUpload, ttl time.Duration) (string, error) {
+	const op = "PresignRepository.PresignUpload"
+
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = p.client.PutObject(ctx, p.bucketName, presignedInfoKey(upload.ID), bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: "application/json",
+	})
+	if err != nil {
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return "", err
+	}
+
+	u, err := p.signer.PresignedPutObject(ctx, p.bucketName, presignedKey(upload.ID), ttl)
+	if err != nil {
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+func (p *PresignRepository) GetPresignedUpload(ctx context.Context, id uuid.UUID) (*domain.PresignedUpload, error) {
+	const op = "PresignRepository.GetPresignedUpload"
+
+	object, err := p.client.GetObject(ctx, p.bucketName, presignedInfoKey(id), minio.GetObjectOptions{})
+	if err != nil {
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+	defer func(object *minio.Object) {
+		if err := object.Close(); err != nil {
+			p.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}(object)
+
+	var upload domain.PresignedUpload
+	if err := json.NewDecoder(object).Decode(&upload); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, domain.ErrNotFound
+		}
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+func (p *PresignRepository) StatPresignedUpload(ctx context.Context, upload *domain.PresignedUpload) (int64, string, error) {
+	const op = "PresignRepository.StatPresignedUpload"
+
+	info, err := p.client.StatObject(ctx, p.bucketName, presignedKey(upload.ID), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return 0, "", domain.ErrNotFound
+		}
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return 0, "", err
+	}
+
+	contentType, _, err := mime.ParseMediaType(info.ContentType)
+	if err != nil {
+		contentType = info.ContentType
+	}
+
+	return info.Size, contentType, nil
+}
+
+func (p *PresignRepository) ReadPresignedUpload(ctx context.Context, upload *domain.PresignedUpload, length int64) ([]byte, error) {
+	const op = "PresignRepository.ReadPresignedUpload"
+
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(0, length-1); err != nil {
+		return nil, err
+	}
+
+	object, err := p.client.GetObject(ctx, p.bucketName, presignedKey(upload.ID), opts)
+	if err != nil {
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+	defer func(object *minio.Object) {
+		if err := object.Close(); err != nil {
+			p.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}(object)
+
+	content, err := io.ReadAll(object)
+	if err != nil {
+		switch minio.ToErrorResponse(err).Code {
+		case "NoSuchKey":
+			return nil, domain.ErrNotFound
+		case "InvalidRange":
+			// Empty content has no bytes to read
+			return nil, nil
+		}
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	return content, nil
+}
+
+func (p *PresignRepository) FinalizeUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	const op = "PresignRepository.FinalizeUpload"
+
+	metadata := fileMetadata(upload.Name, upload.AuthorID, upload.Access)
+	metadata["Content-Type"] = upload.ContentType
+
+	_, err := p.client.ComposeObject(ctx,
+		minio.CopyDestOptions{
+			Bucket:          p.bucketName,
+			Object:          upload.ID.String(),
+			ReplaceMetadata: true,
+			UserMetadata:    metadata,
+		},
+		minio.CopySrcOptions{
+			Bucket: p.bucketName,
+			Object: presignedKey(upload.ID),
+		},
+	)
+	if err != nil {
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return toDomainError(err)
+	}
+
+	return p.remove(ctx, upload.ID)
+}
+
+func (p *PresignRepository) DeletePresignedUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	return p.remove(ctx, upload.ID)
+}
+
+func (p *PresignRepository) GetExpiredPresignedUploads(ctx context.Context, now time.Time) ([]*domain.PresignedUpload, error) {
+	const op = "PresignRepository.GetExpiredPresignedUploads"
+
+	var expired []*domain.PresignedUpload
+	for object := range p.client.ListObjects(ctx, p.bucketName, minio.ListObjectsOptions{Prefix: PresignedPrefix}) {
+		if object.Err != nil {
+			p.logger.Error(op, slog.Any("error", object.Err.Error()))
+			return nil, object.Err
+		}
+		if !strings.HasSuffix(object.Key, infoSuffix) {
+			continue
+		}
+
+		id, err := uuid.Parse(strings.TrimSuffix(strings.TrimPrefix(object.Key, PresignedPrefix), infoSuffix))
+		if err != nil {
+			continue
+		}
+
+		upload, err := p.GetPresignedUpload(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Finalized while listing
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if upload.Expired(now) {
+			expired = append(expired, upload)
+		}
+	}
+
+	return expired, nil
+}
+
+func (p *PresignRepository) PresignDownload(ctx context.Context, file domain.File, ttl time.Duration) (string, error) {
+	const op = "PresignRepository.PresignDownload"
+
+	params := url.Values{}
+	params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+
+	// Blobs are shared by files of any type, so the type of the file is sent with the content
+	key := file.ID.String()
+	if file.Checksum != "" {
+		key = blobKey(file.Checksum)
+		params.Set("response-content-type", contentType(file.ContentType))
+	}
+
+	u, err := p.signer.PresignedGetObject(ctx, p.bucketName, key, ttl, params)
+	if err != nil {
+		p.logger.Error(op, slog.Any("error", err.Error()))
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// remove removes the state and the content of a presigned upload.
+func (p *PresignRepository) remove(ctx context.Context, id uuid.UUID) error {
+	const op = "PresignRepository.remove"
+
+	for _, key := range []string{presignedKey(id), presignedInfoKey(id)} {
+		if err := p.client.RemoveObject(ctx, p.bucketName, key, minio.RemoveObjectOptions{}); err != nil {
+			p.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+func presignedKey(id uuid.UUID) string {
+	return PresignedPrefix + id.String()
+}
+
+func presignedInfoKey(id uuid.UUID) string {
+	return PresignedPrefix + id.String() + infoSuffix
+}
+
+// NewPresignRepository creates a new PresignRepository.
+// URLs are signed with signer, so they point to the endpoint it is configured with.
+func NewPresignRepository(client *minio.Client, signer *minio.Client, bucketName string, logger *slog.Logger) *PresignRepository {
+	return &PresignRepository{
+		bucketName: bucketName,
+		client:     client,
+		signer:     signer,
+		logger:     logger,
+	}
+}
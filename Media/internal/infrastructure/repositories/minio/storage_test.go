@@ -0,0 +1,85 @@
+package minioRepo
+
+import (
+	"Media/internal/infrastructure/repositories/conformance"
+	"context"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+)
+
+// TestConformance runs against the MinIO server of MINIO_TEST_ENDPOINT, with the credentials of
+// MINIO_TEST_ACCESS_KEY and MINIO_TEST_SECRET_KEY, in the bucket of MINIO_TEST_BUCKET.
+// Objects get new IDs in each run, so the bucket can be shared.
+func TestConformance(t *testing.T) {
+	endpoint := os.Getenv("MINIO_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MINIO_TEST_ENDPOINT is not set")
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(os.Getenv("MINIO_TEST_ACCESS_KEY"), os.Getenv("MINIO_TEST_SECRET_KEY"), ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := os.Getenv("MINIO_TEST_BUCKET")
+	if bucket == "" {
+		bucket = "media-test"
+	}
+	if exists, err := client.BucketExists(context.Background(), bucket); err != nil {
+		t.Fatal(err)
+	} else if !exists {
+		if err := client.MakeBucket(context.Background(), bucket, minio.MakeBucketOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, refs := range []struct {
+		name    string
+		counter func() RefCounter
+	}{
+		{name: "objects", counter: func() RefCounter { return nil }},
+		{name: "counter", counter: func() RefCounter { return &mapRefCounter{refs: map[string]int64{}} }},
+	} {
+		t.Run(refs.name, func(t *testing.T) {
+			runConformance(t, client, bucket, refs.counter, logger)
+		})
+	}
+}
+
+// mapRefCounter is a RefCounter of the numbers in a map.
+type mapRefCounter struct {
+	mu   sync.Mutex
+	refs map[string]int64
+}
+
+func (c *mapRefCounter) UpdateRefs(ctx context.Context, checksum string, update func(refs int64) (int64, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refs, err := update(c.refs[checksum])
+	if err != nil {
+		return err
+	}
+	c.refs[checksum] = refs
+	return nil
+}
+
+func runConformance(t *testing.T, client *minio.Client, bucket string, refs func() RefCounter, logger *slog.Logger) {
+	conformance.Run(t, func(t *testing.T) conformance.Backend {
+		return conformance.Backend{
+			Files:    NewFileRepository(client, bucket, logger),
+			Blobs:    NewBlobRepository(client, bucket, refs(), logger),
+			Uploads:  NewUploadRepository(client, bucket, logger),
+			Usage:    NewUsageRepository(client, bucket, logger),
+			Variants: NewVariantRepository(client, bucket, logger),
+		}
+	})
+}
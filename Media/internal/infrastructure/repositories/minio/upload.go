@@ -0,0 +1,458 @@
+package minioRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadsPrefix is the prefix of the objects with the state of uploads.
+const UploadsPrefix = "uploads/"
+
+const (
+	infoSuffix    = ".info"
+	pendingSuffix = ".part"
+)
+
+// writeLease is how long a request may go without saving the state of the upload it writes
+// before other requests can take the upload over.
+const writeLease = 5 * time.Minute
+
+var _ usecases.UploadRepositoryInterface = &UploadRepository{}
+
+// UploadRepository stores resumable uploads as MinIO multipart uploads.
+//
+// The state of an upload is saved as a JSON object next to it, so uploads survive restarts.
+// The multipart upload starts with the first part, once the content type of the upload is known.
+// Parts must be at least 5 MiB, so received content that does not fill a part yet is kept
+// in pending objects, one per request, until more content arrives.
+//
+// A request writing an upload holds a lease on it in its state, and saves the state only if nobody
+// else did in between, so requests of several replicas cannot overwrite the parts of each other.
+type UploadRepository struct {
+	bucketName string
+	core       minio.Core
+	logger     *slog.Logger
+}
+
+// uploadState is the saved state of an upload.
+type uploadState struct {
+	domain.Upload
+	PendingObjects int       `json:"pendingObjects"` // number of objects with the PendingSize bytes not stored in parts yet
+	LockedUntil    time.Time `json:"lockedUntil"`    // end of the lease of the request writing the upload
+}
+
+func (u *UploadRepository) CreateUpload(ctx context.Context, upload *domain.Upload) error {
+	_, err := u.save(ctx, &uploadState{Upload: *upload}, "")
+	return err
+}
+
+func (u *UploadRepository) GetUpload(ctx context.Context, id uuid.UUID) (*domain.Upload, error) {
+	state, _, err := u.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &state.Upload, nil
+}
+
+// AppendUpload stores content at the end of an upload.
+// It fails with domain.ErrUploadLocked while another request writes the upload, and with domain.ErrWrongOffset
+// if the upload received content since it was read.
+func (u *UploadRepository) AppendUpload(ctx context.Context, upload *domain.Upload, content io.Reader) error {
+	const op = "UploadRepository.AppendUpload"
+
+	state, etag, err := u.lock(ctx, upload)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The lease is released with the state as it was last saved, so content received before an error is kept
+		*upload = state.Upload
+		state.LockedUntil = time.Time{}
+		if _, err := u.save(context.WithoutCancel(ctx), state, etag); err != nil {
+			u.logger.Error(op, slog.Any("upload", upload.ID), slog.Any("error", err.Error()))
+		}
+	}()
+
+	buf := make([]byte, PartSize)
+	for state.Offset < state.Size {
+		// Parts are stored when they are full or when they are the last one
+		room := min(PartSize-state.PendingSize, state.Size-state.Offset)
+		n, readErr := io.ReadFull(content, buf[:room])
+		if n > 0 {
+			next := *state
+			if int64(n) == room {
+				err = u.putPart(ctx, &next, buf[:n])
+			} else {
+				err = u.putPending(ctx, &next, buf[:n])
+			}
+			if err != nil {
+				return err
+			}
+
+			next.LockedUntil = time.Now().Add(writeLease)
+			if etag, err = u.save(ctx, &next, etag); err != nil {
+				return err
+			}
+			if next.PendingObjects == 0 {
+				// Stored in the part, the objects are removed while the lease is held, so no other request writes them again
+				u.removePending(ctx, state)
+			}
+			*state = next
+		}
+
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if readErr != nil {
+			u.logger.Error(op, slog.Any("error", readErr.Error()))
+			return readErr
+		}
+	}
+	return nil
+}
+
+func (u *UploadRepository) CompleteUpload(ctx context.Context, upload *domain.Upload) error {
+	const op = "UploadRepository.CompleteUpload"
+
+	if len(upload.Parts) == 0 {
+		// Multipart uploads need at least one part, so empty files are stored directly
+		_, err := u.core.Client.PutObject(ctx, u.bucketName, upload.ID.String(), bytes.NewReader(nil), 0, minio.PutObjectOptions{
+			ContentType:  contentType(upload.ContentType),
+			UserMetadata: fileMetadata(upload.Name, upload.AuthorID, upload.Access),
+		})
+		if err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	} else {
+		parts := make([]minio.CompletePart, 0, len(upload.Parts))
+		for _, part := range upload.Parts {
+			parts = append(parts, minio.CompletePart{PartNumber: part.Number, ETag: part.ETag})
+		}
+
+		_, err := u.core.CompleteMultipartUpload(ctx, u.bucketName, upload.ID.String(), upload.MultipartID, parts, minio.PutObjectOptions{})
+		if err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+
+	return u.remove(ctx, upload.ID)
+}
+
+func (u *UploadRepository) DeleteUpload(ctx context.Context, upload *domain.Upload) error {
+	const op = "UploadRepository.DeleteUpload"
+
+	if upload.MultipartID == "" {
+		return u.remove(ctx, upload.ID)
+	}
+
+	err := u.core.AbortMultipartUpload(ctx, u.bucketName, upload.ID.String(), upload.MultipartID)
+	if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	return u.remove(ctx, upload.ID)
+}
+
+func (u *UploadRepository) GetExpiredUploads(ctx context.Context, now time.Time) ([]*domain.Upload, error) {
+	const op = "UploadRepository.GetExpiredUploads"
+
+	var expired []*domain.Upload
+	for object := range u.core.Client.ListObjects(ctx, u.bucketName, minio.ListObjectsOptions{Prefix: UploadsPrefix}) {
+		if object.Err != nil {
+			u.logger.Error(op, slog.Any("error", object.Err.Error()))
+			return nil, object.Err
+		}
+		if !strings.HasSuffix(object.Key, infoSuffix) {
+			continue
+		}
+
+		id, err := uuid.Parse(strings.TrimSuffix(strings.TrimPrefix(object.Key, UploadsPrefix), infoSuffix))
+		if err != nil {
+			continue
+		}
+
+		upload, err := u.GetUpload(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Completed while listing
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if upload.Expired(now) {
+			expired = append(expired, upload)
+		}
+	}
+
+	return expired, nil
+}
+
+// lock takes the lease of an upload for a request writing it at the offset of upload,
+// and returns the state of the upload with the ETag of its saved state.
+func (u *UploadRepository) lock(ctx context.Context, upload *domain.Upload) (*uploadState, string, error) {
+	state, etag, err := u.load(ctx, upload.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if state.Offset != upload.Offset {
+		return nil, "", domain.ErrWrongOffset
+	}
+	if time.Now().Before(state.LockedUntil) {
+		return nil, "", domain.ErrUploadLocked
+	}
+
+	// The content type is set by the caller before the first content of the upload
+	state.ContentType = upload.ContentType
+	state.LockedUntil = time.Now().Add(writeLease)
+	etag, err = u.save(ctx, state, etag)
+	if err != nil {
+		return nil, "", err
+	}
+	return state, etag, nil
+}
+
+// putPart stores the pending content of an upload followed by content as its next part.
+func (u *UploadRepository) putPart(ctx context.Context, state *uploadState, content []byte) error {
+	const op = "UploadRepository.putPart"
+
+	if state.MultipartID == "" {
+		multipartID, err := u.core.NewMultipartUpload(ctx, u.bucketName, state.ID.String(), minio.PutObjectOptions{
+			ContentType:  contentType(state.ContentType),
+			UserMetadata: fileMetadata(state.Name, state.AuthorID, state.Access),
+		})
+		if err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+		state.MultipartID = multipartID
+	}
+
+	pending := &pendingReader{ctx: ctx, repository: u, keys: pendingKeys(state)}
+	defer func() {
+		if err := pending.Close(); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}()
+
+	number := len(state.Parts) + 1
+	size := state.PendingSize + int64(len(content))
+	part, err := u.core.PutObjectPart(ctx, u.bucketName, state.ID.String(), state.MultipartID, number,
+		io.MultiReader(pending, bytes.NewReader(content)), size, minio.PutObjectPartOptions{})
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	state.Parts = append(state.Parts, domain.UploadPart{
+		Number: number,
+		ETag:   part.ETag,
+		Size:   part.Size,
+	})
+	state.Offset += int64(len(content))
+	state.PendingSize = 0
+	state.PendingObjects = 0
+	return nil
+}
+
+// putPending stores content as the next pending object of an upload.
+// Each request adds an object, so short requests do not rewrite the content received before them.
+func (u *UploadRepository) putPending(ctx context.Context, state *uploadState, content []byte) error {
+	const op = "UploadRepository.putPending"
+
+	key := pendingObjectKey(state.ID, state.PendingObjects)
+	_, err := u.core.Client.PutObject(ctx, u.bucketName, key, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{})
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	state.Offset += int64(len(content))
+	state.PendingSize += int64(len(content))
+	state.PendingObjects++
+	return nil
+}
+
+// removePending removes the pending objects of an upload, objects that are left are overwritten by later requests.
+func (u *UploadRepository) removePending(ctx context.Context, state *uploadState) {
+	const op = "UploadRepository.removePending"
+
+	for _, key := range pendingKeys(state) {
+		if err := u.core.Client.RemoveObject(ctx, u.bucketName, key, minio.RemoveObjectOptions{}); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}
+}
+
+// load returns the saved state of an upload with its ETag.
+func (u *UploadRepository) load(ctx context.Context, id uuid.UUID) (*uploadState, string, error) {
+	const op = "UploadRepository.load"
+
+	object, err := u.core.Client.GetObject(ctx, u.bucketName, infoKey(id), minio.GetObjectOptions{})
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, "", err
+	}
+	defer func(object *minio.Object) {
+		if err := object.Close(); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}(object)
+
+	var state uploadState
+	if err := json.NewDecoder(object).Decode(&state); err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, "", domain.ErrNotFound
+		}
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, "", err
+	}
+
+	info, err := object.Stat()
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, "", err
+	}
+
+	if state.PendingSize > 0 && state.PendingObjects == 0 {
+		// Saved when the pending content was kept in a single object
+		state.PendingObjects = 1
+	}
+
+	return &state, info.ETag, nil
+}
+
+// save saves the state of an upload, and returns the ETag of the saved state.
+// The state is only replaced if its ETag is etag, and fails with domain.ErrUploadLocked otherwise.
+func (u *UploadRepository) save(ctx context.Context, state *uploadState, etag string) (string, error) {
+	const op = "UploadRepository.save"
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	opts := minio.PutObjectOptions{ContentType: "application/json"}
+	if etag != "" {
+		opts.SetMatchETag(etag)
+	}
+	info, err := u.core.Client.PutObject(ctx, u.bucketName, infoKey(state.ID), bytes.NewReader(data), int64(len(data)), opts)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "PreconditionFailed" {
+			return "", domain.ErrUploadLocked
+		}
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return "", err
+	}
+	return info.ETag, nil
+}
+
+// remove removes the state and the pending content of an upload.
+func (u *UploadRepository) remove(ctx context.Context, id uuid.UUID) error {
+	const op = "UploadRepository.remove"
+
+	keys := []string{}
+	for object := range u.core.Client.ListObjects(ctx, u.bucketName, minio.ListObjectsOptions{Prefix: pendingKey(id)}) {
+		if object.Err != nil {
+			u.logger.Error(op, slog.Any("error", object.Err.Error()))
+			return object.Err
+		}
+		keys = append(keys, object.Key)
+	}
+
+	for _, key := range append(keys, infoKey(id)) {
+		if err := u.core.Client.RemoveObject(ctx, u.bucketName, key, minio.RemoveObjectOptions{}); err != nil {
+			u.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingReader reads the pending objects of an upload one after the other.
+type pendingReader struct {
+	ctx        context.Context
+	repository *UploadRepository
+	keys       []string
+	current    *minio.Object
+}
+
+func (r *pendingReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			object, err := r.repository.core.Client.GetObject(r.ctx, r.repository.bucketName, r.keys[0], minio.GetObjectOptions{})
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.keys = object, r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if errors.Is(err, io.EOF) {
+			err = r.current.Close()
+			r.current = nil
+			if n > 0 || err != nil {
+				return n, err
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *pendingReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Close()
+}
+
+func infoKey(id uuid.UUID) string {
+	return UploadsPrefix + id.String() + infoSuffix
+}
+
+func pendingKey(id uuid.UUID) string {
+	return UploadsPrefix + id.String() + pendingSuffix
+}
+
+// pendingObjectKey returns the key of the pending object of an upload with the given index.
+// The first one has the key of the single pending object of the uploads saved before there were several.
+func pendingObjectKey(id uuid.UUID, index int) string {
+	if index == 0 {
+		return pendingKey(id)
+	}
+	return pendingKey(id) + "." + strconv.Itoa(index)
+}
+
+// pendingKeys returns the keys of the pending objects of an upload in order.
+func pendingKeys(state *uploadState) []string {
+	keys := make([]string, 0, state.PendingObjects)
+	for i := 0; i < state.PendingObjects; i++ {
+		keys = append(keys, pendingObjectKey(state.ID, i))
+	}
+	return keys
+}
+
+func NewUploadRepository(client *minio.Client, bucketName string, logger *slog.Logger) *UploadRepository {
+	return &UploadRepository{
+		bucketName: bucketName,
+		core:       minio.Core{Client: client},
+		logger:     logger,
+	}
+}
@@ -0,0 +1,55 @@
+package minioRepo
+
+import (
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"log/slog"
+	"sync"
+)
+
+// UsagePrefix is the prefix of the objects with the storage used by authors.
+const UsagePrefix = "usage/"
+
+var _ usecases.UsageRepositoryInterface = &UsageRepository{}
+
+// UsageRepository keeps the number of bytes stored by each author in a small object.
+//
+// Updates are serialized within the process only, so the usage is exact while the service runs as a single instance.
+// With a database, the service keeps the usage there and reads this one only for authors it has not counted yet.
+type UsageRepository struct {
+	bucketName string
+	client     *minio.Client
+	mu         sync.Mutex
+	logger     *slog.Logger
+}
+
+func (u *UsageRepository) GetUsage(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	// Authors without files have no usage yet
+	return readCounter(ctx, u.client, u.bucketName, usageKey(authorID), u.logger.With("op", "UsageRepository.GetUsage"))
+}
+
+func (u *UsageRepository) AddUsage(ctx context.Context, authorID uuid.UUID, delta int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	used, err := u.GetUsage(ctx, authorID)
+	if err != nil {
+		return err
+	}
+
+	return writeCounter(ctx, u.client, u.bucketName, usageKey(authorID), max(used+delta, 0), u.logger.With("op", "UsageRepository.AddUsage"))
+}
+
+func usageKey(authorID uuid.UUID) string {
+	return UsagePrefix + authorID.String()
+}
+
+func NewUsageRepository(client *minio.Client, bucketName string, logger *slog.Logger) *UsageRepository {
+	return &UsageRepository{
+		bucketName: bucketName,
+		client:     client,
+		logger:     logger,
+	}
+}
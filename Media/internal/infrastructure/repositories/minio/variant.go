@@ -0,0 +1,105 @@
+package minioRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"log/slog"
+	"strconv"
+)
+
+// VariantsPrefix is the prefix of the objects of the variants of images.
+const VariantsPrefix = "variants/"
+
+const (
+	WidthKey  = "Width"
+	HeightKey = "Height"
+)
+
+var _ usecases.VariantRepositoryInterface = &VariantRepository{}
+
+// VariantRepository stores the variants of an image under a prefix with the ID of the image.
+type VariantRepository struct {
+	bucketName string
+	client     *minio.Client
+	logger     *slog.Logger
+}
+
+func (v *VariantRepository) PutVariant(ctx context.Context, fileID uuid.UUID, image usecases.VariantImage) error {
+	const op = "VariantRepository.PutVariant"
+
+	_, err := v.client.PutObject(ctx, v.bucketName, variantKey(fileID, image.Name), bytes.NewReader(image.Content), int64(len(image.Content)), minio.PutObjectOptions{
+		ContentType: image.ContentType,
+		UserMetadata: map[string]string{
+			WidthKey:  strconv.Itoa(image.Width),
+			HeightKey: strconv.Itoa(image.Height),
+		},
+	})
+	if err != nil {
+		v.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (v *VariantRepository) StatVariant(ctx context.Context, fileID uuid.UUID, name string) (domain.Variant, error) {
+	const op = "VariantRepository.StatVariant"
+
+	info, err := v.client.StatObject(ctx, v.bucketName, variantKey(fileID, name), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return domain.Variant{}, domain.ErrNotFound
+		}
+		v.logger.Error(op, slog.Any("error", err.Error()))
+		return domain.Variant{}, err
+	}
+
+	width, _ := strconv.Atoi(info.UserMetadata[WidthKey])
+	height, _ := strconv.Atoi(info.UserMetadata[HeightKey])
+
+	return domain.Variant{
+		Name:         name,
+		Width:        width,
+		Height:       height,
+		ContentType:  info.ContentType,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+func (v *VariantRepository) GetVariant(ctx context.Context, fileID uuid.UUID, name string, opts domain.ReadOptions) (io.ReadCloser, error) {
+	return getObject(ctx, v.client, v.bucketName, variantKey(fileID, name), opts, v.logger.With("op", "VariantRepository.GetVariant"))
+}
+
+func (v *VariantRepository) DeleteVariants(ctx context.Context, fileID uuid.UUID) error {
+	const op = "VariantRepository.DeleteVariants"
+
+	objects := v.client.ListObjects(ctx, v.bucketName, minio.ListObjectsOptions{Prefix: variantKey(fileID, "")})
+
+	// The results are drained, so the removal is not blocked by an error
+	var err error
+	for result := range v.client.RemoveObjects(ctx, v.bucketName, objects, minio.RemoveObjectsOptions{}) {
+		if result.Err != nil && err == nil {
+			v.logger.Error(op, slog.Any("error", result.Err.Error()))
+			err = result.Err
+		}
+	}
+	return err
+}
+
+func variantKey(fileID uuid.UUID, name string) string {
+	return VariantsPrefix + fileID.String() + "/" + name
+}
+
+func NewVariantRepository(client *minio.Client, bucketName string, logger *slog.Logger) *VariantRepository {
+	return &VariantRepository{
+		bucketName: bucketName,
+		client:     client,
+		logger:     logger,
+	}
+}
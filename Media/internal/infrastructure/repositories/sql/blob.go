@@ -0,0 +1,54 @@
+package sqlRepo
+
+import (
+	"Media/internal/infrastructure/repositories/sql/entities"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+// BlobRefRepository keeps the number of files referencing each blob in an SQL database.
+//
+// The row of a blob is locked while it is updated, so instances of the service sharing the storage update it in turn.
+// Rows of blobs without references are kept at 0, so that an update always finds the row it locks.
+type BlobRefRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func (r *BlobRefRepository) UpdateRefs(ctx context.Context, checksum string, update func(refs int64) (int64, error)) error {
+	const op = "BlobRefRepository.UpdateRefs"
+
+	var updateErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.BlobRef{Checksum: checksum}).Error; err != nil {
+			return err
+		}
+
+		var entity entities.BlobRef
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("checksum = ?", checksum).First(&entity).Error; err != nil {
+			return err
+		}
+
+		refs, err := update(entity.Refs)
+		if err != nil {
+			updateErr = err
+			return err
+		}
+		return tx.Model(&entity).Update("refs", refs).Error
+	})
+	if err != nil && !errors.Is(err, updateErr) {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return err
+}
+
+// NewBlobRefRepository creates a new BlobRefRepository.
+func NewBlobRefRepository(db *gorm.DB, logger *slog.Logger) *BlobRefRepository {
+	return &BlobRefRepository{
+		db:     db,
+		logger: logger,
+	}
+}
@@ -0,0 +1,44 @@
+package sqlRepo
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestBlobRefRepository_UpdateRefs(t *testing.T) {
+	repo := NewBlobRefRepository(setupDB(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	increment := func(refs int64) (int64, error) { return refs + 1, nil }
+	current := func(checksum string) int64 {
+		var got int64
+		require.NoError(t, repo.UpdateRefs(ctx, checksum, func(refs int64) (int64, error) {
+			got = refs
+			return refs, nil
+		}))
+		return got
+	}
+
+	// Blobs without references start at 0
+	assert.Zero(t, current("abc"))
+
+	require.NoError(t, repo.UpdateRefs(ctx, "abc", increment))
+	require.NoError(t, repo.UpdateRefs(ctx, "abc", increment))
+	assert.Equal(t, int64(2), current("abc"))
+	assert.Zero(t, current("def"))
+
+	// A failed update keeps the number
+	failure := errors.New("blob unavailable")
+	err := repo.UpdateRefs(ctx, "abc", func(refs int64) (int64, error) { return 0, failure })
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, int64(2), current("abc"))
+
+	require.NoError(t, repo.UpdateRefs(ctx, "abc", func(refs int64) (int64, error) { return 0, nil }))
+	assert.Zero(t, current("abc"))
+	require.NoError(t, repo.UpdateRefs(ctx, "abc", increment))
+	assert.Equal(t, int64(1), current("abc"))
+}
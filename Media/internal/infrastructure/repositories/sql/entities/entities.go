@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// FileMeta is the indexed metadata of a file in gorm.
+type FileMeta struct {
+	ID          uuid.UUID  `json:"id" gorm:"primary_key"`
+	AuthorID    uuid.UUID  `json:"authorId" gorm:"index:idx_file_metas_author_created,priority:1"`
+	Name        string     `json:"name"`
+	Size        int64      `json:"size"`
+	ContentType string     `json:"contentType"`
+	Checksum    string     `json:"checksum" gorm:"index"`
+	Visibility  string     `json:"visibility"`
+	SharedWith  string     `json:"sharedWith"` // comma separated user IDs
+	Purpose     string     `json:"purpose" gorm:"index;default:'generic'"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"index;index:idx_file_metas_author_created,priority:2"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	ReleasedAt  *time.Time `json:"releasedAt"` // when the file lost its last reference, nil if it never had one
+}
+
+// FileReference is a reference to a file in gorm.
+type FileReference struct {
+	FileID    uuid.UUID `json:"fileId" gorm:"primary_key"`
+	Owner     string    `json:"owner" gorm:"primary_key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BlobRef is the number of files referencing a blob in gorm.
+type BlobRef struct {
+	Checksum string `json:"checksum" gorm:"primary_key"`
+	Refs     int64  `json:"refs"`
+}
+
+// Usage is the storage used by an author in gorm.
+type Usage struct {
+	AuthorID uuid.UUID `json:"authorId" gorm:"primary_key"`
+	Used     int64     `json:"used"`
+}
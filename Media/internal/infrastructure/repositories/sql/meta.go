@@ -0,0 +1,241 @@
+package sqlRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/infrastructure/repositories/sql/entities"
+	"Media/internal/usecases"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+var (
+	_ usecases.MetaRepositoryInterface      = &MetaRepository{}
+	_ usecases.ReferenceRepositoryInterface = &MetaRepository{}
+)
+
+// savedColumns are the columns SaveMeta replaces, when files lost their last reference is kept.
+var savedColumns = []string{"author_id", "name", "size", "content_type", "checksum", "visibility", "shared_with", "purpose", "created_at", "updated_at"}
+
+// MetaRepository indexes the metadata of files and the references to them in an SQL database.
+type MetaRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func (r *MetaRepository) SaveMeta(ctx context.Context, meta domain.FileMeta) error {
+	const op = "MetaRepository.SaveMeta"
+
+	entity := metaToEntity(meta)
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(savedColumns),
+	}).Create(&entity).Error
+	if err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (r *MetaRepository) GetMeta(ctx context.Context, id uuid.UUID) (domain.FileMeta, error) {
+	const op = "MetaRepository.GetMeta"
+
+	var entity entities.FileMeta
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.FileMeta{}, domain.ErrNotFound
+		}
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return domain.FileMeta{}, err
+	}
+	return entityToMeta(&entity), nil
+}
+
+func (r *MetaRepository) ListMeta(ctx context.Context, query domain.MetaQuery) (domain.MetaPage, error) {
+	const op = "MetaRepository.ListMeta"
+
+	db := r.db.WithContext(ctx)
+	if query.ReaderID == uuid.Nil {
+		db = db.Where("visibility = ?", domain.VisibilityPublic)
+	} else {
+		// Shared files list their readers, user IDs have a fixed length so they do not match parts of others
+		db = db.Where("visibility = ? OR author_id = ? OR (visibility = ? AND shared_with LIKE ?)",
+			domain.VisibilityPublic, query.ReaderID, domain.VisibilityShared, "%"+query.ReaderID.String()+"%")
+	}
+	if query.AuthorID != nil {
+		db = db.Where("author_id = ?", *query.AuthorID)
+	}
+	if query.Since != nil {
+		db = db.Where("created_at >= ?", *query.Since)
+	}
+	if query.Until != nil {
+		db = db.Where("created_at < ?", *query.Until)
+	}
+	if query.After != nil {
+		db = db.Where("(created_at, id) > (?, ?)", query.After.CreatedAt, query.After.ID)
+	}
+
+	var found []*entities.FileMeta
+	if err := db.Order("created_at, id").Limit(query.Limit + 1).Find(&found).Error; err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return domain.MetaPage{}, err
+	}
+
+	items := make([]domain.FileMeta, 0, len(found))
+	for _, entity := range found {
+		items = append(items, entityToMeta(entity))
+	}
+	return domain.NewMetaPage(items, query.Limit), nil
+}
+
+func (r *MetaRepository) DeleteMeta(ctx context.Context, id uuid.UUID) error {
+	const op = "MetaRepository.DeleteMeta"
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&entities.FileMeta{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return tx.Where("file_id = ?", id).Delete(&entities.FileReference{}).Error
+	})
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return err
+}
+
+func (r *MetaRepository) AddReference(ctx context.Context, ref domain.Reference) error {
+	const op = "MetaRepository.AddReference"
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.FileMeta{}).Where("id = ?", ref.FileID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrNotFound
+		}
+
+		entity := entities.FileReference{FileID: ref.FileID, Owner: ref.Owner, CreatedAt: ref.CreatedAt}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity).Error
+	})
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return err
+}
+
+func (r *MetaRepository) RemoveReference(ctx context.Context, fileID uuid.UUID, owner string) error {
+	const op = "MetaRepository.RemoveReference"
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("file_id = ? AND owner = ?", fileID, owner).Delete(&entities.FileReference{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+
+		var left int64
+		if err := tx.Model(&entities.FileReference{}).Where("file_id = ?", fileID).Count(&left).Error; err != nil {
+			return err
+		}
+		if left > 0 {
+			return nil
+		}
+		return tx.Model(&entities.FileMeta{}).Where("id = ?", fileID).Update("released_at", time.Now().UTC()).Error
+	})
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+	}
+	return err
+}
+
+func (r *MetaRepository) GetUnreferenced(ctx context.Context, purpose domain.Purpose, before time.Time, limit int) ([]domain.FileMeta, error) {
+	const op = "MetaRepository.GetUnreferenced"
+
+	var found []*entities.FileMeta
+	err := r.db.WithContext(ctx).
+		Where("purpose = ?", purpose).
+		Where("COALESCE(released_at, created_at) < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM file_references WHERE file_references.file_id = file_meta.id)").
+		Order("COALESCE(released_at, created_at), id").
+		Limit(limit).
+		Find(&found).Error
+	if err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+
+	items := make([]domain.FileMeta, 0, len(found))
+	for _, entity := range found {
+		items = append(items, entityToMeta(entity))
+	}
+	return items, nil
+}
+
+func metaToEntity(meta domain.FileMeta) entities.FileMeta {
+	sharedWith := make([]string, 0, len(meta.Access.SharedWith))
+	for _, id := range meta.Access.SharedWith {
+		sharedWith = append(sharedWith, id.String())
+	}
+
+	return entities.FileMeta{
+		ID:          meta.ID,
+		AuthorID:    meta.AuthorID,
+		Name:        meta.Name,
+		Size:        meta.Size,
+		ContentType: meta.ContentType,
+		Checksum:    meta.Checksum,
+		Visibility:  string(meta.Access.Visibility),
+		SharedWith:  strings.Join(sharedWith, ","),
+		Purpose:     string(meta.Purpose),
+		CreatedAt:   meta.CreatedAt,
+		UpdatedAt:   meta.UpdatedAt,
+	}
+}
+
+func entityToMeta(entity *entities.FileMeta) domain.FileMeta {
+	var sharedWith []uuid.UUID
+	if entity.SharedWith != "" {
+		for _, rawID := range strings.Split(entity.SharedWith, ",") {
+			if id, err := uuid.Parse(rawID); err == nil {
+				sharedWith = append(sharedWith, id)
+			}
+		}
+	}
+
+	return domain.FileMeta{
+		ID:          entity.ID,
+		AuthorID:    entity.AuthorID,
+		Name:        entity.Name,
+		Size:        entity.Size,
+		ContentType: entity.ContentType,
+		Checksum:    entity.Checksum,
+		Access: domain.Access{
+			Visibility: domain.Visibility(entity.Visibility),
+			SharedWith: sharedWith,
+		},
+		Purpose:   domain.Purpose(entity.Purpose),
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}
+}
+
+// NewMetaRepository creates a new MetaRepository.
+func NewMetaRepository(db *gorm.DB, logger *slog.Logger) *MetaRepository {
+	return &MetaRepository{
+		db:     db,
+		logger: logger,
+	}
+}
@@ -0,0 +1,191 @@
+package sqlRepo
+
+import (
+	"Media/internal/domain"
+	"Media/internal/infrastructure/repositories/sql/entities"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// setupDB returns an in-memory database with the tables of the repositories.
+func setupDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.FileMeta{}, &entities.FileReference{}, &entities.BlobRef{}, &entities.Usage{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func setupMetaRepository(t *testing.T) *MetaRepository {
+	return NewMetaRepository(setupDB(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestMetaRepository_SaveMeta(t *testing.T) {
+	repo := setupMetaRepository(t)
+	meta := domain.FileMeta{
+		ID:       uuid.New(),
+		AuthorID: uuid.New(),
+		Name:     "photo.png",
+		Access:   domain.Access{Visibility: domain.VisibilityPublic},
+	}
+
+	assert.NoError(t, repo.SaveMeta(context.Background(), meta))
+
+	meta.Name = "renamed.png"
+	assert.NoError(t, repo.SaveMeta(context.Background(), meta))
+
+	found, err := repo.GetMeta(context.Background(), meta.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed.png", found.Name)
+
+	assert.NoError(t, repo.DeleteMeta(context.Background(), meta.ID))
+	_, err = repo.GetMeta(context.Background(), meta.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.DeleteMeta(context.Background(), meta.ID), domain.ErrNotFound)
+}
+
+func TestMetaRepository_ListMeta(t *testing.T) {
+	repo := setupMetaRepository(t)
+	author, reader := uuid.New(), uuid.New()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	visibilities := []domain.Access{
+		{Visibility: domain.VisibilityPublic},
+		{Visibility: domain.VisibilityPrivate},
+		{Visibility: domain.VisibilityShared, SharedWith: []uuid.UUID{reader}},
+		{Visibility: domain.VisibilityShared, SharedWith: []uuid.UUID{uuid.New()}},
+		{Visibility: domain.VisibilityPublic},
+	}
+	ids := make([]uuid.UUID, len(visibilities))
+	for i, access := range visibilities {
+		ids[i] = uuid.New()
+		assert.NoError(t, repo.SaveMeta(context.Background(), domain.FileMeta{
+			ID:        ids[i],
+			AuthorID:  author,
+			Access:    access,
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	t.Run("anonymous readers see public files", func(t *testing.T) {
+		page, err := repo.ListMeta(context.Background(), domain.MetaQuery{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{ids[0], ids[4]}, metaIDs(page))
+		assert.Nil(t, page.Next)
+	})
+
+	t.Run("readers see files shared with them", func(t *testing.T) {
+		page, err := repo.ListMeta(context.Background(), domain.MetaQuery{ReaderID: reader, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{ids[0], ids[2], ids[4]}, metaIDs(page))
+	})
+
+	t.Run("authors page through their files", func(t *testing.T) {
+		query := domain.MetaQuery{ReaderID: author, AuthorID: &author, Limit: 2}
+		var listed []uuid.UUID
+		for {
+			page, err := repo.ListMeta(context.Background(), query)
+			assert.NoError(t, err)
+			listed = append(listed, metaIDs(page)...)
+			if page.Next == nil {
+				break
+			}
+			query.After = page.Next
+		}
+		assert.Equal(t, ids, listed)
+	})
+
+	t.Run("files are filtered by creation time", func(t *testing.T) {
+		since, until := start.Add(time.Hour), start.Add(4*time.Hour)
+		page, err := repo.ListMeta(context.Background(), domain.MetaQuery{ReaderID: author, Since: &since, Until: &until, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, ids[1:4], metaIDs(page))
+	})
+}
+
+func metaIDs(page domain.MetaPage) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, meta := range page.Items {
+		ids = append(ids, meta.ID)
+	}
+	return ids
+}
+
+func TestMetaRepository_References(t *testing.T) {
+	ctx := context.Background()
+	repo := setupMetaRepository(t)
+	created := time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond)
+
+	referenced, released, unused, avatar := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	purposes := map[uuid.UUID]domain.Purpose{
+		referenced: domain.PurposeAttachment,
+		released:   domain.PurposeAttachment,
+		unused:     domain.PurposeAttachment,
+		avatar:     domain.PurposeAvatar,
+	}
+	for id, purpose := range purposes {
+		assert.NoError(t, repo.SaveMeta(ctx, domain.FileMeta{
+			ID:        id,
+			Access:    domain.Access{Visibility: domain.VisibilityPublic},
+			Purpose:   purpose,
+			CreatedAt: created,
+		}))
+	}
+
+	for _, id := range []uuid.UUID{referenced, released} {
+		ref := domain.Reference{FileID: id, Owner: "post:1", CreatedAt: created}
+		assert.NoError(t, repo.AddReference(ctx, ref))
+		// Registering a reference again changes nothing
+		assert.NoError(t, repo.AddReference(ctx, ref))
+	}
+	assert.NoError(t, repo.AddReference(ctx, domain.Reference{FileID: referenced, Owner: "post:2", CreatedAt: created}))
+	assert.ErrorIs(t, repo.AddReference(ctx, domain.Reference{FileID: uuid.New(), Owner: "post:1"}), domain.ErrNotFound)
+
+	assert.NoError(t, repo.RemoveReference(ctx, referenced, "post:1"))
+	assert.NoError(t, repo.RemoveReference(ctx, released, "post:1"))
+	assert.ErrorIs(t, repo.RemoveReference(ctx, released, "post:1"), domain.ErrNotFound)
+
+	// Saving the metadata again keeps when the file was released
+	assert.NoError(t, repo.SaveMeta(ctx, domain.FileMeta{
+		ID:        released,
+		Name:      "renamed.png",
+		Access:    domain.Access{Visibility: domain.VisibilityPublic},
+		Purpose:   domain.PurposeAttachment,
+		CreatedAt: created,
+	}))
+
+	t.Run("files are unreferenced since their creation or their release", func(t *testing.T) {
+		metas, err := repo.GetUnreferenced(ctx, domain.PurposeAttachment, time.Now().Add(-time.Minute), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{unused}, metaIDs(domain.MetaPage{Items: metas}))
+
+		metas, err = repo.GetUnreferenced(ctx, domain.PurposeAttachment, time.Now().Add(time.Minute), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{unused, released}, metaIDs(domain.MetaPage{Items: metas}))
+	})
+
+	t.Run("files are listed oldest first up to the limit", func(t *testing.T) {
+		metas, err := repo.GetUnreferenced(ctx, domain.PurposeAttachment, time.Now().Add(time.Minute), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{unused}, metaIDs(domain.MetaPage{Items: metas}))
+	})
+
+	t.Run("references are deleted with the metadata", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteMeta(ctx, referenced))
+		assert.ErrorIs(t, repo.RemoveReference(ctx, referenced, "post:2"), domain.ErrNotFound)
+	})
+}
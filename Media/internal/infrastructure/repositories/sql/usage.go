@@ -0,0 +1,95 @@
+package sqlRepo
+
+import (
+	"Media/internal/infrastructure/repositories/sql/entities"
+	"Media/internal/usecases"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+var _ usecases.UsageRepositoryInterface = &UsageRepository{}
+
+// UsageRepository keeps the number of bytes stored by each author in an SQL database, updated atomically.
+//
+// Authors are counted from their first update, with the usage the storage kept for them until then.
+type UsageRepository struct {
+	db     *gorm.DB
+	legacy usecases.UsageRepositoryInterface // nil if authors had no usage before
+	logger *slog.Logger
+}
+
+func (u *UsageRepository) GetUsage(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	const op = "UsageRepository.GetUsage"
+
+	var entity entities.Usage
+	if err := u.db.WithContext(ctx).Where("author_id = ?", authorID).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return u.legacyUsage(ctx, authorID)
+		}
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return 0, err
+	}
+	return entity.Used, nil
+}
+
+func (u *UsageRepository) AddUsage(ctx context.Context, authorID uuid.UUID, delta int64) error {
+	const op = "UsageRepository.AddUsage"
+
+	if err := u.seed(ctx, authorID); err != nil {
+		return err
+	}
+
+	err := u.db.WithContext(ctx).Model(&entities.Usage{}).Where("author_id = ?", authorID).
+		Update("used", gorm.Expr("CASE WHEN used + ? < 0 THEN 0 ELSE used + ? END", delta, delta)).Error
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// seed creates the row of an author with their usage until now if they have none.
+func (u *UsageRepository) seed(ctx context.Context, authorID uuid.UUID) error {
+	const op = "UsageRepository.seed"
+
+	var count int64
+	if err := u.db.WithContext(ctx).Model(&entities.Usage{}).Where("author_id = ?", authorID).Count(&count).Error; err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	used, err := u.legacyUsage(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	// Another instance may seed the author at the same time, the first row is kept
+	err = u.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.Usage{AuthorID: authorID, Used: used}).Error
+	if err != nil {
+		u.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (u *UsageRepository) legacyUsage(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	if u.legacy == nil {
+		return 0, nil
+	}
+	return u.legacy.GetUsage(ctx, authorID)
+}
+
+// NewUsageRepository creates a new UsageRepository, legacy is the usage kept by the storage before, if any.
+func NewUsageRepository(db *gorm.DB, legacy usecases.UsageRepositoryInterface, logger *slog.Logger) *UsageRepository {
+	return &UsageRepository{
+		db:     db,
+		legacy: legacy,
+		logger: logger,
+	}
+}
@@ -0,0 +1,59 @@
+package sqlRepo
+
+import (
+	inmemoryRepo "Media/internal/infrastructure/repositories/inmemory"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestUsageRepository(t *testing.T) {
+	ctx := context.Background()
+	legacy := inmemoryRepo.NewUsageRepository()
+	repo := NewUsageRepository(setupDB(t), legacy, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	counted, uncounted := uuid.New(), uuid.New()
+	require.NoError(t, legacy.AddUsage(ctx, counted, 100))
+	require.NoError(t, legacy.AddUsage(ctx, uncounted, 30))
+	used := func(authorID uuid.UUID) int64 {
+		got, err := repo.GetUsage(ctx, authorID)
+		require.NoError(t, err)
+		return got
+	}
+
+	// Authors are counted from the usage the storage kept for them
+	assert.Equal(t, int64(30), used(uncounted))
+	require.NoError(t, repo.AddUsage(ctx, counted, 20))
+	assert.Equal(t, int64(120), used(counted))
+
+	// The usage of the storage is not read again
+	require.NoError(t, legacy.AddUsage(ctx, counted, 1000))
+	require.NoError(t, repo.AddUsage(ctx, counted, -50))
+	assert.Equal(t, int64(70), used(counted))
+
+	// Usage does not go below 0
+	require.NoError(t, repo.AddUsage(ctx, counted, -500))
+	assert.Zero(t, used(counted))
+
+	// Authors without usage start at 0
+	other := uuid.New()
+	assert.Zero(t, used(other))
+	require.NoError(t, repo.AddUsage(ctx, other, 5))
+	assert.Equal(t, int64(5), used(other))
+}
+
+func TestUsageRepository_WithoutLegacy(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUsageRepository(setupDB(t), nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	authorID := uuid.New()
+
+	require.NoError(t, repo.AddUsage(ctx, authorID, 10))
+	require.NoError(t, repo.AddUsage(ctx, authorID, 15))
+
+	used, err := repo.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), used)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"Media/internal/domain"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// accessRequest is the access control of a file in request bodies.
+type accessRequest struct {
+	Visibility domain.Visibility `json:"visibility"`
+	SharedWith []uuid.UUID       `json:"shared_with"`
+}
+
+func (a accessRequest) toDomain() domain.Access {
+	return domain.Access{
+		Visibility: a.Visibility,
+		SharedWith: a.SharedWith,
+	}
+}
+
+// parseAccessFields parses the access control of a file from a visibility field and a shared_with
+// field of comma separated user IDs.
+func parseAccessFields(visibility string, sharedWith string) (domain.Access, error) {
+	access := domain.Access{Visibility: domain.Visibility(visibility)}
+	if sharedWith == "" {
+		return access, nil
+	}
+
+	for _, rawID := range strings.Split(sharedWith, ",") {
+		id, err := uuid.Parse(strings.TrimSpace(rawID))
+		if err != nil {
+			return domain.Access{}, domain.ErrInvalidAccess
+		}
+		access.SharedWith = append(access.SharedWith, id)
+	}
+	return access, nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"Media/internal/domain"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errUnsatisfiableRange is returned for ranges outside the content of a file.
+var errUnsatisfiableRange = errors.New("range not satisfiable")
+
+// quoteETag returns the ETag of a file as sent in headers.
+func quoteETag(etag string) string {
+	return `"` + strings.Trim(etag, `"`) + `"`
+}
+
+// etagListMatches reports whether a list of entity tags of an If-None-Match or If-Range header matches an ETag.
+// Weak tags only match with weak comparison.
+func etagListMatches(header string, etag string, weak bool) bool {
+	etag = quoteETag(etag)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified reports whether the client already has the current version of a file.
+// If-Modified-Since is only checked without If-None-Match.
+func notModified(r *http.Request, file domain.File) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return etagListMatches(header, file.ETag, true)
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !file.LastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+		return !file.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// rangeApplies reports whether the Range header of a request applies to the current version of a file.
+// With If-Range, the range is ignored unless the ETag or the modification time of the file match.
+func rangeApplies(r *http.Request, file domain.File) bool {
+	header := r.Header.Get("If-Range")
+	if header == "" {
+		return true
+	}
+
+	if strings.HasSuffix(header, `"`) {
+		// Weak tags never match If-Range
+		return !strings.HasPrefix(header, "W/") && header == quoteETag(file.ETag)
+	}
+
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	return file.LastModified.Truncate(time.Second).Equal(since)
+}
+
+// parseRange parses the Range header of a request for a file of the given size.
+// It returns nil for requests of the whole file, including requests of several ranges, which are served whole.
+func parseRange(header string, size int64) (*domain.ByteRange, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if header == "" || !ok || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+
+	rawStart, rawEnd, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, nil
+	}
+
+	if rawStart == "" {
+		// A suffix range selects the last bytes of the file
+		length, err := strconv.ParseInt(rawEnd, 10, 64)
+		if err != nil || length < 0 {
+			return nil, nil
+		}
+		if length == 0 || size == 0 {
+			return nil, errUnsatisfiableRange
+		}
+		if length > size {
+			length = size
+		}
+		return &domain.ByteRange{Start: size - length, End: size - 1}, nil
+	}
+
+	start, err := strconv.ParseInt(rawStart, 10, 64)
+	if err != nil || start < 0 {
+		return nil, nil
+	}
+	if start >= size {
+		return nil, errUnsatisfiableRange
+	}
+
+	end := size - 1
+	if rawEnd != "" {
+		end, err = strconv.ParseInt(rawEnd, 10, 64)
+		if err != nil || end < start {
+			return nil, nil
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	return &domain.ByteRange{Start: start, End: end}, nil
+}
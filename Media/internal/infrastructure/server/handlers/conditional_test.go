@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"Media/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	const size = 100
+
+	tests := []struct {
+		name   string
+		header string
+		size   int64
+		want   *domain.ByteRange
+		err    error
+	}{
+		{name: "no range", header: "", size: size},
+		{name: "other unit", header: "items=0-10", size: size},
+		{name: "closed range", header: "bytes=10-19", size: size, want: &domain.ByteRange{Start: 10, End: 19}},
+		{name: "open range", header: "bytes=90-", size: size, want: &domain.ByteRange{Start: 90, End: 99}},
+		{name: "single byte", header: "bytes=0-0", size: size, want: &domain.ByteRange{Start: 0, End: 0}},
+		{name: "end clamped to the size", header: "bytes=90-1000", size: size, want: &domain.ByteRange{Start: 90, End: 99}},
+		{name: "suffix range", header: "bytes=-10", size: size, want: &domain.ByteRange{Start: 90, End: 99}},
+		{name: "suffix longer than the file", header: "bytes=-1000", size: size, want: &domain.ByteRange{Start: 0, End: 99}},
+		{name: "empty suffix", header: "bytes=-0", size: size, err: errUnsatisfiableRange},
+		{name: "suffix of an empty file", header: "bytes=-10", size: 0, err: errUnsatisfiableRange},
+		{name: "start at the size", header: "bytes=100-", size: size, err: errUnsatisfiableRange},
+		{name: "start beyond the size", header: "bytes=200-300", size: size, err: errUnsatisfiableRange},
+		{name: "end before start", header: "bytes=20-10", size: size},
+		{name: "multiple ranges served whole", header: "bytes=0-10,20-30", size: size},
+		{name: "malformed", header: "bytes=abc", size: size},
+		{name: "negative suffix", header: "bytes=--1", size: size},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRange(tt.header, tt.size)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEtagListMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		want   bool
+	}{
+		{name: "strong tag", header: `"etag"`, want: true},
+		{name: "other tag", header: `"other"`, want: false},
+		{name: "list", header: `"other", "etag"`, want: true},
+		{name: "any", header: `*`, want: true},
+		{name: "weak tag with weak comparison", header: `W/"etag"`, weak: true, want: true},
+		{name: "weak tag with strong comparison", header: `W/"etag"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagListMatches(tt.header, "etag", tt.weak))
+		})
+	}
+}
+
+func TestRangeApplies(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	file := domain.File{ETag: "etag", LastModified: modified}
+
+	tests := []struct {
+		name    string
+		ifRange string
+		want    bool
+	}{
+		{name: "no If-Range", ifRange: "", want: true},
+		{name: "strong matching tag", ifRange: `"etag"`, want: true},
+		{name: "strong other tag", ifRange: `"other"`, want: false},
+		{name: "weak matching tag", ifRange: `W/"etag"`, want: false},
+		{name: "matching date", ifRange: modified.Format(http.TimeFormat), want: true},
+		{name: "other date", ifRange: modified.Add(-time.Hour).Format(http.TimeFormat), want: false},
+		{name: "malformed date", ifRange: "yesterday", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Range", "bytes=0-10")
+			if tt.ifRange != "" {
+				r.Header.Set("If-Range", tt.ifRange)
+			}
+
+			assert.Equal(t, tt.want, rangeApplies(r, file))
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	file := domain.File{ETag: "etag", LastModified: modified}
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+
+	tests := []struct {
+		name            string
+		ifNoneMatch     string
+		ifModifiedSince string
+		want            bool
+	}{
+		{name: "no conditions", want: false},
+		{name: "matching tag", ifNoneMatch: `"etag"`, want: true},
+		{name: "weak matching tag", ifNoneMatch: `W/"etag"`, want: true},
+		{name: "other tag", ifNoneMatch: `"other"`, want: false},
+		{name: "not modified since", ifModifiedSince: after, want: true},
+		{name: "modified at the second", ifModifiedSince: modified.Format(http.TimeFormat), want: true},
+		{name: "modified since", ifModifiedSince: before, want: false},
+		{name: "malformed date", ifModifiedSince: "yesterday", want: false},
+		{name: "If-None-Match takes precedence over a matching date", ifNoneMatch: `"other"`, ifModifiedSince: after, want: false},
+		{name: "If-None-Match takes precedence over an older date", ifNoneMatch: `"etag"`, ifModifiedSince: before, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			if tt.ifModifiedSince != "" {
+				r.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+
+			assert.Equal(t, tt.want, notModified(r, file))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"Media/api"
+	"Media/internal/domain"
+	"Media/internal/infrastructure/server/utils/errorwrapper"
+	"errors"
+	"net/http"
+)
+
+// Errors of requests the handlers reject, and errors of the domain with the messages clients get instead.
+var (
+	errNotAuthenticated = domain.NewError(domain.KindUnauthenticated, "unauthenticated", "not authenticated")
+	errInvalidID        = domain.NewError(domain.KindBadRequest, "invalid_id", "invalid id")
+	errInvalidAuthorID  = domain.NewError(domain.KindBadRequest, "invalid_author_id", "invalid author_id")
+	errInvalidForm      = domain.NewError(domain.KindBadRequest, "invalid_form", "invalid multipart form")
+	errMissingFile      = domain.NewError(domain.KindBadRequest, "missing_file", "no file in the form")
+	errFileNotFound     = domain.NewError(domain.KindNotFound, "not_found", "file not found")
+	errNotAuthor        = domain.NewError(domain.KindForbidden, "forbidden", "only the author may modify the file")
+	errNotAdmin         = domain.NewError(domain.KindForbidden, "forbidden", "only admins may do this")
+	errImpersonation    = domain.NewError(domain.KindForbidden, "forbidden", "only admins may act on behalf of another user")
+	errUploadNotFound   = domain.NewError(domain.KindNotFound, "not_found", "upload not found")
+	errUploadExpired    = domain.NewError(domain.KindGone, "expired", "upload expired")
+	errWrongOffset      = domain.NewError(domain.KindConflict, "wrong_offset", "offset does not match the upload")
+)
+
+// writeDomainError writes the response of the errors of the domain clients are told about, and reports whether it did.
+// Internal errors are returned by the handlers, which log them.
+func writeDomainError(w http.ResponseWriter, err error) bool {
+	if err == nil || domain.KindOf(err) == domain.KindInternal {
+		return false
+	}
+	errorwrapper.WriteError(w, err)
+	return true
+}
+
+// writeParamError writes the response of the parameters of generated routes that cannot be parsed.
+func writeParamError(w http.ResponseWriter, r *http.Request, err error) {
+	var paramErr *api.InvalidParamFormatError
+	if errors.As(err, &paramErr) && paramErr.ParamName == "id" {
+		errorwrapper.WriteError(w, errInvalidID)
+		return
+	}
+	errorwrapper.WriteError(w, domain.NewError(domain.KindBadRequest, "invalid_parameter", err.Error()))
+}
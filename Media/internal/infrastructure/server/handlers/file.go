@@ -1,134 +1,515 @@
 package handlers
 
 import (
+	"Media/api"
 	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"Media/internal/infrastructure/server/middleware"
 	"Media/internal/infrastructure/server/utils/errorwrapper"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"io"
 	"log/slog"
-	"mime/multipart"
+	"mime"
 	"net/http"
+	"strconv"
 )
 
+// maxFieldSize is the maximum size of the form fields of uploads.
+const maxFieldSize = 1 << 10
+
 type FileHandler struct {
 	fuc    usecases.FileUseCaseInterface
+	vuc    usecases.VariantUseCaseInterface
+	luc    usecases.LifecycleUseCaseInterface
 	logger *slog.Logger
 }
 
-func NewFileHandler(fuc usecases.FileUseCaseInterface, logger *slog.Logger) *FileHandler {
+var _ api.ServerInterface = &FileHandler{}
+
+func NewFileHandler(fuc usecases.FileUseCaseInterface, vuc usecases.VariantUseCaseInterface, luc usecases.LifecycleUseCaseInterface, logger *slog.Logger) *FileHandler {
 	return &FileHandler{
 		fuc:    fuc,
+		vuc:    vuc,
+		luc:    luc,
 		logger: logger,
 	}
 }
 
-func (h *FileHandler) CreateFile(w http.ResponseWriter, r *http.Request) error {
+// CreateFile streams a multipart upload into the storage.
+// The author_id, visibility, shared_with, purpose and checksum fields must come before the file part, parts after the file are ignored.
+// The checksum is the hex encoded SHA-256 of the content, files with another content are not created.
+func (h *FileHandler) CreateFile(w http.ResponseWriter, r *http.Request) {
 	const op = "FileHandler.CreateFile"
 
-	err := r.ParseMultipartForm(32 << 20)
-	if err != nil {
-		return err
-	}
-
-	files, ok := r.MultipartForm.File["file"]
+	authorID, ok := middleware.GetUserID(r.Context())
 	if !ok {
-		return errors.New("file not found")
+		errorwrapper.WriteError(w, errNotAuthenticated)
+		return
 	}
 
-	file, err := files[0].Open()
+	reader, err := r.MultipartReader()
 	if err != nil {
-		h.logger.Error(op, slog.Any("error", err.Error()))
-		return err
+		errorwrapper.WriteError(w, errInvalidForm)
+		return
 	}
-	defer func(file multipart.File) {
-		err := file.Close()
+
+	var visibility, sharedWith, purpose, checksum string
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			errorwrapper.WriteError(w, errMissingFile)
+			return
+		}
 		if err != nil {
-			h.logger.Error(op, slog.Any("error", err.Error()))
+			h.writeError(w, op, err)
+			return
 		}
-	}(file)
 
-	rawAuthorID := r.MultipartForm.Value["author_id"]
-	if len(rawAuthorID) == 0 {
-		return errors.New("author_id not found")
+		switch part.FormName() {
+		case "author_id":
+			// Admins may upload on behalf of another user
+			rawAuthorID, err := io.ReadAll(io.LimitReader(part, maxFieldSize))
+			if err != nil {
+				h.writeError(w, op, err)
+				return
+			}
+			requestedID, err := uuid.Parse(string(rawAuthorID))
+			if err != nil {
+				errorwrapper.WriteError(w, errInvalidAuthorID)
+				return
+			}
+
+			if requestedID != authorID {
+				if !middleware.HasRole(r.Context(), middleware.RoleAdmin) {
+					errorwrapper.WriteWithError(w, http.StatusForbidden, "only admins may upload on behalf of another user")
+					return
+				}
+				h.logger.Info("uploading on behalf of another user", slog.Any("admin", authorID), slog.Any("author", requestedID))
+				authorID = requestedID
+			}
+		case "visibility", "shared_with", "purpose", "checksum":
+			value, err := io.ReadAll(io.LimitReader(part, maxFieldSize))
+			if err != nil {
+				h.writeError(w, op, err)
+				return
+			}
+			switch part.FormName() {
+			case "visibility":
+				visibility = string(value)
+			case "shared_with":
+				sharedWith = string(value)
+			case "checksum":
+				checksum = string(value)
+			default:
+				purpose = string(value)
+			}
+		case "file":
+			access, err := parseAccessFields(visibility, sharedWith)
+			if err != nil {
+				errorwrapper.WriteError(w, err)
+				return
+			}
+			dto := usecases.CreateFileDTO{
+				Name:     part.FileName(),
+				AuthorID: authorID,
+				Size:     -1,
+				Access:   access,
+				Purpose:  domain.Purpose(purpose),
+				Checksum: checksum,
+				Content:  part,
+			}
+			h.createFile(w, r, dto)
+			return
+		}
 	}
-	authorID, err := uuid.Parse(rawAuthorID[0])
+}
+
+func (h *FileHandler) createFile(w http.ResponseWriter, r *http.Request, dto usecases.CreateFileDTO) {
+	const op = "FileHandler.createFile"
+
+	id, err := h.fuc.CreateFile(r.Context(), dto)
 	if err != nil {
-		h.logger.Error(op, slog.Any("error", err.Error()))
-		return err
+		h.writeError(w, op, err)
+		return
 	}
-	dto := usecases.CreateFileDTO{
-		Name:     files[0].Filename,
-		AuthorID: authorID,
-		Size:     files[0].Size,
-		Content:  file,
+
+	h.writeJSON(w, op, http.StatusCreated, api.CreatedFile{Id: id})
+}
+
+// GetFile streams the content of a file to the client, anonymous clients can only read public files.
+// The variant query parameter selects a variant of an image file instead, like GetVariant.
+// Other users than the author get the original variant of image files, which has none of their metadata,
+// like the location of photos.
+func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request, id uuid.UUID, params api.GetFileParams) {
+	const op = "FileHandler.GetFile"
+
+	// Anonymous clients have no user ID
+	userID, _ := middleware.GetUserID(r.Context())
+
+	if params.Variant != nil {
+		h.serveVariant(w, r, userID, id, string(*params.Variant))
+		return
 	}
 
-	id, err := h.fuc.CreateFile(r.Context(), dto)
+	file, err := h.fuc.StatFile(r.Context(), userID, id)
 	if err != nil {
-		h.logger.Error(op, slog.Any("error", err.Error()))
-		return err
+		h.writeError(w, op, err)
+		return
+	}
+
+	if h.vuc.Processes(file) {
+		// The author and the other users get different content at the same URL
+		w.Header().Add("Vary", "Authorization")
+		if userID != file.AuthorID {
+			h.serveVariant(w, r, userID, id, domain.VariantOriginal)
+			return
+		}
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	w.Header().Set("Content-Type", "application/json")
-	_, err = w.Write([]byte(`{"id": "` + id.String() + `"}`))
+	h.serveContent(w, r, file, "attachment", func(opts domain.ReadOptions) (io.ReadCloser, error) {
+		return h.fuc.GetFile(r.Context(), userID, id, opts)
+	})
+}
+
+// HeadFile describes a file like GetFile, without its content.
+func (h *FileHandler) HeadFile(w http.ResponseWriter, r *http.Request, id uuid.UUID, params api.HeadFileParams) {
+	h.GetFile(w, r, id, api.GetFileParams(params))
+}
+
+// GetVariant streams a variant of an image file to the client, like GetFile.
+func (h *FileHandler) GetVariant(w http.ResponseWriter, r *http.Request, id uuid.UUID, name api.VariantName) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	h.serveVariant(w, r, userID, id, string(name))
+}
 
+// HeadVariant describes a variant of an image file like GetVariant, without its content.
+func (h *FileHandler) HeadVariant(w http.ResponseWriter, r *http.Request, id uuid.UUID, name api.VariantName) {
+	h.GetVariant(w, r, id, name)
+}
+
+func (h *FileHandler) serveVariant(w http.ResponseWriter, r *http.Request, userID uuid.UUID, fileID uuid.UUID, name string) {
+	const op = "FileHandler.serveVariant"
+
+	file, err := h.vuc.StatVariant(r.Context(), userID, fileID, name)
 	if err != nil {
-		h.logger.Error(op, slog.Any("error", err.Error()))
-		return err
+		h.writeError(w, op, err)
+		return
 	}
 
-	return nil
+	// Variants are encoded by the service, so browsers can show them
+	h.serveContent(w, r, file, "inline", func(opts domain.ReadOptions) (io.ReadCloser, error) {
+		return h.vuc.GetVariant(r.Context(), userID, fileID, name, opts)
+	})
 }
 
-func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) error {
-	const op = "FileHandler.GetFile"
+// serveContent streams content described by file, opened with open, to the client.
+// It supports single byte ranges and conditional requests with the ETag and the modification time of the file.
+func (h *FileHandler) serveContent(w http.ResponseWriter, r *http.Request, file domain.File, disposition string, open func(opts domain.ReadOptions) (io.ReadCloser, error)) {
+	const op = "FileHandler.serveContent"
 	logger := h.logger.With("op", op)
 
-	id := chi.URLParam(r, "id")
+	w.Header().Set("Accept-Ranges", "bytes")
+	if file.ETag != "" {
+		w.Header().Set("ETag", quoteETag(file.ETag))
+	}
+	if !file.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", file.LastModified.UTC().Format(http.TimeFormat))
+	}
 
-	fileID, err := uuid.Parse(id)
-	if err != nil {
-		logger.Error("error while parsing file id", slog.Any("error", err.Error()))
-		return err
+	if notModified(r, file) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
 
-	file, err := h.fuc.GetFile(r.Context(), fileID)
-	if err != nil {
-		logger.Error("error while getting file", slog.Any("error", err.Error()))
-		return err
+	var err error
+	opts := domain.ReadOptions{ETag: file.ETag}
+	if rangeApplies(r, file) {
+		opts.Range, err = parseRange(r.Header.Get("Range"), file.Size)
+		if errors.Is(err, errUnsatisfiableRange) {
+			w.Header().Set("Content-Range", "bytes */"+strconv.FormatInt(file.Size, 10))
+			errorwrapper.WriteWithError(w, http.StatusRequestedRangeNotSatisfiable, err.Error())
+			return
+		}
+	}
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	// Browsers must not guess another type than the detected one
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Name}))
+	if digest := reprDigest(file.Checksum); digest != "" {
+		// Clients can check the content they got, whole or assembled from ranges
+		w.Header().Set("Repr-Digest", digest)
+	}
+
+	status, length := http.StatusOK, file.Size
+	if opts.Range != nil {
+		status, length = http.StatusPartialContent, opts.Range.Length()
+		w.Header().Set("Content-Range", "bytes "+strconv.FormatInt(opts.Range.Start, 10)+"-"+strconv.FormatInt(opts.Range.End, 10)+"/"+strconv.FormatInt(file.Size, 10))
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
 	}
 
-	w.Header().Set("Content-Disposition", "attachment; filename="+file.Name)
+	// The file may be replaced after it was checked, it is modified then
+	content, err := open(opts)
+	if err != nil {
+		h.writeError(w, op, err)
+		return
+	}
+	defer func(content io.ReadCloser) {
+		err := content.Close()
+		if err != nil {
+			logger.Error("error while closing file", slog.Any("error", err.Error()))
+		}
+	}(content)
 
-	_, err = w.Write(file.Content)
+	w.WriteHeader(status)
 
-	return nil
+	// The status is sent with the first write, errors after it can only be logged
+	if _, err := io.Copy(w, content); err != nil {
+		logger.Error("error while writing file", slog.Any("error", err.Error()))
+	}
 }
 
-func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request) error {
+// DeleteFile deletes a file of the user.
+func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
 	const op = "FileHandler.DeleteFile"
 
-	id := chi.URLParam(r, "id")
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteError(w, errNotAuthenticated)
+		return
+	}
+
+	if err := h.fuc.DeleteFile(r.Context(), userID, id); err != nil {
+		h.writeError(w, op, err)
+	}
+}
+
+// UpdateAccess replaces the visibility and the sharing list of a file of the user.
+func (h *FileHandler) UpdateAccess(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	const op = "FileHandler.UpdateAccess"
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteError(w, errNotAuthenticated)
+		return
+	}
+
+	var request accessRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4*maxFieldSize)).Decode(&request); err != nil {
+		errorwrapper.WriteWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
 
-	fileID, err := uuid.Parse(id)
+	file, err := h.fuc.UpdateAccess(r.Context(), userID, id, request.toDomain())
 	if err != nil {
+		h.writeError(w, op, err)
+		return
+	}
+
+	h.writeJSON(w, op, http.StatusOK, accessRequest{
+		Visibility: file.Access.Visibility,
+		SharedWith: file.Access.SharedWith,
+	})
+}
+
+// AddReference registers that owner uses a file of the user, so the file is kept.
+// Admins may reference the files of the user they act for.
+func (h *FileHandler) AddReference(w http.ResponseWriter, r *http.Request, id uuid.UUID, owner string, params api.AddReferenceParams) {
+	const op = "FileHandler.AddReference"
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteError(w, errNotAuthenticated)
+		return
+	}
+	if userID, ok = h.actingUserID(w, r, userID, params.AuthorId); !ok {
+		return
+	}
+
+	if err := h.luc.AddReference(r.Context(), userID, id, owner); err != nil {
+		h.writeError(w, op, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveReference removes a reference to a file of the user.
+func (h *FileHandler) RemoveReference(w http.ResponseWriter, r *http.Request, id uuid.UUID, owner string, params api.RemoveReferenceParams) {
+	const op = "FileHandler.RemoveReference"
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteError(w, errNotAuthenticated)
+		return
+	}
+	if userID, ok = h.actingUserID(w, r, userID, params.AuthorId); !ok {
+		return
+	}
+
+	if err := h.luc.RemoveReference(r.Context(), userID, id, owner); err != nil {
+		h.writeError(w, op, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// actingUserID returns the user the client acts as, which may be another user than the client only for admins.
+// It writes the error response and returns false if the client may not act as the requested user.
+func (h *FileHandler) actingUserID(w http.ResponseWriter, r *http.Request, userID uuid.UUID, requested *uuid.UUID) (uuid.UUID, bool) {
+	if requested == nil || *requested == userID {
+		return userID, true
+	}
+	if !middleware.HasRole(r.Context(), middleware.RoleAdmin) {
+		errorwrapper.WriteError(w, errImpersonation)
+		return uuid.Nil, false
+	}
+	h.logger.Info("acting on behalf of another user", slog.Any("admin", userID), slog.Any("user", *requested))
+	return *requested, true
+}
+
+// reprDigest returns the Repr-Digest header of content with a hex encoded SHA-256, empty if it is not known.
+func reprDigest(checksum string) string {
+	raw, err := hex.DecodeString(checksum)
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(raw) + ":"
+}
+
+// writeError writes the response of an error of a file, internal errors are logged.
+func (h *FileHandler) writeError(w http.ResponseWriter, op string, err error) {
+	if writeFileError(w, err) {
+		return
+	}
+	h.logger.Error(op, slog.Any("error", err.Error()))
+	errorwrapper.WriteError(w, err)
+}
+
+// writeJSON writes a JSON response, errors can only be logged once the status is sent.
+func (h *FileHandler) writeJSON(w http.ResponseWriter, op string, status int, body any) {
+	if err := writeJSON(w, status, body); err != nil {
 		h.logger.Error(op, slog.Any("error", err.Error()))
-		return err
+	}
+}
+
+// writeFileError writes the response of the errors of files and reports whether it was written.
+func writeFileError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		errorwrapper.WriteError(w, errFileNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		errorwrapper.WriteError(w, errNotAuthor)
+	default:
+		return writeDomainError(w, err)
+	}
+	return true
+}
+
+type fileListResponse struct {
+	Items      []domain.FileMeta `json:"items"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// ListFiles returns a page of the files the client can read, oldest first.
+// The files can be filtered by their author and by their creation time, pages are selected with a limit
+// and the cursor of the previous page.
+func (h *FileHandler) ListFiles(w http.ResponseWriter, r *http.Request, params api.ListFilesParams) {
+	const op = "FileHandler.ListFiles"
+
+	userID, _ := middleware.GetUserID(r.Context())
+	query := domain.MetaQuery{
+		ReaderID: userID,
+		AuthorID: params.AuthorId,
+		Since:    params.Since,
+		Until:    params.Until,
+	}
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		cursor, err := domain.ParseCursor(*params.Cursor)
+		if err != nil {
+			errorwrapper.WriteError(w, err)
+			return
+		}
+		query.After = &cursor
 	}
 
-	err = h.fuc.DeleteFile(r.Context(), fileID)
+	page, err := h.fuc.ListFiles(r.Context(), query)
 	if err != nil {
-		h.logger.Error(op, slog.Any("error", err.Error()))
-		return err
+		h.writeError(w, op, err)
+		return
+	}
+
+	response := fileListResponse{Items: page.Items}
+	if response.Items == nil {
+		response.Items = []domain.FileMeta{}
+	}
+	if page.Next != nil {
+		response.NextCursor = page.Next.String()
+	}
+	h.writeJSON(w, op, http.StatusOK, response)
+}
+
+// GetMeta returns the indexed metadata of a file the client can read.
+func (h *FileHandler) GetMeta(w http.ResponseWriter, r *http.Request, id uuid.UUID, params api.GetMetaParams) {
+	const op = "FileHandler.GetMeta"
+
+	userID, _ := middleware.GetUserID(r.Context())
+	userID, ok := h.actingUserID(w, r, userID, params.AuthorId)
+	if !ok {
+		return
+	}
+
+	meta, err := h.fuc.GetMeta(r.Context(), userID, id)
+	if err != nil {
+		h.writeError(w, op, err)
+		return
+	}
+
+	h.writeJSON(w, op, http.StatusOK, meta)
+}
+
+// GetUsage returns the storage used by the user and their quota.
+func (h *FileHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	const op = "FileHandler.GetUsage"
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteError(w, errNotAuthenticated)
+		return
+	}
+
+	usage, err := h.fuc.GetUsage(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, op, err)
+		return
 	}
 
-	return nil
+	h.writeJSON(w, op, http.StatusOK, usage)
 }
 
-func (h *FileHandler) RegisterRoutes(mux *chi.Mux) {
-	mux.Post("/files", errorwrapper.WrapWithError(h.CreateFile))
-	mux.Get("/files/{id}", errorwrapper.WrapWithError(h.GetFile))
-	mux.Delete("/files/{id}", errorwrapper.WrapWithError(h.DeleteFile))
+// RegisterRoutes registers the file routes of the OpenAPI specification.
+// All of them use the optional auth middleware to identify the client, the handlers of changes require a user.
+func (h *FileHandler) RegisterRoutes(mux *chi.Mux, optionalAuth func(http.Handler) http.Handler) {
+	api.HandlerWithOptions(h, api.ChiServerOptions{
+		BaseRouter:       mux,
+		Middlewares:      []api.MiddlewareFunc{optionalAuth},
+		ErrorHandlerFunc: writeParamError,
+	})
 }
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	contracts "Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	inmemoryRepo "Media/internal/infrastructure/repositories/inmemory"
+	"Media/internal/infrastructure/server/middleware"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// claimsParser is a TokenParser that accepts any token with fixed claims.
+type claimsParser jwt.MapClaims
+
+func (p claimsParser) Parse(tokenString string) (*jwt.Token, error) {
+	return &jwt.Token{Claims: jwt.MapClaims(p), Valid: true}, nil
+}
+
+// recordingFileUseCase records the files it is asked to create.
+type recordingFileUseCase struct {
+	contracts.FileUseCaseInterface
+	created []contracts.CreateFileDTO
+}
+
+func (uc *recordingFileUseCase) CreateFile(ctx context.Context, dto contracts.CreateFileDTO) (uuid.UUID, error) {
+	if _, err := io.Copy(io.Discard, dto.Content); err != nil {
+		return uuid.Nil, err
+	}
+	dto.Content = nil
+	uc.created = append(uc.created, dto)
+	return uuid.New(), nil
+}
+
+// uploadRequest returns a multipart upload of a file, with an author_id field if authorID is not empty.
+func uploadRequest(t *testing.T, authorID string) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if authorID != "" {
+		if err := form.WriteField("author_id", authorID); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	part, err := form.CreateFormFile("file", "file.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, _ = part.Write([]byte("content"))
+	if err := form.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/files", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer token")
+	return req
+}
+
+func TestFileHandler_CreateFile_Author(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name     string
+		roles    []interface{}
+		authorID string
+		status   int
+		want     uuid.UUID
+	}{
+		{name: "default author from the token", status: http.StatusCreated, want: userID},
+		{name: "own ID", authorID: userID.String(), status: http.StatusCreated, want: userID},
+		{name: "non-admin on behalf of another user", authorID: otherID.String(), status: http.StatusForbidden},
+		{name: "admin on behalf of another user", roles: []interface{}{middleware.RoleAdmin}, authorID: otherID.String(), status: http.StatusCreated, want: otherID},
+		{name: "invalid author ID", roles: []interface{}{middleware.RoleAdmin}, authorID: "admin", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := claimsParser{"sub": userID.String()}
+			if tt.roles != nil {
+				claims["roles"] = tt.roles
+			}
+			fuc := &recordingFileUseCase{}
+			h := NewFileHandler(fuc, nil, nil, slog.Default())
+			handler := middleware.Auth(claims, slog.Default())(http.HandlerFunc(h.CreateFile))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, uploadRequest(t, tt.authorID))
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status != http.StatusCreated {
+				assert.Empty(t, fuc.created)
+				return
+			}
+			if assert.Len(t, fuc.created, 1) {
+				assert.Equal(t, tt.want, fuc.created[0].AuthorID)
+			}
+		})
+	}
+}
+
+// pngHeader is the start of PNG images, content starting with it is detected as one.
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// strippingProcessor makes variants of PNG images that are their content without what follows the PNG header,
+// like metadata would be.
+type strippingProcessor struct{}
+
+func (strippingProcessor) Supports(contentType string) bool {
+	return contentType == "image/png"
+}
+
+func (strippingProcessor) MakeVariants(content io.Reader, specs []domain.VariantSpec) ([]usecases.VariantImage, error) {
+	images := make([]usecases.VariantImage, 0, len(specs))
+	for _, spec := range specs {
+		images = append(images, usecases.VariantImage{
+			Variant: domain.Variant{Name: spec.Name, ContentType: "image/png"},
+			Content: []byte(pngHeader),
+		})
+	}
+	return images, nil
+}
+
+// newFileServer returns the file routes over in-memory repositories, with the use case of their files.
+func newFileServer(t *testing.T) (http.Handler, *usecases.FileUseCase) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	files := inmemoryRepo.NewFileRepository()
+	limits := usecases.NewLimits(map[domain.Purpose]domain.Policy{domain.PurposeGeneric: {}}, 0, inmemoryRepo.NewUsageRepository(), logger)
+	vuc := usecases.NewVariantUseCase(inmemoryRepo.NewVariantRepository(), files, strippingProcessor{}, 0, 1, 1, logger)
+	fuc := usecases.NewFileUseCase(files, inmemoryRepo.NewBlobRepository(files), inmemoryRepo.NewMetaRepository(), limits, nopProcessor{}, logger)
+
+	mux := chi.NewRouter()
+	NewFileHandler(fuc, vuc, nil, logger).RegisterRoutes(mux, middleware.OptionalAuth(subjectParser{}, logger))
+	return mux, fuc
+}
+
+func TestFileHandler_GetFile_Image(t *testing.T) {
+	server, fuc := newFileServer(t)
+	authorID := uuid.New()
+
+	photo := pngHeader + "GPS 48.8584N 2.2945E"
+	id, err := fuc.CreateFile(context.Background(), contracts.CreateFileDTO{
+		Name:     "photo.png",
+		AuthorID: authorID,
+		Size:     int64(len(photo)),
+		Content:  strings.NewReader(photo),
+		Access:   domain.Access{Visibility: domain.VisibilityPublic},
+	})
+	require.NoError(t, err)
+	text := "hello"
+	textID, err := fuc.CreateFile(context.Background(), contracts.CreateFileDTO{
+		Name:     "hello.txt",
+		AuthorID: authorID,
+		Size:     int64(len(text)),
+		Content:  strings.NewReader(text),
+		Access:   domain.Access{Visibility: domain.VisibilityPublic},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		target string
+		userID uuid.UUID
+		want   string
+	}{
+		{name: "author gets the uploaded image", target: "/files/" + id.String(), userID: authorID, want: photo},
+		{name: "anonymous gets the original variant", target: "/files/" + id.String(), userID: uuid.Nil, want: pngHeader},
+		{name: "other user gets the original variant", target: "/files/" + id.String(), userID: uuid.New(), want: pngHeader},
+		{name: "author gets a variant on request", target: "/files/" + id.String() + "?variant=original", userID: authorID, want: pngHeader},
+		{name: "other files are served as they are", target: "/files/" + textID.String(), userID: uuid.Nil, want: text},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.userID != uuid.Nil {
+				r.Header.Set("Authorization", "Bearer "+tt.userID.String())
+			}
+
+			w := serve(server, r)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, tt.want, w.Body.String())
+			if tt.target == "/files/"+id.String() {
+				assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestFileHandler_OnBehalfOf(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	files := inmemoryRepo.NewFileRepository()
+	metas := inmemoryRepo.NewMetaRepository()
+	limits := usecases.NewLimits(map[domain.Purpose]domain.Policy{domain.PurposeGeneric: {}}, 0, inmemoryRepo.NewUsageRepository(), logger)
+	fuc := usecases.NewFileUseCase(files, inmemoryRepo.NewBlobRepository(files), metas, limits, nopProcessor{}, logger)
+	luc := usecases.NewLifecycleUseCase(fuc, metas, 0, 10, logger)
+	authorID, clientID := uuid.New(), uuid.New()
+	id, err := fuc.CreateFile(context.Background(), contracts.CreateFileDTO{
+		Name:     "private.txt",
+		AuthorID: authorID,
+		Size:     5,
+		Content:  strings.NewReader("hello"),
+		Access:   domain.Access{Visibility: domain.VisibilityPrivate},
+	})
+	require.NoError(t, err)
+	onBehalf := "?author_id=" + authorID.String()
+
+	tests := []struct {
+		name   string
+		roles  []interface{}
+		method string
+		target string
+		status int
+	}{
+		{name: "metadata of a private file of another user", method: http.MethodGet, target: "/files/" + id.String() + "/meta", status: http.StatusNotFound},
+		{name: "metadata on behalf of the author", method: http.MethodGet, target: "/files/" + id.String() + "/meta" + onBehalf, status: http.StatusForbidden},
+		{name: "admin reads the metadata on behalf of the author", roles: []interface{}{middleware.RoleAdmin}, method: http.MethodGet,
+			target: "/files/" + id.String() + "/meta" + onBehalf, status: http.StatusOK},
+		{name: "reference to a file of another user", method: http.MethodPut, target: "/files/" + id.String() + "/references/post:1", status: http.StatusNotFound},
+		{name: "reference on behalf of the author", method: http.MethodPut, target: "/files/" + id.String() + "/references/post:1" + onBehalf, status: http.StatusForbidden},
+		{name: "admin references on behalf of the author", roles: []interface{}{middleware.RoleAdmin}, method: http.MethodPut,
+			target: "/files/" + id.String() + "/references/post:1" + onBehalf, status: http.StatusNoContent},
+		{name: "admin removes the reference on behalf of the author", roles: []interface{}{middleware.RoleAdmin}, method: http.MethodDelete,
+			target: "/files/" + id.String() + "/references/post:1" + onBehalf, status: http.StatusNoContent},
+		{name: "invalid author ID", roles: []interface{}{middleware.RoleAdmin}, method: http.MethodPut,
+			target: "/files/" + id.String() + "/references/post:1?author_id=admin", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := claimsParser{"sub": clientID.String()}
+			if tt.roles != nil {
+				claims["roles"] = tt.roles
+			}
+			mux := chi.NewRouter()
+			NewFileHandler(fuc, nil, luc, logger).RegisterRoutes(mux, middleware.OptionalAuth(claims, logger))
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+			r.Header.Set("Authorization", "Bearer token")
+
+			w := serve(mux, r)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
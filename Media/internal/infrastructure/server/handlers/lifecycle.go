@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/infrastructure/server/middleware"
+	"Media/internal/infrastructure/server/utils/errorwrapper"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net/http"
+)
+
+// LifecycleHandler reports on the removal of the files nothing references.
+type LifecycleHandler struct {
+	luc    usecases.LifecycleUseCaseInterface
+	logger *slog.Logger
+}
+
+func NewLifecycleHandler(luc usecases.LifecycleUseCaseInterface, logger *slog.Logger) *LifecycleHandler {
+	return &LifecycleHandler{
+		luc:    luc,
+		logger: logger,
+	}
+}
+
+// GetReport returns what a collection of the files would do, without changing anything.
+// Only admins may see it, it lists the files of every user.
+func (h *LifecycleHandler) GetReport(w http.ResponseWriter, r *http.Request) error {
+	const op = "LifecycleHandler.GetReport"
+
+	if !middleware.HasRole(r.Context(), middleware.RoleAdmin) {
+		errorwrapper.WriteError(w, errNotAdmin)
+		return nil
+	}
+
+	report, err := h.luc.Collect(r.Context(), true)
+	if err != nil {
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	return writeJSON(w, http.StatusOK, report)
+}
+
+// RegisterRoutes registers the lifecycle routes, they require the auth middleware.
+func (h *LifecycleHandler) RegisterRoutes(mux *chi.Mux, auth func(http.Handler) http.Handler) {
+	mux.With(auth).Get("/lifecycle/report", errorwrapper.WrapWithError(h.GetReport))
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"Media/internal/infrastructure/server/middleware"
+	"Media/internal/infrastructure/server/utils/errorwrapper"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+// PresignHandler issues presigned URLs, so file content skips the service.
+type PresignHandler struct {
+	puc    usecases.PresignUseCaseInterface
+	logger *slog.Logger
+}
+
+func NewPresignHandler(puc usecases.PresignUseCaseInterface, logger *slog.Logger) *PresignHandler {
+	return &PresignHandler{
+		puc:    puc,
+		logger: logger,
+	}
+}
+
+type presignUploadRequest struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Purpose     string `json:"purpose"`
+	Checksum    string `json:"checksum"`
+	accessRequest
+}
+
+type presignResponse struct {
+	ID uuid.UUID `json:"id"`
+	*domain.PresignedURL
+}
+
+// PresignUpload returns the URL to PUT the content of a new file to.
+// The file is created by FinalizeUpload once the content is uploaded.
+func (h *PresignHandler) PresignUpload(w http.ResponseWriter, r *http.Request) error {
+	const op = "PresignHandler.PresignUpload"
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteWithError(w, http.StatusUnauthorized, "not authenticated")
+		return nil
+	}
+
+	var req presignUploadRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4*maxFieldSize)).Decode(&req); err != nil {
+		errorwrapper.WriteWithError(w, http.StatusBadRequest, "invalid request body")
+		return nil
+	}
+
+	upload, url, err := h.puc.PresignUpload(r.Context(), usecases.PresignUploadDTO{
+		Name:        req.Name,
+		AuthorID:    userID,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		Access:      req.toDomain(),
+		Purpose:     domain.Purpose(req.Purpose),
+		Checksum:    req.Checksum,
+	})
+	if err != nil {
+		if writePresignError(w, err) {
+			return nil
+		}
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	return writeJSON(w, http.StatusCreated, presignResponse{ID: upload.ID, PresignedURL: url})
+}
+
+// FinalizeUpload creates the file of a presigned upload once its content is uploaded.
+func (h *PresignHandler) FinalizeUpload(w http.ResponseWriter, r *http.Request) error {
+	const op = "PresignHandler.FinalizeUpload"
+
+	userID, _ := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorwrapper.WriteError(w, errUploadNotFound)
+		return nil
+	}
+
+	file, err := h.puc.FinalizeUpload(r.Context(), userID, id)
+	if err != nil {
+		if writePresignError(w, err) {
+			return nil
+		}
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	return writeJSON(w, http.StatusCreated, file)
+}
+
+// PresignDownload returns the URL to GET the content of a file from, if the client can read the file.
+func (h *PresignHandler) PresignDownload(w http.ResponseWriter, r *http.Request) error {
+	const op = "PresignHandler.PresignDownload"
+
+	userID, _ := middleware.GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return errInvalidID
+	}
+
+	url, err := h.puc.PresignDownload(r.Context(), userID, id)
+	if err != nil {
+		if writePresignError(w, err) {
+			return nil
+		}
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	return writeJSON(w, http.StatusOK, presignResponse{ID: id, PresignedURL: url})
+}
+
+// writePresignError writes the status of the errors of presigned uploads, and reports whether it did.
+func writePresignError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrForbidden):
+		errorwrapper.WriteError(w, domain.ErrNotFound)
+	default:
+		return writeDomainError(w, err)
+	}
+	return true
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, body any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(body)
+}
+
+// RegisterRoutes registers the presign routes.
+// Uploads require the auth middleware, downloads use the optional auth middleware to identify the reader.
+func (h *PresignHandler) RegisterRoutes(mux *chi.Mux, auth func(http.Handler) http.Handler, optionalAuth func(http.Handler) http.Handler) {
+	mux.With(auth).Post("/files/presigned", errorwrapper.WrapWithError(h.PresignUpload))
+	mux.With(auth).Post("/files/{id}/finalize", errorwrapper.WrapWithError(h.FinalizeUpload))
+	mux.With(optionalAuth).Get("/files/{id}/presigned", errorwrapper.WrapWithError(h.PresignDownload))
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"Media/api"
+	"net/http"
+)
+
+// Spec serves the OpenAPI document of the API.
+func Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(api.Spec)
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"Media/internal/infrastructure/server/middleware"
+	"Media/internal/infrastructure/server/utils/errorwrapper"
+	"encoding/base64"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Headers and values of the tus resumable upload protocol, see https://tus.io/protocols/resumable-upload
+const (
+	TusVersion       = "1.0.0"
+	TusExtensions    = "creation,creation-with-upload,termination,expiration"
+	tusContentType   = "application/offset+octet-stream"
+	tusResumableKey  = "Tus-Resumable"
+	uploadOffsetKey  = "Upload-Offset"
+	uploadLengthKey  = "Upload-Length"
+	uploadExpiresKey = "Upload-Expires"
+	uploadMetaKey    = "Upload-Metadata"
+)
+
+// UploadHandler implements the tus protocol for resumable uploads.
+// A finished upload becomes the file with the ID of the upload.
+type UploadHandler struct {
+	uuc     usecases.UploadUseCaseInterface
+	maxSize int64
+	logger  *slog.Logger
+}
+
+func NewUploadHandler(uuc usecases.UploadUseCaseInterface, maxSize int64, logger *slog.Logger) *UploadHandler {
+	return &UploadHandler{
+		uuc:     uuc,
+		maxSize: maxSize,
+		logger:  logger,
+	}
+}
+
+// Options describes the supported tus version and extensions.
+func (h *UploadHandler) Options(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Tus-Version", TusVersion)
+	w.Header().Set("Tus-Extension", TusExtensions)
+	if h.maxSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.maxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// CreateUpload creates an upload, optionally with its first content.
+func (h *UploadHandler) CreateUpload(w http.ResponseWriter, r *http.Request) error {
+	const op = "UploadHandler.CreateUpload"
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		errorwrapper.WriteWithError(w, http.StatusUnauthorized, "not authenticated")
+		return nil
+	}
+
+	size, err := strconv.ParseInt(r.Header.Get(uploadLengthKey), 10, 64)
+	if err != nil || size < 0 {
+		errorwrapper.WriteWithError(w, http.StatusBadRequest, "invalid "+uploadLengthKey)
+		return nil
+	}
+	if h.maxSize > 0 && size > h.maxSize {
+		errorwrapper.WriteWithError(w, http.StatusRequestEntityTooLarge, "upload is too large")
+		return nil
+	}
+
+	metadata := parseUploadMetadata(r.Header.Get(uploadMetaKey))
+	access, err := parseAccessFields(metadata["visibility"], metadata["shared_with"])
+	if err != nil {
+		return err
+	}
+
+	upload, err := h.uuc.CreateUpload(r.Context(), usecases.CreateUploadDTO{
+		Name:     metadata["filename"],
+		AuthorID: userID,
+		Size:     size,
+		Access:   access,
+		Purpose:  domain.Purpose(metadata["purpose"]),
+		Checksum: metadata["checksum"],
+	})
+	if writeDomainError(w, err) {
+		return nil
+	}
+	if err != nil {
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	// The creation-with-upload extension sends the first content with the creation request
+	if r.Header.Get("Content-Type") == tusContentType {
+		upload, err = h.uuc.WriteUpload(r.Context(), userID, upload.ID, 0, r.Body)
+		if err != nil {
+			h.logger.Error(op, slog.Any("error", err.Error()))
+			return err
+		}
+	}
+
+	w.Header().Set("Location", "/uploads/"+upload.ID.String())
+	w.Header().Set(uploadOffsetKey, strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set(uploadExpiresKey, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// GetUpload returns the offset of an upload, to resume it.
+func (h *UploadHandler) GetUpload(w http.ResponseWriter, r *http.Request) error {
+	upload, ok := h.upload(w, r)
+	if !ok {
+		return nil
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set(uploadOffsetKey, strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set(uploadLengthKey, strconv.FormatInt(upload.Size, 10))
+	w.Header().Set(uploadExpiresKey, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// PatchUpload appends content to an upload.
+func (h *UploadHandler) PatchUpload(w http.ResponseWriter, r *http.Request) error {
+	const op = "UploadHandler.PatchUpload"
+
+	if r.Header.Get("Content-Type") != tusContentType {
+		errorwrapper.WriteWithError(w, http.StatusUnsupportedMediaType, "content type must be "+tusContentType)
+		return nil
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(uploadOffsetKey), 10, 64)
+	if err != nil || offset < 0 {
+		errorwrapper.WriteWithError(w, http.StatusBadRequest, "invalid "+uploadOffsetKey)
+		return nil
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorwrapper.WriteError(w, errUploadNotFound)
+		return nil
+	}
+
+	upload, err := h.uuc.WriteUpload(r.Context(), userID, id, offset, r.Body)
+	if err != nil {
+		if writeUploadError(w, err) {
+			return nil
+		}
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	w.Header().Set(uploadOffsetKey, strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set(uploadExpiresKey, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// DeleteUpload cancels an upload.
+func (h *UploadHandler) DeleteUpload(w http.ResponseWriter, r *http.Request) error {
+	const op = "UploadHandler.DeleteUpload"
+
+	userID, _ := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorwrapper.WriteError(w, errUploadNotFound)
+		return nil
+	}
+
+	if err := h.uuc.DeleteUpload(r.Context(), userID, id); err != nil {
+		if writeUploadError(w, err) {
+			return nil
+		}
+		h.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// upload returns the upload of the request, or writes an error if it is not available.
+func (h *UploadHandler) upload(w http.ResponseWriter, r *http.Request) (*domain.Upload, bool) {
+	const op = "UploadHandler.upload"
+
+	userID, _ := middleware.GetUserID(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorwrapper.WriteError(w, errUploadNotFound)
+		return nil, false
+	}
+
+	upload, err := h.uuc.GetUpload(r.Context(), userID, id)
+	if err != nil {
+		if !writeUploadError(w, err) {
+			h.logger.Error(op, slog.Any("error", err.Error()))
+			errorwrapper.WriteError(w, err)
+		}
+		return nil, false
+	}
+	return upload, true
+}
+
+// writeUploadError writes the status of the errors of the tus protocol, and reports whether it did.
+func writeUploadError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrForbidden):
+		// Uploads of other users are not revealed
+		errorwrapper.WriteError(w, errUploadNotFound)
+	case errors.Is(err, domain.ErrExpired):
+		errorwrapper.WriteError(w, errUploadExpired)
+	case errors.Is(err, domain.ErrWrongOffset):
+		errorwrapper.WriteError(w, errWrongOffset)
+	default:
+		return writeDomainError(w, err)
+	}
+	return true
+}
+
+// parseUploadMetadata parses an Upload-Metadata header of comma separated keys and base64 encoded values.
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		key, rawValue, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(rawValue)
+		if err != nil {
+			continue
+		}
+		metadata[key] = string(value)
+	}
+	return metadata
+}
+
+// tusResumable checks the protocol version of requests and adds it to responses.
+func tusResumable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(tusResumableKey, TusVersion)
+		if r.Method != http.MethodOptions && r.Header.Get(tusResumableKey) != TusVersion {
+			w.Header().Set("Tus-Version", TusVersion)
+			errorwrapper.WriteWithError(w, http.StatusPreconditionFailed, "unsupported tus version")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RegisterRoutes registers the upload routes, all of them except OPTIONS require the auth middleware.
+func (h *UploadHandler) RegisterRoutes(mux *chi.Mux, auth func(http.Handler) http.Handler) {
+	mux.Route("/uploads", func(router chi.Router) {
+		router.Use(tusResumable)
+		router.Options("/", errorwrapper.WrapWithError(h.Options))
+
+		router.Group(func(router chi.Router) {
+			router.Use(auth)
+			router.Post("/", errorwrapper.WrapWithError(h.CreateUpload))
+			router.Head("/{id}", errorwrapper.WrapWithError(h.GetUpload))
+			router.Patch("/{id}", errorwrapper.WrapWithError(h.PatchUpload))
+			router.Delete("/{id}", errorwrapper.WrapWithError(h.DeleteUpload))
+		})
+	})
+}
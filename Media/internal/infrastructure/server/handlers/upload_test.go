@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"Media/internal/domain"
+	inmemoryRepo "Media/internal/infrastructure/repositories/inmemory"
+	"Media/internal/infrastructure/server/middleware"
+	"Media/internal/usecases"
+	"context"
+	"encoding/base64"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// subjectParser is a TokenParser that takes tokens for the ID of their user.
+type subjectParser struct{}
+
+func (subjectParser) Parse(tokenString string) (*jwt.Token, error) {
+	return &jwt.Token{Claims: jwt.MapClaims{"sub": tokenString}, Valid: true}, nil
+}
+
+type nopProcessor struct{}
+
+func (nopProcessor) Enqueue(file domain.File) {}
+
+func (nopProcessor) Remove(ctx context.Context, id uuid.UUID) error { return nil }
+
+// newTusServer returns the upload routes over in-memory repositories, with the repository of the created files.
+func newTusServer(t *testing.T, maxSize int64) (http.Handler, *inmemoryRepo.FileRepository) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	files := inmemoryRepo.NewFileRepository()
+	limits := usecases.NewLimits(map[domain.Purpose]domain.Policy{domain.PurposeGeneric: {}}, 0, inmemoryRepo.NewUsageRepository(), logger)
+	uuc := usecases.NewUploadUseCase(inmemoryRepo.NewUploadRepository(files), files, inmemoryRepo.NewBlobRepository(files),
+		inmemoryRepo.NewMetaRepository(), limits, nopProcessor{}, maxSize, time.Hour, logger)
+
+	mux := chi.NewRouter()
+	NewUploadHandler(uuc, maxSize, logger).RegisterRoutes(mux, middleware.Auth(subjectParser{}, logger))
+	return mux, files
+}
+
+// tusRequest returns a request of the tus protocol by the user, without authorization if userID is uuid.Nil.
+func tusRequest(method string, target string, userID uuid.UUID, headers map[string]string, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set(tusResumableKey, TusVersion)
+	if userID != uuid.Nil {
+		r.Header.Set("Authorization", "Bearer "+userID.String())
+	}
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	return r
+}
+
+func serve(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestUploadHandler_Options(t *testing.T) {
+	server, _ := newTusServer(t, 1024)
+
+	w := serve(server, httptest.NewRequest(http.MethodOptions, "/uploads", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, TusVersion, w.Header().Get("Tus-Version"))
+	assert.Equal(t, TusExtensions, w.Header().Get("Tus-Extension"))
+	assert.Equal(t, "1024", w.Header().Get("Tus-Max-Size"))
+}
+
+func TestUploadHandler_Upload(t *testing.T) {
+	server, files := newTusServer(t, 0)
+	userID := uuid.New()
+
+	metadata := "filename " + base64.StdEncoding.EncodeToString([]byte("hello.txt")) +
+		",visibility " + base64.StdEncoding.EncodeToString([]byte("public"))
+	w := serve(server, tusRequest(http.MethodPost, "/uploads", userID, map[string]string{
+		uploadLengthKey: "11",
+		uploadMetaKey:   metadata,
+	}, ""))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, TusVersion, w.Header().Get(tusResumableKey))
+	assert.Equal(t, "0", w.Header().Get(uploadOffsetKey))
+	location := w.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "/uploads/"), location)
+
+	w = serve(server, tusRequest(http.MethodPatch, location, userID, map[string]string{
+		"Content-Type":  tusContentType,
+		uploadOffsetKey: "0",
+	}, "hello "))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, "6", w.Header().Get(uploadOffsetKey))
+
+	w = serve(server, tusRequest(http.MethodHead, location, userID, nil, ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "6", w.Header().Get(uploadOffsetKey))
+	assert.Equal(t, "11", w.Header().Get(uploadLengthKey))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	w = serve(server, tusRequest(http.MethodPatch, location, userID, map[string]string{
+		"Content-Type":  tusContentType,
+		uploadOffsetKey: "0",
+	}, "hello "))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serve(server, tusRequest(http.MethodPatch, location, userID, map[string]string{
+		"Content-Type":  tusContentType,
+		uploadOffsetKey: "6",
+	}, "world"))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, "11", w.Header().Get(uploadOffsetKey))
+
+	// The done upload is the file with its ID
+	id := uuid.MustParse(strings.TrimPrefix(location, "/uploads/"))
+	file, err := files.StatFile(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "hello.txt", file.Name)
+	assert.Equal(t, userID, file.AuthorID)
+	assert.Equal(t, domain.VisibilityPublic, file.Access.Visibility)
+
+	w = serve(server, tusRequest(http.MethodHead, location, userID, nil, ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUploadHandler_CreationWithUpload(t *testing.T) {
+	server, files := newTusServer(t, 0)
+	userID := uuid.New()
+
+	w := serve(server, tusRequest(http.MethodPost, "/uploads", userID, map[string]string{
+		uploadLengthKey: "5",
+		"Content-Type":  tusContentType,
+	}, "hello"))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "5", w.Header().Get(uploadOffsetKey))
+	id := uuid.MustParse(strings.TrimPrefix(w.Header().Get("Location"), "/uploads/"))
+	_, err := files.StatFile(context.Background(), id)
+	assert.NoError(t, err)
+}
+
+func TestUploadHandler_Errors(t *testing.T) {
+	server, _ := newTusServer(t, 100)
+	userID := uuid.New()
+
+	w := serve(server, tusRequest(http.MethodPost, "/uploads", userID, map[string]string{uploadLengthKey: "10"}, ""))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	location := w.Header().Get("Location")
+
+	tests := []struct {
+		name   string
+		r      *http.Request
+		status int
+	}{
+		{
+			name:   "unsupported version",
+			r:      tusRequest(http.MethodHead, location, userID, map[string]string{tusResumableKey: "0.2.2"}, ""),
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "unauthenticated",
+			r:      tusRequest(http.MethodHead, location, uuid.Nil, nil, ""),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "upload of another user",
+			r:      tusRequest(http.MethodHead, location, uuid.New(), nil, ""),
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown upload",
+			r:      tusRequest(http.MethodHead, "/uploads/"+uuid.NewString(), userID, nil, ""),
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid length",
+			r:      tusRequest(http.MethodPost, "/uploads", userID, map[string]string{uploadLengthKey: "-1"}, ""),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "too large",
+			r:      tusRequest(http.MethodPost, "/uploads", userID, map[string]string{uploadLengthKey: "101"}, ""),
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "wrong content type",
+			r: tusRequest(http.MethodPatch, location, userID, map[string]string{
+				"Content-Type":  "text/plain",
+				uploadOffsetKey: "0",
+			}, "hello"),
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "invalid offset",
+			r: tusRequest(http.MethodPatch, location, userID, map[string]string{
+				"Content-Type":  tusContentType,
+				uploadOffsetKey: "start",
+			}, "hello"),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(server, tt.r)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+
+	w = serve(server, tusRequest(http.MethodDelete, location, uuid.New(), nil, ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(server, tusRequest(http.MethodDelete, location, userID, nil, ""))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(server, tusRequest(http.MethodHead, location, userID, nil, ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestParseUploadMetadata(t *testing.T) {
+	encode := func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		want   map[string]string
+	}{
+		{name: "empty", header: "", want: map[string]string{}},
+		{name: "pairs", header: "filename " + encode("cat.png") + ",purpose " + encode("avatar"),
+			want: map[string]string{"filename": "cat.png", "purpose": "avatar"}},
+		{name: "spaces around pairs", header: " filename " + encode("cat.png") + " , purpose " + encode("avatar"),
+			want: map[string]string{"filename": "cat.png", "purpose": "avatar"}},
+		{name: "key without value", header: "is_confidential", want: map[string]string{"is_confidential": ""}},
+		{name: "invalid base64 is skipped", header: "filename !!!,purpose " + encode("avatar"),
+			want: map[string]string{"purpose": "avatar"}},
+		{name: "empty pairs are skipped", header: ",,filename " + encode("a"), want: map[string]string{"filename": "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseUploadMetadata(tt.header))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"Media/internal/infrastructure/server/utils/errorwrapper"
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type key string
+
+const (
+	userIDKey key = "userID"
+	rolesKey  key = "roles"
+)
+
+// RoleAdmin is the role of users that may act on behalf of other users.
+const RoleAdmin = "admin"
+
+// TokenParser parses and verifies access tokens.
+type TokenParser interface {
+	Parse(tokenString string) (*jwt.Token, error)
+}
+
+// Auth is a middleware that checks if the user is authenticated.
+func Auth(tokenParser TokenParser, logger *slog.Logger) func(next http.Handler) http.Handler {
+	return auth(tokenParser, logger, true)
+}
+
+// OptionalAuth is a middleware that authenticates users with a token, and lets requests without one through.
+// Requests with an invalid token are rejected.
+func OptionalAuth(tokenParser TokenParser, logger *slog.Logger) func(next http.Handler) http.Handler {
+	return auth(tokenParser, logger, false)
+}
+
+func auth(tokenParser TokenParser, logger *slog.Logger, required bool) func(next http.Handler) http.Handler {
+	const op = "Auth"
+
+	logger = logger.With(slog.Any("op", op))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("Authorization")
+			if token == "" {
+				if !required {
+					next.ServeHTTP(w, r)
+					return
+				}
+				errorwrapper.WriteWithError(w, http.StatusUnauthorized, "no token provided")
+				return
+			}
+
+			if !strings.HasPrefix(token, "Bearer ") {
+				errorwrapper.WriteWithError(w, http.StatusUnauthorized, "invalid token format")
+				return
+			}
+			token = strings.TrimPrefix(token, "Bearer ")
+
+			parsedToken, err := tokenParser.Parse(token)
+			if err != nil {
+				logger.Error("Failed to parse token", slog.String("error", err.Error()))
+				errorwrapper.WriteWithError(w, http.StatusUnauthorized, "failed to parse token")
+				return
+			}
+
+			subject, err := parsedToken.Claims.GetSubject()
+			if err != nil {
+				logger.Error("Failed to get user ID from token", slog.String("error", err.Error()))
+				errorwrapper.WriteWithError(w, http.StatusUnauthorized, "failed to get user ID from token")
+				return
+			}
+			userID, err := uuid.Parse(subject)
+			if err != nil {
+				logger.Error("Failed to parse user ID", slog.String("error", err.Error()))
+				errorwrapper.WriteWithError(w, http.StatusUnauthorized, "invalid user ID in token")
+				return
+			}
+
+			// Add the user ID and roles to the request context
+			ctx := r.Context()
+			ctx = context.WithValue(ctx, userIDKey, userID)
+			ctx = context.WithValue(ctx, rolesKey, rolesFromClaims(parsedToken.Claims))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetUserID returns the user ID from the request context.
+// It is uuid.Nil and false for anonymous requests.
+func GetUserID(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
+	return userID, ok
+}
+
+// HasRole reports whether the user of the request context has a role.
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(rolesKey).([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// rolesFromClaims returns the roles in the "roles" claim.
+func rolesFromClaims(claims jwt.Claims) []string {
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+
+	rawRoles, _ := mapClaims["roles"].([]interface{})
+	roles := make([]string, 0, len(rawRoles))
+	for _, rawRole := range rawRoles {
+		if role, ok := rawRole.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tokensParser is a TokenParser that accepts only its tokens, with their claims.
+type tokensParser map[string]jwt.MapClaims
+
+func (p tokensParser) Parse(tokenString string) (*jwt.Token, error) {
+	claims, ok := p[tokenString]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &jwt.Token{Claims: claims, Valid: true}, nil
+}
+
+func TestAuth(t *testing.T) {
+	userID := uuid.New()
+	parser := tokensParser{
+		"user":       {"sub": userID.String()},
+		"admin":      {"sub": userID.String(), "roles": []interface{}{RoleAdmin, 1}},
+		"no subject": {},
+		"bad user":   {"sub": "user"},
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		required      bool
+		status        int
+		userID        uuid.UUID
+		admin         bool
+	}{
+		{name: "user", authorization: "Bearer user", required: true, status: http.StatusOK, userID: userID},
+		{name: "admin", authorization: "Bearer admin", required: true, status: http.StatusOK, userID: userID, admin: true},
+		{name: "no token", required: true, status: http.StatusUnauthorized},
+		{name: "not a bearer token", authorization: "Basic user", required: true, status: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer invalid", required: true, status: http.StatusUnauthorized},
+		{name: "no subject", authorization: "Bearer no subject", required: true, status: http.StatusUnauthorized},
+		{name: "subject is not a user ID", authorization: "Bearer bad user", required: true, status: http.StatusUnauthorized},
+		{name: "optional user", authorization: "Bearer user", status: http.StatusOK, userID: userID},
+		{name: "optional without token is anonymous", status: http.StatusOK},
+		{name: "optional with an invalid token", authorization: "Bearer invalid", status: http.StatusUnauthorized},
+		{name: "optional not a bearer token", authorization: "Basic user", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			mw := OptionalAuth(parser, logger)
+			if tt.required {
+				mw = Auth(parser, logger)
+			}
+
+			var (
+				called bool
+				gotID  uuid.UUID
+				gotOK  bool
+				admin  bool
+			)
+			handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				gotID, gotOK = GetUserID(r.Context())
+				admin = HasRole(r.Context(), RoleAdmin)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.status == http.StatusOK, called)
+			assert.Equal(t, tt.userID, gotID)
+			assert.Equal(t, tt.userID != uuid.Nil, gotOK)
+			assert.Equal(t, tt.admin, admin)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"Media/internal/infrastructure/server/handlers"
+	"Media/internal/infrastructure/server/middleware"
 	"context"
 	"errors"
 	"github.com/go-chi/chi/v5"
@@ -18,27 +19,62 @@ import (
 type Server struct {
 	address string
 	fuc     usecases.FileUseCaseInterface
+	vuc     usecases.VariantUseCaseInterface
+	uuc     usecases.UploadUseCaseInterface
+	puc     usecases.PresignUseCaseInterface // nil if the storage cannot presign URLs
+	luc     usecases.LifecycleUseCaseInterface
+	maxSize int64
+	tokens  middleware.TokenParser
 	logger  *slog.Logger
 	server  *http.Server
 }
 
-func NewServer(address string, fuc usecases.FileUseCaseInterface, logger *slog.Logger) *Server {
+func NewServer(address string, fuc usecases.FileUseCaseInterface, vuc usecases.VariantUseCaseInterface, uuc usecases.UploadUseCaseInterface, puc usecases.PresignUseCaseInterface, luc usecases.LifecycleUseCaseInterface, maxSize int64, tokens middleware.TokenParser, logger *slog.Logger) *Server {
 	return &Server{
 		address: address,
 		fuc:     fuc,
+		vuc:     vuc,
+		uuc:     uuc,
+		puc:     puc,
+		luc:     luc,
+		maxSize: maxSize,
+		tokens:  tokens,
 		logger:  logger,
 	}
 }
 
-func (s *Server) Start() error {
+// routes returns the router of the API, the routes follow the OpenAPI document served at /openapi.json.
+func (s *Server) routes() *chi.Mux {
 	router := chi.NewRouter()
+	router.Use(middleware.RequestID())
+
+	auth := middleware.Auth(s.tokens, s.logger)
+	optionalAuth := middleware.OptionalAuth(s.tokens, s.logger)
+
+	router.Get("/openapi.json", handlers.Spec)
+
+	fileHandler := handlers.NewFileHandler(s.fuc, s.vuc, s.luc, s.logger)
+	fileHandler.RegisterRoutes(router, optionalAuth)
+
+	uploadHandler := handlers.NewUploadHandler(s.uuc, s.maxSize, s.logger)
+	uploadHandler.RegisterRoutes(router, auth)
 
-	fileHandler := handlers.NewFileHandler(s.fuc, s.logger)
-	fileHandler.RegisterRoutes(router)
+	// Presigned URLs need a storage clients can reach directly
+	if s.puc != nil {
+		presignHandler := handlers.NewPresignHandler(s.puc, s.logger)
+		presignHandler.RegisterRoutes(router, auth, optionalAuth)
+	}
+
+	lifecycleHandler := handlers.NewLifecycleHandler(s.luc, s.logger)
+	lifecycleHandler.RegisterRoutes(router, auth)
 
+	return router
+}
+
+func (s *Server) Start() error {
 	s.server = &http.Server{
 		Addr:    s.address,
-		Handler: router,
+		Handler: s.routes(),
 	}
 
 	s.logger.Info("starting server", slog.Any("address", s.address))
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"Media/api"
+	"Media/internal/contracts/usecases"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type presignUseCase struct {
+	usecases.PresignUseCaseInterface
+}
+
+// TestRoutes_FollowSpec checks that every route of the API is described by the OpenAPI document.
+func TestRoutes_FollowSpec(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if !assert.NoError(t, json.Unmarshal(api.Spec, &spec)) {
+		return
+	}
+
+	s := NewServer("", nil, nil, nil, &presignUseCase{}, nil, 0, nil, slog.Default())
+
+	routes := 0
+	err := chi.Walk(s.routes(), func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes++
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		assert.Contains(t, spec.Paths, route)
+		assert.Contains(t, spec.Paths[route], strings.ToLower(method), route)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 22, routes)
+}
@@ -1,22 +1,94 @@
 package errorwrapper
 
 import (
+	"Media/internal/domain"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 )
 
-func WriteWithError(w http.ResponseWriter, status int, err string) {
+// requestIDHeader is the response header the request ID middleware sets, error responses repeat the ID in their body.
+const requestIDHeader = "X-Request-ID"
+
+// internalMessage replaces the messages of internal errors, which may tell more about the service than clients should know.
+const internalMessage = "internal server error"
+
+// Response is the body of error responses.
+type Response struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// statuses are the response statuses of the kinds of errors.
+var statuses = map[domain.Kind]int{
+	domain.KindInternal:            http.StatusInternalServerError,
+	domain.KindBadRequest:          http.StatusBadRequest,
+	domain.KindUnauthenticated:     http.StatusUnauthorized,
+	domain.KindForbidden:           http.StatusForbidden,
+	domain.KindNotFound:            http.StatusNotFound,
+	domain.KindConflict:            http.StatusConflict,
+	domain.KindLocked:              http.StatusLocked,
+	domain.KindGone:                http.StatusGone,
+	domain.KindPreconditionFailed:  http.StatusPreconditionFailed,
+	domain.KindTooLarge:            http.StatusRequestEntityTooLarge,
+	domain.KindUnsupportedType:     http.StatusUnsupportedMediaType,
+	domain.KindUnprocessable:       http.StatusUnprocessableEntity,
+	domain.KindInsufficientStorage: http.StatusInsufficientStorage,
+}
+
+// Status returns the response status of errors of a kind.
+func Status(kind domain.Kind) int {
+	if status, ok := statuses[kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// statusCode returns the code of error responses written with a status only, the kind with the status if there is one.
+func statusCode(status int) string {
+	for kind, kindStatus := range statuses {
+		if kindStatus == status {
+			return string(kind)
+		}
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// WriteWithError writes an error response with a status and a message, its code is derived from the status.
+func WriteWithError(w http.ResponseWriter, status int, message string) {
+	write(w, status, Response{Error: message, Code: statusCode(status)})
+}
+
+// WriteError writes the response of an error with the status of its kind and its code.
+// Errors that are not domain errors are internal, their message is not sent.
+func WriteError(w http.ResponseWriter, err error) {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == domain.KindInternal {
+		write(w, http.StatusInternalServerError, Response{Error: internalMessage, Code: string(domain.KindInternal)})
+		return
+	}
+	write(w, Status(domainErr.Kind), Response{Error: domainErr.Message, Code: domainErr.Code})
+}
+
+func write(w http.ResponseWriter, status int, response Response) {
+	response.RequestID = w.Header().Get(requestIDHeader)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_, _ = w.Write([]byte(`{"error":"` + err + `"}`))
+	_ = json.NewEncoder(w).Encode(response)
 }
 
 type HandlerWithError func(http.ResponseWriter, *http.Request) error
 
+// WrapWithError adapts a handler returning errors, the returned errors are written with WriteError.
+// Handlers log the internal errors they return.
 func WrapWithError(handlerFunc HandlerWithError) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := handlerFunc(w, r)
 		if err != nil {
-			WriteWithError(w, http.StatusInternalServerError, err.Error())
+			WriteError(w, err)
 		}
 	}
 }
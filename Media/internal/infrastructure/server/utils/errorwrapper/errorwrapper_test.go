@@ -0,0 +1,78 @@
+package errorwrapper
+
+import (
+	"Media/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serve(handler HandlerWithError) (*httptest.ResponseRecorder, Response) {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set(requestIDHeader, "request-1")
+	WrapWithError(handler)(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var response Response
+	_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func TestWrapWithError_DomainErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domain.ErrNotFound, http.StatusNotFound, "not_found"},
+		{fmt.Errorf("stat: %w", domain.ErrNotFound), http.StatusNotFound, "not_found"},
+		{domain.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+		{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{domain.ErrTooLarge, http.StatusRequestEntityTooLarge, "too_large"},
+		{domain.ErrQuota, http.StatusInsufficientStorage, "quota_exceeded"},
+		{domain.ErrChecksum, http.StatusUnprocessableEntity, "checksum_mismatch"},
+	}
+
+	for _, test := range tests {
+		recorder, response := serve(func(w http.ResponseWriter, r *http.Request) error {
+			return test.err
+		})
+
+		assert.Equal(t, test.status, recorder.Code, test.err.Error())
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, test.code, response.Code)
+		assert.Equal(t, "request-1", response.RequestID)
+	}
+}
+
+func TestWrapWithError_InternalErrors(t *testing.T) {
+	recorder, response := serve(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New(`dial tcp "minio:9000": connection refused`)
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "internal", response.Code)
+	assert.Equal(t, internalMessage, response.Error)
+}
+
+func TestWriteWithError(t *testing.T) {
+	recorder, response := serve(func(w http.ResponseWriter, r *http.Request) error {
+		WriteWithError(w, http.StatusBadRequest, `invalid "name"`)
+		return nil
+	})
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "bad_request", response.Code)
+	assert.Equal(t, `invalid "name"`, response.Error)
+
+	recorder, response = serve(func(w http.ResponseWriter, r *http.Request) error {
+		WriteWithError(w, http.StatusRequestedRangeNotSatisfiable, "range not satisfiable")
+		return nil
+	})
+
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, recorder.Code)
+	assert.Equal(t, "requested_range_not_satisfiable", response.Code)
+}
@@ -0,0 +1,87 @@
+package usecases_test
+
+import (
+	"Media/internal/domain"
+	inmemoryRepo "Media/internal/infrastructure/repositories/inmemory"
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// backend is the use cases of a Media service with in-memory repositories.
+type backend struct {
+	files     *inmemoryRepo.FileRepository
+	blobs     *inmemoryRepo.BlobRepository
+	metas     *inmemoryRepo.MetaRepository
+	usage     *inmemoryRepo.UsageRepository
+	uploads   *inmemoryRepo.UploadRepository
+	processor *recordingProcessor
+
+	limits        *usecases.Limits
+	fileUseCase   *usecases.FileUseCase
+	uploadUseCase *usecases.UploadUseCase
+}
+
+// newBackend returns a backend with the policies of the purposes and the quota of authors, policies allow only generic files if nil.
+func newBackend(t *testing.T, policies map[domain.Purpose]domain.Policy, quota int64) *backend {
+	t.Helper()
+	if policies == nil {
+		policies = map[domain.Purpose]domain.Policy{domain.PurposeGeneric: {}}
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	files := inmemoryRepo.NewFileRepository()
+	b := &backend{
+		files:     files,
+		blobs:     inmemoryRepo.NewBlobRepository(files),
+		metas:     inmemoryRepo.NewMetaRepository(),
+		usage:     inmemoryRepo.NewUsageRepository(),
+		uploads:   inmemoryRepo.NewUploadRepository(files),
+		processor: &recordingProcessor{},
+	}
+	b.limits = usecases.NewLimits(policies, quota, b.usage, logger)
+	b.fileUseCase = usecases.NewFileUseCase(b.files, b.blobs, b.metas, b.limits, b.processor, logger)
+	b.uploadUseCase = usecases.NewUploadUseCase(b.uploads, b.files, b.blobs, b.metas, b.limits, b.processor, 0, time.Hour, logger)
+	return b
+}
+
+// recordingProcessor records the files it is given instead of processing them.
+type recordingProcessor struct {
+	m        sync.Mutex
+	enqueued []uuid.UUID
+	removed  []uuid.UUID
+}
+
+func (p *recordingProcessor) Enqueue(file domain.File) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.enqueued = append(p.enqueued, file.ID)
+}
+
+func (p *recordingProcessor) Remove(ctx context.Context, id uuid.UUID) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.removed = append(p.removed, id)
+	return nil
+}
+
+// readAll reads the content of a file of the author.
+func (b *backend) readAll(t *testing.T, authorID uuid.UUID, id uuid.UUID) string {
+	t.Helper()
+	content, err := b.fileUseCase.GetFile(context.Background(), authorID, id, domain.ReadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return string(data)
+}
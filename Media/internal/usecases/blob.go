@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"hash"
+	"io"
+	"log/slog"
+)
+
+// BlobRepositoryInterface stores each distinct content once, as a blob addressed by its SHA-256,
+// with the number of files referencing it.
+type BlobRepositoryInterface interface {
+	// AddReference references a blob for one more file.
+	// The blob is created from the stored content of the file if it does not exist yet.
+	AddReference(ctx context.Context, checksum string, fileID uuid.UUID) error
+	// RemoveReference drops a reference to a blob, and removes the blob with its last reference.
+	RemoveReference(ctx context.Context, checksum string) error
+}
+
+// storeContent checks the stored content of a new file against the checksum the client expects,
+// and moves the content to the blob of its checksum.
+// The content is read once more for its checksum if checksum is empty. Files with another content are deleted.
+func storeContent(ctx context.Context, files FileRepositoryInterface, blobs BlobRepositoryInterface, file *domain.File, checksum string, expected string, logger *slog.Logger) error {
+	const op = "storeContent"
+
+	if checksum == "" {
+		var err error
+		checksum, err = checksumFile(ctx, files, file.ID)
+		if err != nil && expected != "" {
+			// Content that cannot be checked is not kept
+			deleteContent(ctx, files, file.ID, logger)
+			return err
+		}
+		if err != nil {
+			logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+			return nil
+		}
+	}
+
+	if expected != "" && checksum != expected {
+		logger.Info(op, slog.Any("file", file.ID), slog.Any("checksum", checksum), slog.Any("expected", expected))
+		deleteContent(ctx, files, file.ID, logger)
+		return domain.ErrChecksum
+	}
+
+	// The file keeps its own content when the blob cannot be used, it is only not shared then
+	if err := blobs.AddReference(ctx, checksum, file.ID); err != nil {
+		logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+		return nil
+	}
+	if err := files.LinkFile(ctx, file.ID, checksum); err != nil {
+		logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+		if err := blobs.RemoveReference(ctx, checksum); err != nil {
+			logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+		}
+		return nil
+	}
+
+	file.Checksum = checksum
+	return nil
+}
+
+// deleteContent deletes a file that cannot be created after its content was stored.
+func deleteContent(ctx context.Context, files FileRepositoryInterface, id uuid.UUID, logger *slog.Logger) {
+	if err := files.DeleteFile(ctx, id); err != nil {
+		logger.Error("deleteContent", slog.Any("file", id), slog.Any("error", err.Error()))
+	}
+}
+
+// checksumFile returns the hex encoded SHA-256 of the stored content of a file.
+func checksumFile(ctx context.Context, files FileRepositoryInterface, id uuid.UUID) (string, error) {
+	content, err := files.GetFile(ctx, id, domain.ReadOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifiedReader reads the whole content of a file and fails at its end if the content does not match its checksum.
+type verifiedReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	checksum string
+	id       uuid.UUID
+	logger   *slog.Logger
+}
+
+func newVerifiedReader(content io.ReadCloser, file domain.File, logger *slog.Logger) *verifiedReader {
+	return &verifiedReader{
+		ReadCloser: content,
+		hash:       sha256.New(),
+		checksum:   file.Checksum,
+		id:         file.ID,
+		logger:     logger,
+	}
+}
+
+func (r *verifiedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if checksum := hex.EncodeToString(r.hash.Sum(nil)); checksum != r.checksum {
+			// The client gets a broken response instead of content it cannot tell from the right one
+			r.logger.Error("verifiedReader.Read", slog.Any("file", r.id), slog.Any("checksum", checksum), slog.Any("expected", r.checksum))
+			return n, domain.ErrChecksum
+		}
+	}
+	return n, err
+}
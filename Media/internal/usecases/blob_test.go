@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestVerifiedReader(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello world"))
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		content string
+		chunked bool
+		err     error
+	}{
+		{name: "matching", content: "hello world"},
+		{name: "matching in chunks", content: "hello world", chunked: true},
+		{name: "other content", content: "hello there", err: domain.ErrChecksum},
+		{name: "other content in chunks", content: "hello there", chunked: true, err: domain.ErrChecksum},
+		{name: "truncated", content: "hello", err: domain.ErrChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var content io.Reader = strings.NewReader(tt.content)
+			if tt.chunked {
+				content = iotest.OneByteReader(content)
+			}
+			file := domain.File{ID: uuid.New(), Checksum: checksum}
+			reader := newVerifiedReader(io.NopCloser(content), file, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			// The content is read whole, and fails only at its end
+			got, err := io.ReadAll(reader)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.content, string(got))
+		})
+	}
+}
+
+func TestVerifiedReader_ReadError(t *testing.T) {
+	failure := errors.New("connection reset")
+	file := domain.File{ID: uuid.New(), Checksum: strings.Repeat("0", 64)}
+	reader := newVerifiedReader(io.NopCloser(iotest.ErrReader(failure)), file, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := io.ReadAll(reader)
+
+	assert.Equal(t, failure, err)
+	require.NoError(t, reader.Close())
+}
@@ -4,56 +4,243 @@ import (
 	"Media/internal/contracts/usecases"
 	"Media/internal/domain"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"github.com/google/uuid"
+	"io"
 	"log/slog"
+	"time"
 )
 
 type FileRepositoryInterface interface {
-	CreateFile(ctx context.Context, file domain.File) error
-	GetFile(ctx context.Context, id uuid.UUID) (domain.File, error)
+	// CreateFile stores a file with its content, file.Size is -1 if the size is not known in advance.
+	CreateFile(ctx context.Context, file domain.File, content io.Reader) error
+	StatFile(ctx context.Context, id uuid.UUID) (domain.File, error)
+	// GetFile returns the content of a file, the caller must close it.
+	GetFile(ctx context.Context, id uuid.UUID, opts domain.ReadOptions) (io.ReadCloser, error)
+	// UpdateFile replaces the name and the access control of a file.
+	UpdateFile(ctx context.Context, file domain.File) error
+	// LinkFile replaces the stored content of a file with a reference to the blob with the given checksum.
+	// Linked files are read from their blob.
+	LinkFile(ctx context.Context, id uuid.UUID, checksum string) error
 	DeleteFile(ctx context.Context, id uuid.UUID) error
+	// GetFilesModifiedBefore returns the IDs of the stored files last modified before the given time.
+	GetFilesModifiedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error)
 }
 
 var _ usecases.FileUseCaseInterface = &FileUseCase{}
 
 type FileUseCase struct {
 	Repository FileRepositoryInterface
+	Blobs      BlobRepositoryInterface
+	Metas      MetaRepositoryInterface
+	Limits     *Limits
+	Processor  FileProcessor
 	logger     *slog.Logger
 }
 
+// CreateFile stores a file with the content type detected from its content.
+// The content is checked against the policy of the purpose of the file and the quota of its author while it is stored,
+// and is hashed on the way, so it is kept once with the files of the same content.
 func (f *FileUseCase) CreateFile(ctx context.Context, dto usecases.CreateFileDTO) (uuid.UUID, error) {
-	id := uuid.New()
-	content := make([]byte, dto.Size)
-	_, err := dto.Content.Read(content)
+	if err := dto.Access.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+	expected, err := domain.ParseChecksum(dto.Checksum)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	allowance, err := f.Limits.allowance(ctx, dto.AuthorID, dto.Purpose, dto.Size)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	contentType, content, err := sniffContentType(dto.Content)
 	if err != nil {
 		return uuid.Nil, err
 	}
+	if !allowance.policy.AllowsContentType(contentType) {
+		return uuid.Nil, domain.ErrContentType
+	}
+
+	id := uuid.New()
 	file := domain.File{
-		ID:       id,
-		Name:     dto.Name,
-		Size:     dto.Size,
-		Content:  content,
-		AuthorID: dto.AuthorID,
+		ID:          id,
+		Name:        dto.Name,
+		Size:        dto.Size,
+		ContentType: contentType,
+		AuthorID:    dto.AuthorID,
+		Access:      dto.Access,
+	}
+	limited := allowance.limit(content)
+	hash := sha256.New()
+	err = f.Repository.CreateFile(ctx, file, io.TeeReader(limited, hash))
+	if limited.exceeded {
+		return uuid.Nil, limited.err
 	}
-	err = f.Repository.CreateFile(ctx, file)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
+	file.Size = limited.read
+	if err := storeContent(ctx, f.Repository, f.Blobs, &file, hex.EncodeToString(hash.Sum(nil)), expected, f.logger); err != nil {
+		return uuid.Nil, err
+	}
+
+	f.Limits.charge(ctx, dto.AuthorID, limited.read)
+
+	indexFile(ctx, f.Metas, file, dto.Purpose, f.logger)
+	f.Processor.Enqueue(file)
+
 	return id, nil
 }
 
-func (f *FileUseCase) GetFile(ctx context.Context, id uuid.UUID) (domain.File, error) {
-	return f.Repository.GetFile(ctx, id)
+// StatFile returns a file the user can read, files the user cannot read are not found.
+func (f *FileUseCase) StatFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.File, error) {
+	file, err := f.Repository.StatFile(ctx, id)
+	if err != nil {
+		return domain.File{}, err
+	}
+	if !file.CanRead(userID) {
+		return domain.File{}, domain.ErrNotFound
+	}
+	return file, nil
+}
+
+// GetFile returns the content of a file the user can read.
+// Whole contents of files with a blob are checked against its checksum while they are read.
+func (f *FileUseCase) GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID, opts domain.ReadOptions) (io.ReadCloser, error) {
+	file, err := f.StatFile(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := f.Repository.GetFile(ctx, id, opts)
+	if err != nil {
+		return nil, err
+	}
+	if file.Checksum != "" && opts.Range == nil {
+		return newVerifiedReader(content, file, f.logger), nil
+	}
+	return content, nil
+}
+
+func (f *FileUseCase) UpdateAccess(ctx context.Context, userID uuid.UUID, id uuid.UUID, access domain.Access) (domain.File, error) {
+	if err := access.Validate(); err != nil {
+		return domain.File{}, err
+	}
+
+	file, err := f.StatFile(ctx, userID, id)
+	if err != nil {
+		return domain.File{}, err
+	}
+	if !file.CanModify(userID) {
+		return domain.File{}, domain.ErrForbidden
+	}
+
+	file.Access = access
+	if err := f.Repository.UpdateFile(ctx, file); err != nil {
+		return domain.File{}, err
+	}
+
+	meta, err := f.Metas.GetMeta(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Files stored before they were indexed are indexed when they change
+		meta, err = domain.MetaOf(file, file.LastModified), nil
+	}
+	if err != nil {
+		return domain.File{}, err
+	}
+	meta.Access = access
+	meta.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	if err := f.Metas.SaveMeta(ctx, meta); err != nil {
+		return domain.File{}, err
+	}
+
+	return file, nil
+}
+
+func (f *FileUseCase) DeleteFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	file, err := f.StatFile(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !file.CanModify(userID) {
+		return domain.ErrForbidden
+	}
+	return f.remove(ctx, file)
+}
+
+// remove deletes a file with its metadata and variants, and releases its blob and the storage it used.
+func (f *FileUseCase) remove(ctx context.Context, file domain.File) error {
+	const op = "FileUseCase.remove"
+
+	if err := f.Repository.DeleteFile(ctx, file.ID); err != nil {
+		return err
+	}
+
+	f.Limits.charge(ctx, file.AuthorID, -file.Size)
+
+	// Other files may still share the blob, it is removed with the last of them
+	if file.Checksum != "" {
+		if err := f.Blobs.RemoveReference(ctx, file.Checksum); err != nil {
+			f.logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+		}
+	}
+
+	if err := f.Metas.DeleteMeta(ctx, file.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		f.logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+	}
+
+	// The file is gone either way, what is left of it only takes space
+	if err := f.Processor.Remove(ctx, file.ID); err != nil {
+		f.logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+	}
+
+	return nil
+}
+
+// GetMeta returns the indexed metadata of a file the user can read.
+// Files stored before they were indexed get metadata from the storage.
+func (f *FileUseCase) GetMeta(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.FileMeta, error) {
+	meta, err := f.Metas.GetMeta(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		file, err := f.StatFile(ctx, userID, id)
+		if err != nil {
+			return domain.FileMeta{}, err
+		}
+		return domain.MetaOf(file, file.LastModified), nil
+	}
+	if err != nil {
+		return domain.FileMeta{}, err
+	}
+	if !meta.CanRead(userID) {
+		return domain.FileMeta{}, domain.ErrNotFound
+	}
+	return meta, nil
+}
+
+// ListFiles returns a page of the indexed files the reader of the query can read.
+func (f *FileUseCase) ListFiles(ctx context.Context, query domain.MetaQuery) (domain.MetaPage, error) {
+	if err := query.Validate(); err != nil {
+		return domain.MetaPage{}, err
+	}
+	return f.Metas.ListMeta(ctx, query)
 }
 
-func (f *FileUseCase) DeleteFile(ctx context.Context, id uuid.UUID) error {
-	return f.Repository.DeleteFile(ctx, id)
+func (f *FileUseCase) GetUsage(ctx context.Context, userID uuid.UUID) (domain.Usage, error) {
+	return f.Limits.GetUsage(ctx, userID)
 }
 
-func NewFileUseCase(repository FileRepositoryInterface, logger *slog.Logger) *FileUseCase {
+func NewFileUseCase(repository FileRepositoryInterface, blobs BlobRepositoryInterface, metas MetaRepositoryInterface, limits *Limits, processor FileProcessor, logger *slog.Logger) *FileUseCase {
 	return &FileUseCase{
 		Repository: repository,
+		Blobs:      blobs,
+		Metas:      metas,
+		Limits:     limits,
+		Processor:  processor,
 		logger:     logger,
 	}
 }
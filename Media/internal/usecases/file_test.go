@@ -0,0 +1,136 @@
+package usecases_test
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createFile(t *testing.T, b *backend, authorID uuid.UUID, access domain.Access) uuid.UUID {
+	t.Helper()
+	id, err := b.fileUseCase.CreateFile(context.Background(), usecases.CreateFileDTO{
+		Name:     "file.txt",
+		AuthorID: authorID,
+		Size:     5,
+		Content:  strings.NewReader("hello"),
+		Access:   access,
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestFileUseCase_Unreadable(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	friendID := uuid.New()
+	otherID := uuid.New()
+
+	private := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityPrivate})
+	shared := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityShared, SharedWith: []uuid.UUID{friendID}})
+	public := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityPublic})
+
+	tests := []struct {
+		name   string
+		id     uuid.UUID
+		userID uuid.UUID
+		err    error
+	}{
+		{name: "private by the author", id: private, userID: authorID},
+		{name: "private by another user", id: private, userID: otherID, err: domain.ErrNotFound},
+		{name: "private by anonymous", id: private, userID: uuid.Nil, err: domain.ErrNotFound},
+		{name: "shared by a user it is shared with", id: shared, userID: friendID},
+		{name: "shared by another user", id: shared, userID: otherID, err: domain.ErrNotFound},
+		{name: "public by anonymous", id: public, userID: uuid.Nil},
+		{name: "unknown file", id: uuid.New(), userID: authorID, err: domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Files the user cannot read are not found, so their existence is not disclosed
+			_, err := b.fileUseCase.StatFile(ctx, tt.userID, tt.id)
+			assert.ErrorIs(t, err, tt.err)
+
+			content, err := b.fileUseCase.GetFile(ctx, tt.userID, tt.id, domain.ReadOptions{})
+			assert.ErrorIs(t, err, tt.err)
+			if err == nil {
+				_ = content.Close()
+			}
+
+			_, err = b.fileUseCase.GetMeta(ctx, tt.userID, tt.id)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestFileUseCase_Unmodifiable(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	friendID := uuid.New()
+	id := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityShared, SharedWith: []uuid.UUID{friendID}})
+
+	// Readers that are not the author cannot change the file
+	_, err := b.fileUseCase.UpdateAccess(ctx, friendID, id, domain.Access{Visibility: domain.VisibilityPublic})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.ErrorIs(t, b.fileUseCase.DeleteFile(ctx, friendID, id), domain.ErrForbidden)
+
+	// Users that cannot read it do not find it
+	assert.ErrorIs(t, b.fileUseCase.DeleteFile(ctx, uuid.New(), id), domain.ErrNotFound)
+
+	file, err := b.fileUseCase.UpdateAccess(ctx, authorID, id, domain.Access{Visibility: domain.VisibilityPrivate})
+	require.NoError(t, err)
+	assert.Equal(t, domain.VisibilityPrivate, file.Access.Visibility)
+
+	_, err = b.fileUseCase.StatFile(ctx, friendID, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	require.NoError(t, b.fileUseCase.DeleteFile(ctx, authorID, id))
+	_, err = b.fileUseCase.StatFile(ctx, authorID, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestFileUseCase_CreateFile_Checksum(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	sum := sha256.Sum256([]byte("hello"))
+	checksum := hex.EncodeToString(sum[:])
+	create := func(checksum string) (uuid.UUID, error) {
+		return b.fileUseCase.CreateFile(ctx, usecases.CreateFileDTO{
+			Name:     "file.txt",
+			AuthorID: authorID,
+			Size:     5,
+			Content:  strings.NewReader("hello"),
+			Checksum: checksum,
+			Access:   domain.Access{Visibility: domain.VisibilityPrivate},
+		})
+	}
+
+	// Content that does not match the checksum of the client is not kept nor charged
+	_, err := create(strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, domain.ErrChecksum)
+	ids, err := b.files.GetFilesModifiedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Used)
+	assert.Empty(t, b.processor.enqueued)
+
+	// Matching content is stored in the blob of its checksum
+	id, err := create(checksum)
+	require.NoError(t, err)
+	file, err := b.fileUseCase.StatFile(ctx, authorID, id)
+	require.NoError(t, err)
+	assert.Equal(t, checksum, file.Checksum)
+	assert.Equal(t, "hello", b.readAll(t, authorID, id))
+}
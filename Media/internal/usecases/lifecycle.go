@@ -0,0 +1,226 @@
+package usecases
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// ReferenceRepositoryInterface keeps the references to indexed files, and when files lost their last reference.
+type ReferenceRepositoryInterface interface {
+	// AddReference registers a reference to a file, registering it again changes nothing.
+	AddReference(ctx context.Context, ref domain.Reference) error
+	// RemoveReference removes a reference to a file, the file is released if it was its last reference.
+	RemoveReference(ctx context.Context, fileID uuid.UUID, owner string) error
+	// GetUnreferenced returns up to limit indexed files of a purpose without references,
+	// created or released before the given time, oldest first.
+	GetUnreferenced(ctx context.Context, purpose domain.Purpose, before time.Time, limit int) ([]domain.FileMeta, error)
+}
+
+var _ usecases.LifecycleUseCaseInterface = &LifecycleUseCase{}
+
+// LifecycleUseCase keeps the files something uses and removes the others.
+//
+// Services using files register references to them. Files without references are removed once the retention
+// of their purpose has passed, counted from their creation or from the removal of their last reference,
+// and never before the grace period, which gives clients time to reference the files they just uploaded.
+// Files of purposes without retention are kept.
+type LifecycleUseCase struct {
+	Files       *FileUseCase
+	References  ReferenceRepositoryInterface
+	GracePeriod time.Duration
+	BatchSize   int // files of a purpose removed per collection
+	logger      *slog.Logger
+}
+
+func (l *LifecycleUseCase) AddReference(ctx context.Context, userID uuid.UUID, id uuid.UUID, owner string) error {
+	if err := domain.ValidateOwner(owner); err != nil {
+		return err
+	}
+	file, err := l.modifiableFile(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	// References are kept with the metadata, files stored before they were indexed are indexed first
+	if _, err := l.Files.Metas.GetMeta(ctx, id); errors.Is(err, domain.ErrNotFound) {
+		if err := l.Files.Metas.SaveMeta(ctx, domain.MetaOf(file, file.LastModified)); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return l.References.AddReference(ctx, domain.Reference{
+		FileID:    id,
+		Owner:     owner,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	})
+}
+
+func (l *LifecycleUseCase) RemoveReference(ctx context.Context, userID uuid.UUID, id uuid.UUID, owner string) error {
+	if err := domain.ValidateOwner(owner); err != nil {
+		return err
+	}
+	if _, err := l.modifiableFile(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := l.References.RemoveReference(ctx, id, owner); errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrNotReferenced
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// modifiableFile returns a file the user can modify.
+func (l *LifecycleUseCase) modifiableFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.File, error) {
+	file, err := l.Files.StatFile(ctx, userID, id)
+	if err != nil {
+		return domain.File{}, err
+	}
+	if !file.CanModify(userID) {
+		return domain.File{}, domain.ErrForbidden
+	}
+	return file, nil
+}
+
+// Collect indexes the stored files without metadata older than the grace period, so their retention applies to them,
+// and removes up to BatchSize expired files of each purpose.
+// Files that cannot be indexed or removed are logged and left for the next collection.
+func (l *LifecycleUseCase) Collect(ctx context.Context, dryRun bool) (domain.LifecycleReport, error) {
+	const op = "LifecycleUseCase.Collect"
+
+	now := time.Now().UTC()
+	report := domain.LifecycleReport{
+		DryRun:    dryRun,
+		Expired:   []domain.FileMeta{},
+		Unindexed: []uuid.UUID{},
+	}
+
+	// Files are stored before they are indexed, so files younger than the grace period may be still in creation
+	ids, err := l.Files.Repository.GetFilesModifiedBefore(ctx, now.Add(-l.GracePeriod))
+	if err != nil {
+		return domain.LifecycleReport{}, err
+	}
+	for _, id := range ids {
+		_, err := l.Files.Metas.GetMeta(ctx, id)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return domain.LifecycleReport{}, err
+		}
+
+		report.Unindexed = append(report.Unindexed, id)
+		if dryRun {
+			continue
+		}
+		if err := l.index(ctx, id); err != nil {
+			l.logger.Error(op, slog.Any("file", id), slog.Any("error", err.Error()))
+		}
+	}
+
+	purposes := make([]domain.Purpose, 0, len(l.Files.Limits.Policies))
+	for purpose := range l.Files.Limits.Policies {
+		purposes = append(purposes, purpose)
+	}
+	slices.Sort(purposes)
+
+	for _, purpose := range purposes {
+		retention := l.Files.Limits.Policies[purpose].Retention
+		if retention <= 0 {
+			continue
+		}
+
+		metas, err := l.References.GetUnreferenced(ctx, purpose, now.Add(-max(retention, l.GracePeriod)), l.BatchSize)
+		if err != nil {
+			return domain.LifecycleReport{}, err
+		}
+		for _, meta := range metas {
+			if !dryRun {
+				if err := l.remove(ctx, meta); err != nil {
+					l.logger.Error(op, slog.Any("file", meta.ID), slog.Any("error", err.Error()))
+					continue
+				}
+			}
+			report.Expired = append(report.Expired, meta)
+			report.Freed += meta.Size
+		}
+	}
+
+	return report, nil
+}
+
+// index saves the metadata of a stored file without it, as a generic file created when it was last modified.
+func (l *LifecycleUseCase) index(ctx context.Context, id uuid.UUID) error {
+	file, err := l.Files.Repository.StatFile(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Deleted since it was listed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return l.Files.Metas.SaveMeta(ctx, domain.MetaOf(file, file.LastModified))
+}
+
+// remove removes an expired file, the metadata of files that are not stored anymore is only dropped from the index.
+func (l *LifecycleUseCase) remove(ctx context.Context, meta domain.FileMeta) error {
+	file, err := l.Files.Repository.StatFile(ctx, meta.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		if err := l.Files.Metas.DeleteMeta(ctx, meta.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return l.Files.remove(ctx, file)
+}
+
+// Run collects the files periodically until ctx is done, dry runs only log what would be done.
+func (l *LifecycleUseCase) Run(ctx context.Context, interval time.Duration, dryRun bool) {
+	const op = "LifecycleUseCase.Run"
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := l.Collect(ctx, dryRun)
+			if err != nil {
+				l.logger.Error(op, slog.Any("error", err.Error()))
+				continue
+			}
+			if dryRun {
+				for _, meta := range report.Expired {
+					l.logger.Info(op, slog.Any("expired", meta.ID), slog.Any("purpose", meta.Purpose), slog.Any("size", meta.Size))
+				}
+			}
+			if len(report.Expired) > 0 || len(report.Unindexed) > 0 {
+				l.logger.Info(op, slog.Any("dry_run", dryRun), slog.Any("expired", len(report.Expired)),
+					slog.Any("unindexed", len(report.Unindexed)), slog.Any("freed", report.Freed))
+			}
+		}
+	}
+}
+
+func NewLifecycleUseCase(files *FileUseCase, references ReferenceRepositoryInterface, gracePeriod time.Duration, batchSize int, logger *slog.Logger) *LifecycleUseCase {
+	return &LifecycleUseCase{
+		Files:       files,
+		References:  references,
+		GracePeriod: gracePeriod,
+		BatchSize:   batchSize,
+		logger:      logger,
+	}
+}
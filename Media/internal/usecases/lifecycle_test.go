@@ -0,0 +1,212 @@
+package usecases_test
+
+import (
+	contracts "Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newLifecycle returns the lifecycle of the files of a backend, avatars are kept for an hour and attachments for a day.
+func newLifecycle(t *testing.T, gracePeriod time.Duration) (*backend, *usecases.LifecycleUseCase) {
+	t.Helper()
+	b := newBackend(t, map[domain.Purpose]domain.Policy{
+		domain.PurposeGeneric:    {},
+		domain.PurposeAvatar:     {Retention: time.Hour},
+		domain.PurposeAttachment: {Retention: 24 * time.Hour},
+	}, 0)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return b, usecases.NewLifecycleUseCase(b.fileUseCase, b.metas, gracePeriod, 10, logger)
+}
+
+// createPurposeFile creates a file of the purpose, indexed as created age ago.
+func createPurposeFile(t *testing.T, b *backend, authorID uuid.UUID, purpose domain.Purpose, age time.Duration) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	id, err := b.fileUseCase.CreateFile(ctx, contracts.CreateFileDTO{
+		Name:     "file.txt",
+		AuthorID: authorID,
+		Size:     5,
+		Content:  strings.NewReader("hello"),
+		Access:   domain.Access{Visibility: domain.VisibilityPrivate},
+		Purpose:  purpose,
+	})
+	require.NoError(t, err)
+
+	meta, err := b.metas.GetMeta(ctx, id)
+	require.NoError(t, err)
+	meta.CreatedAt = meta.CreatedAt.Add(-age)
+	require.NoError(t, b.metas.SaveMeta(ctx, meta))
+	return id
+}
+
+func expiredIDs(report domain.LifecycleReport) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(report.Expired))
+	for _, meta := range report.Expired {
+		ids = append(ids, meta.ID)
+	}
+	return ids
+}
+
+func TestLifecycleUseCase_Collect_Retention(t *testing.T) {
+	b, luc := newLifecycle(t, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	oldAvatar := createPurposeFile(t, b, authorID, domain.PurposeAvatar, 2*time.Hour)
+	newAvatar := createPurposeFile(t, b, authorID, domain.PurposeAvatar, 30*time.Minute)
+	attachment := createPurposeFile(t, b, authorID, domain.PurposeAttachment, 2*time.Hour)
+	generic := createPurposeFile(t, b, authorID, domain.PurposeGeneric, 1000*time.Hour)
+
+	// Only the files past the retention of their purpose are removed, generic files have none
+	report, err := luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{oldAvatar}, expiredIDs(report))
+	assert.Equal(t, int64(5), report.Freed)
+	assert.Empty(t, report.Unindexed)
+
+	_, err = b.files.StatFile(ctx, oldAvatar)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = b.metas.GetMeta(ctx, oldAvatar)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Contains(t, b.processor.removed, oldAvatar)
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), usage.Used)
+
+	for _, id := range []uuid.UUID{newAvatar, attachment, generic} {
+		_, err := b.files.StatFile(ctx, id)
+		assert.NoError(t, err)
+	}
+}
+
+func TestLifecycleUseCase_Collect_GracePeriod(t *testing.T) {
+	b, luc := newLifecycle(t, 3*time.Hour)
+	authorID := uuid.New()
+	inGrace := createPurposeFile(t, b, authorID, domain.PurposeAvatar, 2*time.Hour)
+	pastGrace := createPurposeFile(t, b, authorID, domain.PurposeAvatar, 4*time.Hour)
+
+	// Files past their retention are kept until the grace period has passed too
+	report, err := luc.Collect(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{pastGrace}, expiredIDs(report))
+
+	_, err = b.files.StatFile(context.Background(), inGrace)
+	assert.NoError(t, err)
+}
+
+func TestLifecycleUseCase_Collect_Released(t *testing.T) {
+	b, luc := newLifecycle(t, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	luc.Files.Limits.Policies[domain.PurposeAvatar] = domain.Policy{Retention: 50 * time.Millisecond}
+	id := createPurposeFile(t, b, authorID, domain.PurposeAvatar, time.Hour)
+	owner := "user:" + authorID.String()
+
+	// Referenced files are kept whatever their age
+	require.NoError(t, luc.AddReference(ctx, authorID, id, owner))
+	report, err := luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Expired)
+
+	// The retention of a file that lost its last reference counts from then
+	require.NoError(t, luc.RemoveReference(ctx, authorID, id, owner))
+	report, err = luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Expired)
+
+	time.Sleep(60 * time.Millisecond)
+	report, err = luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, expiredIDs(report))
+	_, err = b.files.StatFile(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestLifecycleUseCase_Collect_DryRun(t *testing.T) {
+	b, luc := newLifecycle(t, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	expired := createPurposeFile(t, b, authorID, domain.PurposeAvatar, 2*time.Hour)
+	unindexed := uuid.New()
+	require.NoError(t, b.files.CreateFile(ctx, domain.File{ID: unindexed, AuthorID: authorID, Size: 5}, strings.NewReader("hello")))
+
+	// Dry runs report what would be done and leave everything as it is
+	report, err := luc.Collect(ctx, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []uuid.UUID{expired}, expiredIDs(report))
+	assert.Equal(t, []uuid.UUID{unindexed}, report.Unindexed)
+	assert.Equal(t, int64(5), report.Freed)
+
+	_, err = b.files.StatFile(ctx, expired)
+	assert.NoError(t, err)
+	_, err = b.metas.GetMeta(ctx, expired)
+	assert.NoError(t, err)
+	_, err = b.metas.GetMeta(ctx, unindexed)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Empty(t, b.processor.removed)
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), usage.Used)
+}
+
+func TestLifecycleUseCase_Collect_Unindexed(t *testing.T) {
+	b, luc := newLifecycle(t, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	legacy := uuid.New()
+	require.NoError(t, b.files.CreateFile(ctx, domain.File{ID: legacy, AuthorID: authorID, Size: 5}, strings.NewReader("hello")))
+
+	// Files stored before they were indexed become generic files, which are kept
+	report, err := luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{legacy}, report.Unindexed)
+	assert.Empty(t, report.Expired)
+
+	meta, err := b.metas.GetMeta(ctx, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PurposeGeneric, meta.Purpose)
+	assert.Equal(t, authorID, meta.AuthorID)
+
+	report, err = luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Unindexed)
+	_, err = b.files.StatFile(ctx, legacy)
+	assert.NoError(t, err)
+}
+
+func TestLifecycleUseCase_Collect_UnindexedInGrace(t *testing.T) {
+	b, luc := newLifecycle(t, time.Hour)
+	ctx := context.Background()
+	id := uuid.New()
+	require.NoError(t, b.files.CreateFile(ctx, domain.File{ID: id, AuthorID: uuid.New(), Size: 5}, strings.NewReader("hello")))
+
+	// Files younger than the grace period may be still in creation, they are not indexed yet
+	report, err := luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Unindexed)
+	_, err = b.metas.GetMeta(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestLifecycleUseCase_Collect_NotStored(t *testing.T) {
+	b, luc := newLifecycle(t, 0)
+	ctx := context.Background()
+	id := createPurposeFile(t, b, uuid.New(), domain.PurposeAvatar, 2*time.Hour)
+	require.NoError(t, b.files.DeleteFile(ctx, id))
+
+	// Metadata of expired files that are not stored anymore is dropped from the index
+	report, err := luc.Collect(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, expiredIDs(report))
+	_, err = b.metas.GetMeta(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"bufio"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// sniffLen is the number of first bytes content types are detected from.
+const sniffLen = 512
+
+// UsageRepositoryInterface tracks the storage used by authors.
+type UsageRepositoryInterface interface {
+	GetUsage(ctx context.Context, authorID uuid.UUID) (int64, error)
+	// AddUsage adds delta bytes, which may be negative, to the storage used by an author.
+	AddUsage(ctx context.Context, authorID uuid.UUID, delta int64) error
+}
+
+// Limits checks uploads against the policy of their purpose and the storage quota of their author.
+//
+// Resumable and presigned uploads reserve their declared size when they start, so uploads running at the same time
+// cannot overshoot the quota together. The reservation is released if the upload does not become a file.
+// Uploads of a single request may not declare their size, so they are limited to the quota left when they start
+// and charged when their files are created: such requests of an author running at the same time may overshoot
+// the quota by the size of all but one of them.
+type Limits struct {
+	Policies map[domain.Purpose]domain.Policy
+	Quota    int64 // bytes per author, not limited if 0
+	Usage    UsageRepositoryInterface
+	logger   *slog.Logger
+}
+
+// allowance is what an author may still upload for a purpose.
+type allowance struct {
+	policy    domain.Policy
+	remaining int64 // bytes left in the quota of the author, not limited if negative
+}
+
+// allowance returns what an author may upload for a purpose, size is -1 if it is not known in advance.
+func (l *Limits) allowance(ctx context.Context, authorID uuid.UUID, purpose domain.Purpose, size int64) (allowance, error) {
+	if purpose == "" {
+		purpose = domain.PurposeGeneric
+	}
+	policy, ok := l.Policies[purpose]
+	if !ok {
+		return allowance{}, domain.ErrPurpose
+	}
+	if size >= 0 && !policy.AllowsSize(size) {
+		return allowance{}, domain.ErrTooLarge
+	}
+
+	a := allowance{policy: policy, remaining: -1}
+	if l.Quota > 0 {
+		used, err := l.Usage.GetUsage(ctx, authorID)
+		if err != nil {
+			return allowance{}, err
+		}
+		a.remaining = max(l.Quota-used, 0)
+		if size > a.remaining {
+			return allowance{}, domain.ErrQuota
+		}
+	}
+
+	return a, nil
+}
+
+// limit returns a reader of content that fails once the content exceeds the allowance.
+func (a allowance) limit(content io.Reader) *limitedReader {
+	limited := &limitedReader{r: content, n: -1}
+	if a.policy.MaxSize > 0 {
+		limited.n, limited.err = a.policy.MaxSize, domain.ErrTooLarge
+	}
+	if a.remaining >= 0 && (limited.n < 0 || a.remaining < limited.n) {
+		limited.n, limited.err = a.remaining, domain.ErrQuota
+	}
+	return limited
+}
+
+// reserve charges the declared size of an upload to its author before its content is received.
+// It fails with domain.ErrQuota if the reservation would overshoot the quota.
+func (l *Limits) reserve(ctx context.Context, authorID uuid.UUID, size int64) (int64, error) {
+	if l.Quota <= 0 || size <= 0 {
+		return 0, nil
+	}
+
+	// The size is added before the quota is checked, so concurrent reservations see each other
+	if err := l.Usage.AddUsage(ctx, authorID, size); err != nil {
+		return 0, err
+	}
+	used, err := l.Usage.GetUsage(ctx, authorID)
+	if err == nil && used > l.Quota {
+		err = domain.ErrQuota
+	}
+	if err != nil {
+		l.charge(ctx, authorID, -size)
+		return 0, err
+	}
+	return size, nil
+}
+
+// charge adds the size of a created or deleted file to the storage used by its author.
+// The change of the file is done either way, so errors are logged instead of returned.
+func (l *Limits) charge(ctx context.Context, authorID uuid.UUID, delta int64) {
+	const op = "Limits.charge"
+
+	if delta == 0 {
+		return
+	}
+	if err := l.Usage.AddUsage(ctx, authorID, delta); err != nil {
+		l.logger.Error(op, slog.Any("author", authorID), slog.Any("delta", delta), slog.Any("error", err.Error()))
+	}
+}
+
+// GetUsage returns the storage used by an author.
+func (l *Limits) GetUsage(ctx context.Context, authorID uuid.UUID) (domain.Usage, error) {
+	used, err := l.Usage.GetUsage(ctx, authorID)
+	if err != nil {
+		return domain.Usage{}, err
+	}
+	return domain.Usage{Used: used, Quota: l.Quota}, nil
+}
+
+// NewLimits creates a new Limits, quota is not limited if 0.
+func NewLimits(policies map[domain.Purpose]domain.Policy, quota int64, usage UsageRepositoryInterface, logger *slog.Logger) *Limits {
+	return &Limits{
+		Policies: policies,
+		Quota:    quota,
+		Usage:    usage,
+		logger:   logger,
+	}
+}
+
+// limitedReader reads up to n bytes and fails with err if there are more, n is not limited if negative.
+type limitedReader struct {
+	r        io.Reader
+	n        int64
+	err      error
+	read     int64 // number of bytes read
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, l.err
+	}
+
+	// One byte past the limit is read to tell whether the content exceeds it
+	if l.n >= 0 && int64(len(p)) > l.n-l.read+1 {
+		p = p[:l.n-l.read+1]
+	}
+
+	n, err := l.r.Read(p)
+	if l.n >= 0 && l.read+int64(n) > l.n {
+		l.exceeded = true
+		n = int(l.n - l.read)
+		err = l.err
+	}
+	l.read += int64(n)
+	return n, err
+}
+
+// sniffContentType detects the content type of content from its first bytes.
+// The returned reader reads the whole content, the detected bytes are buffered in it.
+func sniffContentType(content io.Reader) (string, *bufio.Reader, error) {
+	reader := bufio.NewReaderSize(content, sniffLen)
+	head, err := reader.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", nil, err
+	}
+
+	return http.DetectContentType(head), reader, nil
+}
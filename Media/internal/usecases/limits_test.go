@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// usageMap is a UsageRepositoryInterface of the usage in a map, it fails if err is set.
+type usageMap struct {
+	used map[uuid.UUID]int64
+	err  error
+}
+
+func (u *usageMap) GetUsage(ctx context.Context, authorID uuid.UUID) (int64, error) {
+	return u.used[authorID], u.err
+}
+
+func (u *usageMap) AddUsage(ctx context.Context, authorID uuid.UUID, delta int64) error {
+	if u.err != nil {
+		return u.err
+	}
+	u.used[authorID] = max(u.used[authorID]+delta, 0)
+	return nil
+}
+
+func newTestLimits(quota int64, used int64) (*Limits, uuid.UUID) {
+	authorID := uuid.New()
+	usage := &usageMap{used: map[uuid.UUID]int64{authorID: used}}
+	policies := map[domain.Purpose]domain.Policy{
+		domain.PurposeGeneric: {},
+		domain.PurposeAvatar:  {MaxSize: 100, ContentTypes: []string{"image/*"}},
+	}
+	return NewLimits(policies, quota, usage, slog.New(slog.NewTextHandler(io.Discard, nil))), authorID
+}
+
+func TestLimits_Allowance(t *testing.T) {
+	tests := []struct {
+		name      string
+		quota     int64
+		used      int64
+		purpose   domain.Purpose
+		size      int64
+		err       error
+		maxSize   int64
+		remaining int64
+	}{
+		{name: "generic by default", size: 10, maxSize: 0, remaining: -1},
+		{name: "unknown size", purpose: domain.PurposeAvatar, size: -1, maxSize: 100, remaining: -1},
+		{name: "size of the policy", purpose: domain.PurposeAvatar, size: 100, maxSize: 100, remaining: -1},
+		{name: "larger than the policy", purpose: domain.PurposeAvatar, size: 101, err: domain.ErrTooLarge},
+		{name: "unknown purpose", purpose: "banner", size: 10, err: domain.ErrPurpose},
+		{name: "remaining quota", quota: 100, used: 40, size: 60, remaining: 60},
+		{name: "unknown size with a quota", quota: 100, used: 40, size: -1, remaining: 60},
+		{name: "over the quota", quota: 100, used: 40, size: 61, err: domain.ErrQuota},
+		{name: "used beyond the quota", quota: 100, used: 140, size: -1, remaining: 0},
+		{name: "empty file beyond the quota", quota: 100, used: 140, size: 0, remaining: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, authorID := newTestLimits(tt.quota, tt.used)
+
+			got, err := limits.allowance(context.Background(), authorID, tt.purpose, tt.size)
+
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, tt.maxSize, got.policy.MaxSize)
+				assert.Equal(t, tt.remaining, got.remaining)
+			}
+		})
+	}
+}
+
+func TestLimits_Allowance_UsageError(t *testing.T) {
+	limits, authorID := newTestLimits(100, 0)
+	failure := errors.New("usage unavailable")
+	limits.Usage.(*usageMap).err = failure
+
+	_, err := limits.allowance(context.Background(), authorID, domain.PurposeGeneric, 10)
+
+	assert.Equal(t, failure, err)
+}
+
+func TestAllowance_Limit(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowance allowance
+		n         int64
+		err       error
+	}{
+		{name: "not limited", allowance: allowance{remaining: -1}, n: -1},
+		{name: "policy", allowance: allowance{policy: domain.Policy{MaxSize: 100}, remaining: -1}, n: 100, err: domain.ErrTooLarge},
+		{name: "quota", allowance: allowance{remaining: 50}, n: 50, err: domain.ErrQuota},
+		{name: "quota below the policy", allowance: allowance{policy: domain.Policy{MaxSize: 100}, remaining: 50}, n: 50, err: domain.ErrQuota},
+		{name: "policy below the quota", allowance: allowance{policy: domain.Policy{MaxSize: 100}, remaining: 500}, n: 100, err: domain.ErrTooLarge},
+		{name: "no quota left", allowance: allowance{policy: domain.Policy{MaxSize: 100}, remaining: 0}, n: 0, err: domain.ErrQuota},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limited := tt.allowance.limit(strings.NewReader(""))
+
+			assert.Equal(t, tt.n, limited.n)
+			assert.Equal(t, tt.err, limited.err)
+		})
+	}
+}
+
+// lengthsReader records the lengths of the reads it is asked for.
+type lengthsReader struct {
+	r       io.Reader
+	lengths []int
+}
+
+func (l *lengthsReader) Read(p []byte) (int, error) {
+	l.lengths = append(l.lengths, len(p))
+	return l.r.Read(p)
+}
+
+func TestLimitedReader(t *testing.T) {
+	errLimit := errors.New("limit")
+
+	tests := []struct {
+		name    string
+		content string
+		n       int64
+		chunked bool
+		want    string
+		err     error
+	}{
+		{name: "not limited", content: "hello world", n: -1, want: "hello world"},
+		{name: "under the limit", content: "hello", n: 10, want: "hello"},
+		{name: "at the limit", content: "hello", n: 5, want: "hello"},
+		{name: "at the limit in chunks", content: "hello", n: 5, chunked: true, want: "hello"},
+		{name: "one byte past the limit", content: "hello!", n: 5, want: "hello", err: errLimit},
+		{name: "one byte past the limit in chunks", content: "hello!", n: 5, chunked: true, want: "hello", err: errLimit},
+		{name: "far past the limit", content: "hello world", n: 5, want: "hello", err: errLimit},
+		{name: "empty at a zero limit", content: "", n: 0, want: ""},
+		{name: "past a zero limit", content: "h", n: 0, want: "", err: errLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var content io.Reader = strings.NewReader(tt.content)
+			if tt.chunked {
+				content = iotest.OneByteReader(content)
+			}
+			limited := &limitedReader{r: content, n: tt.n, err: errLimit}
+
+			got, err := io.ReadAll(limited)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, string(got))
+			assert.Equal(t, int64(len(tt.want)), limited.read)
+			assert.Equal(t, tt.err != nil, limited.exceeded)
+		})
+	}
+}
+
+func TestLimitedReader_ReadsOneBytePastTheLimit(t *testing.T) {
+	source := &lengthsReader{r: strings.NewReader(strings.Repeat("a", 100))}
+	limited := &limitedReader{r: source, n: 10, err: domain.ErrTooLarge}
+
+	// Large reads are cut to the bytes left and one more, which tells the content exceeds the limit
+	n, err := limited.Read(make([]byte, 64))
+	assert.Equal(t, 10, n)
+	assert.Equal(t, domain.ErrTooLarge, err)
+	assert.Equal(t, []int{11}, source.lengths)
+	assert.Equal(t, int64(10), limited.read)
+
+	// Once exceeded, the source is not read anymore
+	n, err = limited.Read(make([]byte, 64))
+	assert.Equal(t, 0, n)
+	assert.Equal(t, domain.ErrTooLarge, err)
+	assert.Len(t, source.lengths, 1)
+}
+
+func TestLimitedReader_SmallReads(t *testing.T) {
+	source := &lengthsReader{r: strings.NewReader("hello")}
+	limited := &limitedReader{r: source, n: 5, err: domain.ErrTooLarge}
+
+	// Reads smaller than what is left are not cut
+	n, err := limited.Read(make([]byte, 3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	// Content ending at the limit is not exceeded, the byte past it is asked for but not there
+	got, err := io.ReadAll(limited)
+	require.NoError(t, err)
+	assert.Equal(t, "lo", string(got))
+	assert.False(t, limited.exceeded)
+	assert.Equal(t, 3, source.lengths[1])
+}
+
+func TestLimitedReader_ReadError(t *testing.T) {
+	failure := errors.New("connection reset")
+	limited := &limitedReader{r: iotest.ErrReader(failure), n: 10, err: domain.ErrTooLarge}
+
+	_, err := io.ReadAll(limited)
+
+	assert.Equal(t, failure, err)
+	assert.False(t, limited.exceeded)
+}
+
+func TestSniffContentType(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 1000)...)
+
+	tests := []struct {
+		name    string
+		content []byte
+		chunked bool
+		want    string
+	}{
+		{name: "empty", content: nil, want: "text/plain; charset=utf-8"},
+		{name: "text", content: []byte("hello world"), want: "text/plain; charset=utf-8"},
+		{name: "png", content: png, want: "image/png"},
+		{name: "png in chunks", content: png, chunked: true, want: "image/png"},
+		{name: "binary", content: []byte{0, 1, 2, 3}, want: "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var content io.Reader = bytes.NewReader(tt.content)
+			if tt.chunked {
+				content = iotest.OneByteReader(content)
+			}
+
+			contentType, reader, err := sniffContentType(content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, contentType)
+
+			// The sniffed bytes are read again with the rest of the content
+			got, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.content), len(got))
+			assert.True(t, bytes.Equal(tt.content, got))
+		})
+	}
+}
+
+func TestSniffContentType_ReadError(t *testing.T) {
+	failure := errors.New("connection reset")
+
+	_, _, err := sniffContentType(iotest.ErrReader(failure))
+
+	assert.Equal(t, failure, err)
+}
+
+func TestLimits_Reserve(t *testing.T) {
+	ctx := context.Background()
+	limits, authorID := newTestLimits(100, 40)
+
+	reserved, err := limits.reserve(ctx, authorID, 50)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), reserved)
+
+	// The reservation counts for the next uploads
+	_, err = limits.reserve(ctx, authorID, 20)
+	assert.Equal(t, domain.ErrQuota, err)
+
+	reserved, err = limits.reserve(ctx, authorID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), reserved)
+
+	usage, err := limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), usage.Used)
+
+	// Nothing is reserved without a quota or a size
+	unlimited, otherID := newTestLimits(0, 0)
+	reserved, err = unlimited.reserve(ctx, otherID, 50)
+	require.NoError(t, err)
+	assert.Zero(t, reserved)
+	reserved, err = limits.reserve(ctx, authorID, 0)
+	require.NoError(t, err)
+	assert.Zero(t, reserved)
+}
@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// MetaRepositoryInterface indexes the metadata of files, so they can be listed without scanning the storage.
+type MetaRepositoryInterface interface {
+	// SaveMeta creates or replaces the metadata of a file.
+	SaveMeta(ctx context.Context, meta domain.FileMeta) error
+	GetMeta(ctx context.Context, id uuid.UUID) (domain.FileMeta, error)
+	// ListMeta returns a page of the files selected by a validated query.
+	ListMeta(ctx context.Context, query domain.MetaQuery) (domain.MetaPage, error)
+	DeleteMeta(ctx context.Context, id uuid.UUID) error
+}
+
+// indexFile saves the metadata of a file created for a purpose, generic if empty.
+// The file is stored either way, so errors are logged instead of returned.
+func indexFile(ctx context.Context, metas MetaRepositoryInterface, file domain.File, purpose domain.Purpose, logger *slog.Logger) {
+	const op = "indexFile"
+
+	meta := domain.MetaOf(file, time.Now().UTC().Truncate(time.Microsecond))
+	if purpose != "" {
+		meta.Purpose = purpose
+	}
+	if err := metas.SaveMeta(ctx, meta); err != nil {
+		logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+	}
+}
@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type PresignRepositoryInterface interface {
+	// PresignUpload saves a presigned upload and returns the URL to PUT its content to.
+	PresignUpload(ctx context.Context, upload *domain.PresignedUpload, ttl time.Duration) (string, error)
+	GetPresignedUpload(ctx context.Context, id uuid.UUID) (*domain.PresignedUpload, error)
+	// StatPresignedUpload returns the size and the content type of the uploaded content.
+	StatPresignedUpload(ctx context.Context, upload *domain.PresignedUpload) (int64, string, error)
+	// ReadPresignedUpload returns up to length first bytes of the uploaded content.
+	ReadPresignedUpload(ctx context.Context, upload *domain.PresignedUpload, length int64) ([]byte, error)
+	// FinalizeUpload creates the file of a presigned upload with the uploaded content and removes the upload.
+	FinalizeUpload(ctx context.Context, upload *domain.PresignedUpload) error
+	// DeletePresignedUpload removes a presigned upload with its content.
+	DeletePresignedUpload(ctx context.Context, upload *domain.PresignedUpload) error
+	// GetExpiredPresignedUploads returns the presigned uploads that expired at the given time.
+	GetExpiredPresignedUploads(ctx context.Context, now time.Time) ([]*domain.PresignedUpload, error)
+	// PresignDownload returns the URL to GET the content of a file from.
+	PresignDownload(ctx context.Context, file domain.File, ttl time.Duration) (string, error)
+}
+
+var _ usecases.PresignUseCaseInterface = &PresignUseCase{}
+
+type PresignUseCase struct {
+	Repository PresignRepositoryInterface
+	Files      FileRepositoryInterface
+	Blobs      BlobRepositoryInterface
+	Metas      MetaRepositoryInterface
+	Limits     *Limits
+	Processor  FileProcessor
+	TTL        time.Duration // lifetime of the URLs
+	logger     *slog.Logger
+}
+
+func (p *PresignUseCase) PresignUpload(ctx context.Context, dto usecases.PresignUploadDTO) (*domain.PresignedUpload, *domain.PresignedURL, error) {
+	if dto.Size < 0 {
+		return nil, nil, domain.ErrInvalidSize
+	}
+	if err := dto.Access.Validate(); err != nil {
+		return nil, nil, err
+	}
+	checksum, err := domain.ParseChecksum(dto.Checksum)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dto.Purpose == "" {
+		dto.Purpose = domain.PurposeGeneric
+	}
+	allowance, err := p.Limits.allowance(ctx, dto.AuthorID, dto.Purpose, dto.Size)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dto.ContentType == "" || !allowance.policy.AllowsContentType(dto.ContentType) {
+		return nil, nil, domain.ErrContentType
+	}
+
+	upload := &domain.PresignedUpload{
+		ID:          uuid.New(),
+		AuthorID:    dto.AuthorID,
+		Name:        dto.Name,
+		ContentType: dto.ContentType,
+		Size:        dto.Size,
+		ExpiresAt:   time.Now().UTC().Add(p.TTL),
+		Access:      dto.Access,
+		Purpose:     dto.Purpose,
+		Checksum:    checksum,
+	}
+
+	upload.Reserved, err = p.Limits.reserve(ctx, upload.AuthorID, upload.Size)
+	if err != nil {
+		return nil, nil, err
+	}
+	url, err := p.Repository.PresignUpload(ctx, upload, p.TTL)
+	if err != nil {
+		p.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+		return nil, nil, err
+	}
+
+	return upload, &domain.PresignedURL{
+		URL:       url,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": upload.ContentType},
+		ExpiresAt: upload.ExpiresAt,
+	}, nil
+}
+
+func (p *PresignUseCase) FinalizeUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.File, error) {
+	const op = "PresignUseCase.FinalizeUpload"
+
+	upload, err := p.Repository.GetPresignedUpload(ctx, id)
+	if err != nil {
+		return domain.File{}, err
+	}
+	if upload.AuthorID != userID {
+		return domain.File{}, domain.ErrForbidden
+	}
+
+	size, contentType, err := p.Repository.StatPresignedUpload(ctx, upload)
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	// The presigned URL does not limit what is uploaded, so the content is checked before it becomes a file
+	if size != upload.Size || contentType != upload.ContentType {
+		p.logger.Info(op, slog.Any("upload", upload.ID), slog.Any("size", size), slog.Any("content_type", contentType))
+		if err := p.deleteUpload(ctx, upload); err != nil {
+			return domain.File{}, err
+		}
+		return domain.File{}, domain.ErrContentDiffer
+	}
+
+	// The declared content type is not trusted, the file gets the one detected from its content
+	head, err := p.Repository.ReadPresignedUpload(ctx, upload, sniffLen)
+	if err != nil {
+		return domain.File{}, err
+	}
+	upload.ContentType = http.DetectContentType(head)
+	if policy, ok := p.Limits.Policies[upload.Purpose]; !ok || !policy.AllowsContentType(upload.ContentType) {
+		p.logger.Info(op, slog.Any("upload", upload.ID), slog.Any("detected_content_type", upload.ContentType))
+		if err := p.deleteUpload(ctx, upload); err != nil {
+			return domain.File{}, err
+		}
+		return domain.File{}, domain.ErrContentType
+	}
+
+	if err := p.Repository.FinalizeUpload(ctx, upload); err != nil {
+		return domain.File{}, err
+	}
+
+	// The upload is gone once it is composed into the file, so a file that cannot be stored is not kept
+	file, err := p.Files.StatFile(ctx, upload.ID)
+	if err != nil {
+		deleteContent(ctx, p.Files, upload.ID, p.logger)
+		p.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+		return domain.File{}, err
+	}
+
+	// The content skipped the service, so it is read for its checksum
+	if err := storeContent(ctx, p.Files, p.Blobs, &file, "", upload.Checksum, p.logger); err != nil {
+		deleteContent(ctx, p.Files, upload.ID, p.logger)
+		p.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+		return domain.File{}, err
+	}
+
+	p.Limits.charge(ctx, upload.AuthorID, upload.Size-upload.Reserved)
+
+	indexFile(ctx, p.Metas, file, upload.Purpose, p.logger)
+	p.Processor.Enqueue(file)
+
+	return file, nil
+}
+
+func (p *PresignUseCase) PresignDownload(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.PresignedURL, error) {
+	file, err := p.Files.StatFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !file.CanRead(userID) {
+		return nil, domain.ErrNotFound
+	}
+
+	url, err := p.Repository.PresignDownload(ctx, file, p.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PresignedURL{
+		URL:       url,
+		Method:    http.MethodGet,
+		ExpiresAt: time.Now().UTC().Add(p.TTL),
+	}, nil
+}
+
+func (p *PresignUseCase) ExpireUploads(ctx context.Context) (int, error) {
+	const op = "PresignUseCase.ExpireUploads"
+
+	// Uploads started just before their URL expired get another TTL to finish
+	uploads, err := p.Repository.GetExpiredPresignedUploads(ctx, time.Now().Add(-p.TTL))
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, upload := range uploads {
+		if err := p.deleteUpload(ctx, upload); err != nil {
+			p.logger.Error(op, slog.Any("upload", upload.ID), slog.Any("error", err.Error()))
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// deleteUpload removes a presigned upload and releases its reservation.
+func (p *PresignUseCase) deleteUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	if err := p.Repository.DeletePresignedUpload(ctx, upload); err != nil {
+		return err
+	}
+	p.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+	return nil
+}
+
+func NewPresignUseCase(repository PresignRepositoryInterface, files FileRepositoryInterface, blobs BlobRepositoryInterface, metas MetaRepositoryInterface, limits *Limits, processor FileProcessor, ttl time.Duration, logger *slog.Logger) *PresignUseCase {
+	return &PresignUseCase{
+		Repository: repository,
+		Files:      files,
+		Blobs:      blobs,
+		Metas:      metas,
+		Limits:     limits,
+		Processor:  processor,
+		TTL:        ttl,
+		logger:     logger,
+	}
+}
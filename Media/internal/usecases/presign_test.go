@@ -0,0 +1,379 @@
+package usecases_test
+
+import (
+	contracts "Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	inmemoryRepo "Media/internal/infrastructure/repositories/inmemory"
+	"Media/internal/usecases"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// presignedContent is what a client uploaded to the URL of a presigned upload.
+type presignedContent struct {
+	contentType string
+	data        []byte
+}
+
+// fakePresignRepository keeps presigned uploads in memory and composes them into the files of a FileRepository.
+// Content is uploaded with put, as a client would to the presigned URL.
+type fakePresignRepository struct {
+	files *inmemoryRepo.FileRepository
+
+	m        sync.Mutex
+	uploads  map[uuid.UUID]domain.PresignedUpload
+	contents map[uuid.UUID]presignedContent
+}
+
+func newFakePresignRepository(files *inmemoryRepo.FileRepository) *fakePresignRepository {
+	return &fakePresignRepository{
+		files:    files,
+		uploads:  make(map[uuid.UUID]domain.PresignedUpload),
+		contents: make(map[uuid.UUID]presignedContent),
+	}
+}
+
+func (r *fakePresignRepository) put(id uuid.UUID, contentType string, data string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.contents[id] = presignedContent{contentType: contentType, data: []byte(data)}
+}
+
+func (r *fakePresignRepository) PresignUpload(ctx context.Context, upload *domain.PresignedUpload, ttl time.Duration) (string, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.uploads[upload.ID] = *upload
+	return "https://storage.example.com/presigned/" + upload.ID.String(), nil
+}
+
+func (r *fakePresignRepository) GetPresignedUpload(ctx context.Context, id uuid.UUID) (*domain.PresignedUpload, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	upload, ok := r.uploads[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &upload, nil
+}
+
+func (r *fakePresignRepository) StatPresignedUpload(ctx context.Context, upload *domain.PresignedUpload) (int64, string, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	content, ok := r.contents[upload.ID]
+	if !ok {
+		return 0, "", domain.ErrNotFound
+	}
+	return int64(len(content.data)), content.contentType, nil
+}
+
+func (r *fakePresignRepository) ReadPresignedUpload(ctx context.Context, upload *domain.PresignedUpload, length int64) ([]byte, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	content, ok := r.contents[upload.ID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return content.data[:min(int64(len(content.data)), length)], nil
+}
+
+func (r *fakePresignRepository) FinalizeUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	r.m.Lock()
+	content := r.contents[upload.ID]
+	r.m.Unlock()
+
+	file := domain.File{
+		ID:          upload.ID,
+		AuthorID:    upload.AuthorID,
+		Name:        upload.Name,
+		Size:        int64(len(content.data)),
+		ContentType: upload.ContentType,
+		Access:      upload.Access,
+	}
+	if err := r.files.CreateFile(ctx, file, bytes.NewReader(content.data)); err != nil {
+		return err
+	}
+	return r.DeletePresignedUpload(ctx, upload)
+}
+
+func (r *fakePresignRepository) DeletePresignedUpload(ctx context.Context, upload *domain.PresignedUpload) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.uploads, upload.ID)
+	delete(r.contents, upload.ID)
+	return nil
+}
+
+func (r *fakePresignRepository) GetExpiredPresignedUploads(ctx context.Context, now time.Time) ([]*domain.PresignedUpload, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	var expired []*domain.PresignedUpload
+	for _, upload := range r.uploads {
+		if upload.Expired(now) {
+			expired = append(expired, &upload)
+		}
+	}
+	return expired, nil
+}
+
+func (r *fakePresignRepository) PresignDownload(ctx context.Context, file domain.File, ttl time.Duration) (string, error) {
+	return "https://storage.example.com/" + file.ID.String(), nil
+}
+
+// newPresignUseCase returns a PresignUseCase over the repositories of a backend, with URLs that last an hour.
+func newPresignUseCase(b *backend) (*fakePresignRepository, *usecases.PresignUseCase) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	presign := newFakePresignRepository(b.files)
+	return presign, usecases.NewPresignUseCase(presign, b.files, b.blobs, b.metas, b.limits, b.processor, time.Hour, logger)
+}
+
+func presignUpload(t *testing.T, puc *usecases.PresignUseCase, authorID uuid.UUID, contentType string, size int64) *domain.PresignedUpload {
+	t.Helper()
+	upload, url, err := puc.PresignUpload(context.Background(), contracts.PresignUploadDTO{
+		Name:        "file.txt",
+		AuthorID:    authorID,
+		ContentType: contentType,
+		Size:        size,
+		Access:      domain.Access{Visibility: domain.VisibilityPrivate},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, contentType, url.Headers["Content-Type"])
+	return upload
+}
+
+func TestPresignUseCase_FinalizeUpload(t *testing.T) {
+	b := newBackend(t, nil, 100)
+	ctx := context.Background()
+	authorID := uuid.New()
+	presign, puc := newPresignUseCase(b)
+	used := func() int64 {
+		usage, err := b.limits.GetUsage(ctx, authorID)
+		require.NoError(t, err)
+		return usage.Used
+	}
+
+	upload := presignUpload(t, puc, authorID, "text/plain", 5)
+	assert.Equal(t, int64(5), used())
+	presign.put(upload.ID, "text/plain", "hello")
+
+	// Only the author finalizes the upload
+	_, err := puc.FinalizeUpload(ctx, uuid.New(), upload.ID)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	file, err := puc.FinalizeUpload(ctx, authorID, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", file.ContentType)
+	sum := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), file.Checksum)
+	assert.Equal(t, "hello", b.readAll(t, authorID, upload.ID))
+	assert.Equal(t, int64(5), used())
+	assert.Contains(t, b.processor.enqueued, upload.ID)
+
+	meta, err := b.metas.GetMeta(ctx, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PurposeGeneric, meta.Purpose)
+
+	_, err = puc.FinalizeUpload(ctx, authorID, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestPresignUseCase_FinalizeUpload_ContentDiffer(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		content     string
+	}{
+		{name: "larger than declared", contentType: "text/plain", content: "hello world"},
+		{name: "smaller than declared", contentType: "text/plain", content: "hi"},
+		{name: "other content type", contentType: "image/png", content: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBackend(t, nil, 100)
+			ctx := context.Background()
+			authorID := uuid.New()
+			presign, puc := newPresignUseCase(b)
+			upload := presignUpload(t, puc, authorID, "text/plain", 5)
+			presign.put(upload.ID, tt.contentType, tt.content)
+
+			// Content that is not what was declared is dropped with its upload and reservation
+			_, err := puc.FinalizeUpload(ctx, authorID, upload.ID)
+			assert.ErrorIs(t, err, domain.ErrContentDiffer)
+
+			_, err = presign.GetPresignedUpload(ctx, upload.ID)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+			_, err = b.files.StatFile(ctx, upload.ID)
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+			usage, err := b.limits.GetUsage(ctx, authorID)
+			require.NoError(t, err)
+			assert.Zero(t, usage.Used)
+		})
+	}
+}
+
+func TestPresignUseCase_FinalizeUpload_SniffedContentType(t *testing.T) {
+	b := newBackend(t, map[domain.Purpose]domain.Policy{
+		domain.PurposeAvatar: {ContentTypes: []string{"image/*"}},
+	}, 100)
+	ctx := context.Background()
+	authorID := uuid.New()
+	presign, puc := newPresignUseCase(b)
+
+	// The declared type is allowed, the content is checked for the type it really has
+	upload, _, err := puc.PresignUpload(ctx, contracts.PresignUploadDTO{
+		Name:        "avatar.png",
+		AuthorID:    authorID,
+		ContentType: "image/png",
+		Size:        5,
+		Purpose:     domain.PurposeAvatar,
+	})
+	require.NoError(t, err)
+	presign.put(upload.ID, "image/png", "hello")
+
+	_, err = puc.FinalizeUpload(ctx, authorID, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrContentType)
+
+	_, err = presign.GetPresignedUpload(ctx, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = b.files.StatFile(ctx, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Used)
+}
+
+func TestPresignUseCase_FinalizeUpload_Checksum(t *testing.T) {
+	b := newBackend(t, nil, 100)
+	ctx := context.Background()
+	authorID := uuid.New()
+	presign, puc := newPresignUseCase(b)
+	upload, _, err := puc.PresignUpload(ctx, contracts.PresignUploadDTO{
+		Name:        "file.txt",
+		AuthorID:    authorID,
+		ContentType: "text/plain",
+		Size:        5,
+		Checksum:    strings.Repeat("0", 64),
+	})
+	require.NoError(t, err)
+	presign.put(upload.ID, "text/plain", "hello")
+
+	// The file composed from the upload is not kept when its content cannot be stored
+	_, err = puc.FinalizeUpload(ctx, authorID, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrChecksum)
+
+	_, err = b.files.StatFile(ctx, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Used)
+	assert.Empty(t, b.processor.enqueued)
+}
+
+func TestPresignUseCase_PresignUpload_Rejected(t *testing.T) {
+	b := newBackend(t, map[domain.Purpose]domain.Policy{
+		domain.PurposeGeneric: {},
+		domain.PurposeAvatar:  {ContentTypes: []string{"image/*"}},
+	}, 10)
+	ctx := context.Background()
+	authorID := uuid.New()
+	_, puc := newPresignUseCase(b)
+
+	tests := []struct {
+		name string
+		dto  contracts.PresignUploadDTO
+		err  error
+	}{
+		{name: "negative size", dto: contracts.PresignUploadDTO{ContentType: "text/plain", Size: -1}, err: domain.ErrInvalidSize},
+		{name: "no content type", dto: contracts.PresignUploadDTO{Size: 5}, err: domain.ErrContentType},
+		{name: "content type of another purpose", dto: contracts.PresignUploadDTO{ContentType: "text/plain", Size: 5, Purpose: domain.PurposeAvatar},
+			err: domain.ErrContentType},
+		{name: "over the quota", dto: contracts.PresignUploadDTO{ContentType: "text/plain", Size: 11}, err: domain.ErrQuota},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dto.AuthorID = authorID
+			tt.dto.Name = "file.txt"
+
+			_, _, err := puc.PresignUpload(ctx, tt.dto)
+
+			assert.ErrorIs(t, err, tt.err)
+			usage, err := b.limits.GetUsage(ctx, authorID)
+			require.NoError(t, err)
+			assert.Zero(t, usage.Used)
+		})
+	}
+}
+
+func TestPresignUseCase_PresignDownload(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	_, puc := newPresignUseCase(b)
+	private := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityPrivate})
+	public := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityPublic})
+
+	url, err := puc.PresignDownload(ctx, authorID, private)
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.example.com/"+private.String(), url.URL)
+	assert.Equal(t, "GET", url.Method)
+
+	_, err = puc.PresignDownload(ctx, uuid.Nil, public)
+	assert.NoError(t, err)
+
+	// Files the user cannot read are not found
+	_, err = puc.PresignDownload(ctx, uuid.New(), private)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = puc.PresignDownload(ctx, uuid.Nil, private)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = puc.PresignDownload(ctx, authorID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestPresignUseCase_ExpireUploads(t *testing.T) {
+	b := newBackend(t, nil, 100)
+	ctx := context.Background()
+	authorID := uuid.New()
+	presign, puc := newPresignUseCase(b)
+	recent := presignUpload(t, puc, authorID, "text/plain", 5)
+	inGrace := presignUpload(t, puc, authorID, "text/plain", 5)
+	expired := presignUpload(t, puc, authorID, "text/plain", 5)
+
+	// Uploads get another TTL after their URL expired to finish
+	backdate := func(id uuid.UUID, expiresAt time.Time) {
+		presign.m.Lock()
+		defer presign.m.Unlock()
+		upload := presign.uploads[id]
+		upload.ExpiresAt = expiresAt
+		presign.uploads[id] = upload
+	}
+	backdate(inGrace.ID, time.Now().Add(-30*time.Minute))
+	backdate(expired.ID, time.Now().Add(-2*time.Hour))
+
+	n, err := puc.ExpireUploads(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = presign.GetPresignedUpload(ctx, expired.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	for _, id := range []uuid.UUID{recent.ID, inGrace.ID} {
+		_, err := presign.GetPresignedUpload(ctx, id)
+		assert.NoError(t, err)
+	}
+
+	// The reservation of the expired upload is released
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), usage.Used)
+}
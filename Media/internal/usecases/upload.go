@@ -0,0 +1,309 @@
+package usecases
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type UploadRepositoryInterface interface {
+	// CreateUpload saves a new upload.
+	CreateUpload(ctx context.Context, upload *domain.Upload) error
+	GetUpload(ctx context.Context, id uuid.UUID) (*domain.Upload, error)
+	// AppendUpload stores content at the end of an upload, and saves the upload with its new offset.
+	// The received content is kept if reading it fails.
+	AppendUpload(ctx context.Context, upload *domain.Upload, content io.Reader) error
+	// CompleteUpload creates the file of a done upload and removes the upload.
+	CompleteUpload(ctx context.Context, upload *domain.Upload) error
+	// DeleteUpload cancels an upload in the storage and removes it.
+	DeleteUpload(ctx context.Context, upload *domain.Upload) error
+	// GetExpiredUploads returns the uploads that expired at the given time.
+	GetExpiredUploads(ctx context.Context, now time.Time) ([]*domain.Upload, error)
+}
+
+var _ usecases.UploadUseCaseInterface = &UploadUseCase{}
+
+type UploadUseCase struct {
+	Repository UploadRepositoryInterface
+	Files      FileRepositoryInterface
+	Blobs      BlobRepositoryInterface
+	Metas      MetaRepositoryInterface
+	Limits     *Limits
+	Processor  FileProcessor
+	MaxSize    int64
+	TTL        time.Duration
+	logger     *slog.Logger
+
+	// writing holds the IDs of the uploads written by a request of this process
+	writing uploadLocks
+}
+
+// uploadLocks are locks of uploads that are not waited for, requests of a locked upload fail with domain.ErrUploadLocked.
+type uploadLocks struct {
+	m      sync.Mutex
+	locked map[uuid.UUID]struct{}
+}
+
+// lock locks an upload, and reports whether it was not locked already.
+func (l *uploadLocks) lock(id uuid.UUID) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if _, ok := l.locked[id]; ok {
+		return false
+	}
+	if l.locked == nil {
+		l.locked = make(map[uuid.UUID]struct{})
+	}
+	l.locked[id] = struct{}{}
+	return true
+}
+
+func (l *uploadLocks) unlock(id uuid.UUID) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	delete(l.locked, id)
+}
+
+func (u *UploadUseCase) CreateUpload(ctx context.Context, dto usecases.CreateUploadDTO) (*domain.Upload, error) {
+	if dto.Size < 0 {
+		return nil, domain.ErrInvalidSize
+	}
+	if u.MaxSize > 0 && dto.Size > u.MaxSize {
+		return nil, domain.ErrTooLarge
+	}
+	if err := dto.Access.Validate(); err != nil {
+		return nil, err
+	}
+	checksum, err := domain.ParseChecksum(dto.Checksum)
+	if err != nil {
+		return nil, err
+	}
+	if dto.Purpose == "" {
+		dto.Purpose = domain.PurposeGeneric
+	}
+	allowance, err := u.Limits.allowance(ctx, dto.AuthorID, dto.Purpose, dto.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	upload := &domain.Upload{
+		ID:        uuid.New(),
+		AuthorID:  dto.AuthorID,
+		Name:      dto.Name,
+		Size:      dto.Size,
+		CreatedAt: now,
+		ExpiresAt: now.Add(u.TTL),
+		Access:    dto.Access,
+		Purpose:   dto.Purpose,
+		Checksum:  checksum,
+	}
+
+	// Empty files have no content to wait for
+	if upload.Done() {
+		upload.ContentType = http.DetectContentType(nil)
+		if !allowance.policy.AllowsContentType(upload.ContentType) {
+			return nil, domain.ErrContentType
+		}
+	}
+
+	upload.Reserved, err = u.Limits.reserve(ctx, upload.AuthorID, upload.Size)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.Repository.CreateUpload(ctx, upload); err != nil {
+		u.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+		return nil, err
+	}
+
+	if upload.Done() {
+		if err := u.complete(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	return upload, nil
+}
+
+func (u *UploadUseCase) GetUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Upload, error) {
+	upload, err := u.Repository.GetUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.AuthorID != userID {
+		return nil, domain.ErrForbidden
+	}
+	if upload.Expired(time.Now()) {
+		return nil, domain.ErrExpired
+	}
+	return upload, nil
+}
+
+// WriteUpload appends content to an upload.
+// Concurrent writes of an upload would both pass the offset check, so the second one fails with domain.ErrUploadLocked.
+func (u *UploadUseCase) WriteUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID, offset int64, content io.Reader) (*domain.Upload, error) {
+	if !u.writing.lock(id) {
+		return nil, domain.ErrUploadLocked
+	}
+	defer u.writing.unlock(id)
+
+	upload, err := u.GetUpload(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return nil, domain.ErrWrongOffset
+	}
+
+	// Content past the size of the upload is ignored
+	content = io.LimitReader(content, upload.Size-upload.Offset)
+
+	// The content type is detected from the first content, before any of it is stored
+	if upload.ContentType == "" {
+		content, err = u.detectContentType(ctx, upload, content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = u.Repository.AppendUpload(ctx, upload, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Done() {
+		if err := u.complete(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	return upload, nil
+}
+
+// detectContentType sets the content type of an upload from its first content.
+// Uploads of content types their purpose does not allow are cancelled.
+func (u *UploadUseCase) detectContentType(ctx context.Context, upload *domain.Upload, content io.Reader) (io.Reader, error) {
+	contentType, reader, err := sniffContentType(content)
+	if err != nil {
+		return nil, err
+	}
+	if reader.Buffered() == 0 {
+		// Nothing is received yet
+		return reader, nil
+	}
+
+	policy, ok := u.Limits.Policies[upload.Purpose]
+	if !ok || !policy.AllowsContentType(contentType) {
+		if err := u.deleteUpload(ctx, upload); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrContentType
+	}
+
+	upload.ContentType = contentType
+	return reader, nil
+}
+
+// complete creates the file of a done upload, moves its content to a blob, charges what was not reserved of it
+// to its author, indexes it and queues it for processing.
+func (u *UploadUseCase) complete(ctx context.Context, upload *domain.Upload) error {
+	if err := u.Repository.CompleteUpload(ctx, upload); err != nil {
+		return err
+	}
+
+	file := domain.File{
+		ID:          upload.ID,
+		AuthorID:    upload.AuthorID,
+		Name:        upload.Name,
+		Size:        upload.Size,
+		ContentType: upload.ContentType,
+		Access:      upload.Access,
+	}
+
+	// The content arrived in several requests, so it is hashed once it is stored
+	if err := storeContent(ctx, u.Files, u.Blobs, &file, "", upload.Checksum, u.logger); err != nil {
+		u.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+		return err
+	}
+
+	u.Limits.charge(ctx, upload.AuthorID, upload.Size-upload.Reserved)
+
+	indexFile(ctx, u.Metas, file, upload.Purpose, u.logger)
+	u.Processor.Enqueue(file)
+
+	return nil
+}
+
+func (u *UploadUseCase) DeleteUpload(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	upload, err := u.Repository.GetUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+	if upload.AuthorID != userID {
+		return domain.ErrForbidden
+	}
+
+	if !u.writing.lock(id) {
+		return domain.ErrUploadLocked
+	}
+	defer u.writing.unlock(id)
+
+	return u.deleteUpload(ctx, upload)
+}
+
+// deleteUpload removes an upload and releases its reservation.
+func (u *UploadUseCase) deleteUpload(ctx context.Context, upload *domain.Upload) error {
+	if err := u.Repository.DeleteUpload(ctx, upload); err != nil {
+		return err
+	}
+	u.Limits.charge(ctx, upload.AuthorID, -upload.Reserved)
+	return nil
+}
+
+func (u *UploadUseCase) ExpireUploads(ctx context.Context) (int, error) {
+	const op = "UploadUseCase.ExpireUploads"
+
+	uploads, err := u.Repository.GetExpiredUploads(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, upload := range uploads {
+		// Uploads being written are expired by a later run
+		if !u.writing.lock(upload.ID) {
+			continue
+		}
+		err := u.deleteUpload(ctx, upload)
+		u.writing.unlock(upload.ID)
+		if err != nil {
+			u.logger.Error(op, slog.Any("upload", upload.ID), slog.Any("error", err.Error()))
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func NewUploadUseCase(repository UploadRepositoryInterface, files FileRepositoryInterface, blobs BlobRepositoryInterface, metas MetaRepositoryInterface, limits *Limits, processor FileProcessor, maxSize int64, ttl time.Duration, logger *slog.Logger) *UploadUseCase {
+	return &UploadUseCase{
+		Repository: repository,
+		Files:      files,
+		Blobs:      blobs,
+		Metas:      metas,
+		Limits:     limits,
+		Processor:  processor,
+		MaxSize:    maxSize,
+		TTL:        ttl,
+		logger:     logger,
+	}
+}
@@ -0,0 +1,185 @@
+package usecases_test
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// blockingReader blocks its first read until it is released, and then reads its content.
+type blockingReader struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+	content io.Reader
+}
+
+func newBlockingReader(content string) *blockingReader {
+	return &blockingReader{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+		content: strings.NewReader(content),
+	}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	r.once.Do(func() { close(r.started) })
+	<-r.release
+	return r.content.Read(p)
+}
+
+func createUpload(t *testing.T, b *backend, authorID uuid.UUID, size int64) *domain.Upload {
+	t.Helper()
+	upload, err := b.uploadUseCase.CreateUpload(context.Background(), usecases.CreateUploadDTO{
+		Name:     "upload.txt",
+		AuthorID: authorID,
+		Size:     size,
+		Access:   domain.Access{Visibility: domain.VisibilityPrivate},
+	})
+	require.NoError(t, err)
+	return upload
+}
+
+func TestUploadUseCase_WriteUpload(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	upload := createUpload(t, b, authorID, 11)
+
+	upload, err := b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, strings.NewReader("hello "))
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), upload.Offset)
+	assert.Equal(t, "text/plain; charset=utf-8", upload.ContentType)
+
+	_, err = b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, strings.NewReader("hello "))
+	assert.ErrorIs(t, err, domain.ErrWrongOffset)
+
+	_, err = b.uploadUseCase.WriteUpload(ctx, uuid.New(), upload.ID, 6, strings.NewReader("world"))
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	// Content past the size of the upload is ignored
+	upload, err = b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 6, strings.NewReader("world and more"))
+	require.NoError(t, err)
+	assert.True(t, upload.Done())
+
+	assert.Equal(t, "hello world", b.readAll(t, authorID, upload.ID))
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), usage.Used)
+	assert.Contains(t, b.processor.enqueued, upload.ID)
+
+	_, err = b.uploadUseCase.GetUpload(ctx, authorID, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUploadUseCase_WriteUpload_Concurrent(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	upload := createUpload(t, b, authorID, 11)
+
+	first := newBlockingReader("hello ")
+	done := make(chan error)
+	go func() {
+		_, err := b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, first)
+		done <- err
+	}()
+	<-first.started
+
+	// Both requests are at the offset of the upload, the second one must not write it too
+	_, err := b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, strings.NewReader("HELLO "))
+	assert.ErrorIs(t, err, domain.ErrUploadLocked)
+	assert.ErrorIs(t, b.uploadUseCase.DeleteUpload(ctx, authorID, upload.ID), domain.ErrUploadLocked)
+
+	close(first.release)
+	require.NoError(t, <-done)
+
+	_, err = b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, strings.NewReader("HELLO "))
+	assert.ErrorIs(t, err, domain.ErrWrongOffset)
+
+	upload, err = b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 6, strings.NewReader("world"))
+	require.NoError(t, err)
+	assert.True(t, upload.Done())
+	assert.Equal(t, "hello world", b.readAll(t, authorID, upload.ID))
+}
+
+func TestUploadUseCase_DeleteUpload(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	upload := createUpload(t, b, authorID, 11)
+
+	assert.ErrorIs(t, b.uploadUseCase.DeleteUpload(ctx, uuid.New(), upload.ID), domain.ErrForbidden)
+	require.NoError(t, b.uploadUseCase.DeleteUpload(ctx, authorID, upload.ID))
+
+	_, err := b.uploadUseCase.GetUpload(ctx, authorID, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUploadUseCase_Quota(t *testing.T) {
+	b := newBackend(t, nil, 20)
+	ctx := context.Background()
+	authorID := uuid.New()
+	used := func() int64 {
+		usage, err := b.limits.GetUsage(ctx, authorID)
+		require.NoError(t, err)
+		return usage.Used
+	}
+
+	// The declared size is reserved when the upload starts, so a second one cannot overshoot the quota
+	first := createUpload(t, b, authorID, 15)
+	assert.Equal(t, int64(15), used())
+	_, err := b.uploadUseCase.CreateUpload(ctx, usecases.CreateUploadDTO{Name: "second.txt", AuthorID: authorID, Size: 10})
+	assert.ErrorIs(t, err, domain.ErrQuota)
+	assert.Equal(t, int64(15), used())
+
+	// Cancelled uploads release their reservation
+	require.NoError(t, b.uploadUseCase.DeleteUpload(ctx, authorID, first.ID))
+	assert.Zero(t, used())
+
+	// Completed uploads are not charged twice
+	upload := createUpload(t, b, authorID, 15)
+	upload, err = b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, strings.NewReader(strings.Repeat("a", 15)))
+	require.NoError(t, err)
+	assert.True(t, upload.Done())
+	assert.Equal(t, int64(15), used())
+
+	// Uploads rejected for their content type release their reservation too
+	b.limits.Policies[domain.PurposeAvatar] = domain.Policy{ContentTypes: []string{"image/*"}}
+	avatar, err := b.uploadUseCase.CreateUpload(ctx, usecases.CreateUploadDTO{Name: "avatar.png", AuthorID: authorID, Size: 5, Purpose: domain.PurposeAvatar})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), used())
+	_, err = b.uploadUseCase.WriteUpload(ctx, authorID, avatar.ID, 0, strings.NewReader("hello"))
+	assert.ErrorIs(t, err, domain.ErrContentType)
+	assert.Equal(t, int64(15), used())
+}
+
+func TestUploadUseCase_WriteUpload_Checksum(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	upload, err := b.uploadUseCase.CreateUpload(ctx, usecases.CreateUploadDTO{
+		Name:     "upload.txt",
+		AuthorID: authorID,
+		Size:     5,
+		Checksum: strings.Repeat("0", 64),
+	})
+	require.NoError(t, err)
+
+	// The done upload does not become a file when its content does not match the checksum of the client
+	_, err = b.uploadUseCase.WriteUpload(ctx, authorID, upload.ID, 0, strings.NewReader("hello"))
+	assert.ErrorIs(t, err, domain.ErrChecksum)
+	_, err = b.files.StatFile(ctx, upload.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	usage, err := b.limits.GetUsage(ctx, authorID)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Used)
+	assert.Empty(t, b.processor.enqueued)
+}
@@ -0,0 +1,290 @@
+package usecases
+
+import (
+	"Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+)
+
+// VariantImage is an encoded variant of an image.
+type VariantImage struct {
+	domain.Variant
+	Content []byte
+}
+
+// ImageProcessorInterface makes the variants of images.
+type ImageProcessorInterface interface {
+	// Supports reports whether images of the content type can be processed.
+	Supports(contentType string) bool
+	// MakeVariants decodes an image and encodes its variants without its metadata.
+	// It fails with domain.ErrNotImage if the content is not a supported image.
+	MakeVariants(content io.Reader, specs []domain.VariantSpec) ([]VariantImage, error)
+}
+
+type VariantRepositoryInterface interface {
+	PutVariant(ctx context.Context, fileID uuid.UUID, image VariantImage) error
+	StatVariant(ctx context.Context, fileID uuid.UUID, name string) (domain.Variant, error)
+	// GetVariant returns the content of a variant, the caller must close it.
+	GetVariant(ctx context.Context, fileID uuid.UUID, name string, opts domain.ReadOptions) (io.ReadCloser, error)
+	// DeleteVariants deletes all variants of a file.
+	DeleteVariants(ctx context.Context, fileID uuid.UUID) error
+}
+
+// FileProcessor derives content from created files, like the variants of images.
+type FileProcessor interface {
+	// Enqueue queues a created file to be processed in the background, it does not block.
+	Enqueue(file domain.File)
+	// Remove removes what was derived from a deleted file.
+	Remove(ctx context.Context, id uuid.UUID) error
+}
+
+var (
+	_ usecases.VariantUseCaseInterface = &VariantUseCase{}
+	_ FileProcessor                    = &VariantUseCase{}
+)
+
+// VariantUseCase makes resized variants of images in the background and serves them.
+type VariantUseCase struct {
+	Repository VariantRepositoryInterface
+	Files      FileRepositoryInterface
+	Processor  ImageProcessorInterface
+	MaxSize    int64 // bytes, larger images are not processed, not limited if 0
+	queue      chan domain.File
+	logger     *slog.Logger
+
+	// making holds the files whose variants are being made, so each image is decoded once at a time
+	making variantFlights
+	// requested bounds the images whose variants are made on request at the same time
+	requested chan struct{}
+}
+
+// variantFlights are the files whose variants are being made.
+type variantFlights struct {
+	m       sync.Mutex
+	running map[uuid.UUID]*variantFlight
+}
+
+// variantFlight is the making of the variants of a file, done is closed with its error set when it ends.
+type variantFlight struct {
+	done chan struct{}
+	err  error
+}
+
+// start returns the flight of a file, and reports whether it is a new one the caller must run and finish.
+func (f *variantFlights) start(id uuid.UUID) (*variantFlight, bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if flight, ok := f.running[id]; ok {
+		return flight, false
+	}
+	if f.running == nil {
+		f.running = make(map[uuid.UUID]*variantFlight)
+	}
+	flight := &variantFlight{done: make(chan struct{})}
+	f.running[id] = flight
+	return flight, true
+}
+
+func (f *variantFlights) finish(id uuid.UUID, flight *variantFlight, err error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	delete(f.running, id)
+	flight.err = err
+	close(flight.done)
+}
+
+func (v *VariantUseCase) Enqueue(file domain.File) {
+	const op = "VariantUseCase.Enqueue"
+
+	if !v.Processes(file) {
+		return
+	}
+
+	select {
+	case v.queue <- file:
+	default:
+		// Variants of dropped files are made when they are requested
+		v.logger.Warn(op, slog.Any("file", file.ID), slog.Any("error", "queue is full"))
+	}
+}
+
+// Run makes the variants of queued files with the given number of workers until ctx is done.
+func (v *VariantUseCase) Run(ctx context.Context, workers int) {
+	const op = "VariantUseCase.Run"
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case file := <-v.queue:
+					// Variants requested while the file was queued are being made already
+					flight, ok := v.making.start(file.ID)
+					if !ok {
+						continue
+					}
+					err := v.makeVariants(ctx, file)
+					v.making.finish(file.ID, flight, err)
+					if err != nil {
+						v.logger.Error(op, slog.Any("file", file.ID), slog.Any("error", err.Error()))
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (v *VariantUseCase) Remove(ctx context.Context, id uuid.UUID) error {
+	return v.Repository.DeleteVariants(ctx, id)
+}
+
+// StatVariant returns a variant of a file the user can read as a file.
+// Variants that are not made yet are made before it returns, requests of the same file wait for the same work.
+func (v *VariantUseCase) StatVariant(ctx context.Context, userID uuid.UUID, id uuid.UUID, name string) (domain.File, error) {
+	file, err := v.Files.StatFile(ctx, id)
+	if err != nil {
+		return domain.File{}, err
+	}
+	if !file.CanRead(userID) {
+		return domain.File{}, domain.ErrNotFound
+	}
+	if _, ok := domain.LookupVariant(name); !ok || !v.Processes(file) {
+		return domain.File{}, domain.ErrNotFound
+	}
+
+	variant, err := v.Repository.StatVariant(ctx, id, name)
+	if errors.Is(err, domain.ErrNotFound) {
+		// The file is still queued, was dropped from the queue or was uploaded before variants were made
+		if err := v.makeRequestedVariants(ctx, file); err != nil {
+			if errors.Is(err, domain.ErrNotImage) {
+				return domain.File{}, domain.ErrNotFound
+			}
+			return domain.File{}, err
+		}
+		variant, err = v.Repository.StatVariant(ctx, id, name)
+	}
+	if err != nil {
+		return domain.File{}, err
+	}
+
+	file.Name = variantName(file.Name, variant)
+	file.Size = variant.Size
+	file.ContentType = variant.ContentType
+	file.ETag = variant.ETag
+	file.LastModified = variant.LastModified
+	file.Checksum = ""
+	return file, nil
+}
+
+func (v *VariantUseCase) GetVariant(ctx context.Context, userID uuid.UUID, id uuid.UUID, name string, opts domain.ReadOptions) (io.ReadCloser, error) {
+	if _, err := v.StatVariant(ctx, userID, id, name); err != nil {
+		return nil, err
+	}
+	return v.Repository.GetVariant(ctx, id, name, opts)
+}
+
+// Processes reports whether variants are made of a file.
+func (v *VariantUseCase) Processes(file domain.File) bool {
+	return v.Processor.Supports(file.ContentType) && (v.MaxSize <= 0 || file.Size <= v.MaxSize)
+}
+
+// makeRequestedVariants makes the variants of an image for a request.
+// Images are decoded in memory, so requests wait for a free slot, or for the variants being made of the same file.
+func (v *VariantUseCase) makeRequestedVariants(ctx context.Context, file domain.File) error {
+	for {
+		flight, ok := v.making.start(file.ID)
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-flight.done:
+			}
+			// The request that made them went away, another one makes them then
+			if errors.Is(flight.err, context.Canceled) || errors.Is(flight.err, context.DeadlineExceeded) {
+				continue
+			}
+			return flight.err
+		}
+
+		err := v.makeWithSlot(ctx, file)
+		v.making.finish(file.ID, flight, err)
+		return err
+	}
+}
+
+// makeWithSlot makes the variants of an image once a slot for requested images is free.
+func (v *VariantUseCase) makeWithSlot(ctx context.Context, file domain.File) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case v.requested <- struct{}{}:
+	}
+	defer func() { <-v.requested }()
+
+	return v.makeVariants(ctx, file)
+}
+
+// makeVariants makes and stores all variants of an image file.
+func (v *VariantUseCase) makeVariants(ctx context.Context, file domain.File) error {
+	const op = "VariantUseCase.makeVariants"
+
+	content, err := v.Files.GetFile(ctx, file.ID, domain.ReadOptions{})
+	if err != nil {
+		return err
+	}
+	defer func(content io.ReadCloser) {
+		if err := content.Close(); err != nil {
+			v.logger.Error(op, slog.Any("error", err.Error()))
+		}
+	}(content)
+
+	images, err := v.Processor.MakeVariants(content, domain.Variants)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		if err := v.Repository.PutVariant(ctx, file.ID, image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// variantName returns the file name of a variant of a file, like photo-thumb.jpg for photo.png.
+func variantName(name string, variant domain.Variant) string {
+	ext := ".jpg"
+	if strings.HasPrefix(variant.ContentType, "image/png") {
+		ext = ".png"
+	}
+	return strings.TrimSuffix(name, path.Ext(name)) + "-" + variant.Name + ext
+}
+
+// NewVariantUseCase creates a new VariantUseCase with a queue of the given size,
+// the variants of requestWorkers images at most are made on request at the same time.
+func NewVariantUseCase(repository VariantRepositoryInterface, files FileRepositoryInterface, processor ImageProcessorInterface, maxSize int64, queueSize int, requestWorkers int, logger *slog.Logger) *VariantUseCase {
+	return &VariantUseCase{
+		Repository: repository,
+		Files:      files,
+		Processor:  processor,
+		MaxSize:    maxSize,
+		queue:      make(chan domain.File, queueSize),
+		requested:  make(chan struct{}, max(requestWorkers, 1)),
+		logger:     logger,
+	}
+}
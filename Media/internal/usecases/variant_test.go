@@ -0,0 +1,226 @@
+package usecases_test
+
+import (
+	contracts "Media/internal/contracts/usecases"
+	"Media/internal/domain"
+	inmemoryRepo "Media/internal/infrastructure/repositories/inmemory"
+	"Media/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pngHeader is the start of PNG images, content starting with it is detected as one.
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// countingImageProcessor makes empty variants of PNG images, and counts the images it decodes at the same time.
+// Images ending with "broken" cannot be decoded.
+// Each image waits for release before its variants are made if release is set.
+type countingImageProcessor struct {
+	release chan struct{}
+	started chan struct{}
+
+	m       sync.Mutex
+	calls   int
+	running int
+	peak    int
+}
+
+func (p *countingImageProcessor) Supports(contentType string) bool {
+	return contentType == "image/png"
+}
+
+func (p *countingImageProcessor) MakeVariants(content io.Reader, specs []domain.VariantSpec) ([]usecases.VariantImage, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	p.m.Lock()
+	p.calls++
+	p.running++
+	p.peak = max(p.peak, p.running)
+	p.m.Unlock()
+	defer func() {
+		p.m.Lock()
+		p.running--
+		p.m.Unlock()
+	}()
+
+	if p.started != nil {
+		p.started <- struct{}{}
+	}
+	if p.release != nil {
+		<-p.release
+	}
+	if strings.HasSuffix(string(data), "broken") {
+		return nil, domain.ErrNotImage
+	}
+
+	images := make([]usecases.VariantImage, 0, len(specs))
+	for _, spec := range specs {
+		images = append(images, usecases.VariantImage{
+			Variant: domain.Variant{Name: spec.Name, ContentType: "image/png"},
+			Content: []byte(pngHeader + spec.Name),
+		})
+	}
+	return images, nil
+}
+
+func (p *countingImageProcessor) stats() (int, int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.calls, p.peak
+}
+
+// newVariantUseCase returns a VariantUseCase over the files of a backend, it makes the variants of requestWorkers
+// images on request at the same time.
+func newVariantUseCase(b *backend, processor *countingImageProcessor, requestWorkers int) *usecases.VariantUseCase {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return usecases.NewVariantUseCase(inmemoryRepo.NewVariantRepository(), b.files, processor, 0, 8, requestWorkers, logger)
+}
+
+func createImage(t *testing.T, b *backend, authorID uuid.UUID, content string) uuid.UUID {
+	t.Helper()
+	content = pngHeader + content
+	id, err := b.fileUseCase.CreateFile(context.Background(), contracts.CreateFileDTO{
+		Name:     "image.png",
+		AuthorID: authorID,
+		Size:     int64(len(content)),
+		Content:  strings.NewReader(content),
+		Access:   domain.Access{Visibility: domain.VisibilityPublic},
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestVariantUseCase_StatVariant(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	ctx := context.Background()
+	authorID := uuid.New()
+	processor := &countingImageProcessor{}
+	vuc := newVariantUseCase(b, processor, 1)
+	imageID := createImage(t, b, authorID, "image")
+	textID := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityPublic})
+	privateID := createFile(t, b, authorID, domain.Access{Visibility: domain.VisibilityPrivate})
+
+	// Variants are made on the first request, and served from the repository then
+	file, err := vuc.StatVariant(ctx, uuid.Nil, imageID, domain.VariantThumb)
+	require.NoError(t, err)
+	assert.Equal(t, "image-thumb.png", file.Name)
+	assert.Equal(t, "image/png", file.ContentType)
+	assert.Empty(t, file.Checksum)
+
+	_, err = vuc.StatVariant(ctx, uuid.Nil, imageID, domain.VariantOriginal)
+	require.NoError(t, err)
+	calls, _ := processor.stats()
+	assert.Equal(t, 1, calls)
+
+	_, err = vuc.StatVariant(ctx, uuid.Nil, imageID, "large")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = vuc.StatVariant(ctx, uuid.Nil, textID, domain.VariantThumb)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = vuc.StatVariant(ctx, uuid.New(), privateID, domain.VariantThumb)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestVariantUseCase_StatVariant_NotImage(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	processor := &countingImageProcessor{}
+	vuc := newVariantUseCase(b, processor, 1)
+
+	// Files that only look like an image have no variants
+	id := createImage(t, b, uuid.New(), "broken")
+
+	_, err := vuc.StatVariant(context.Background(), uuid.Nil, id, domain.VariantThumb)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestVariantUseCase_StatVariant_SameImage(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	processor := &countingImageProcessor{release: make(chan struct{}), started: make(chan struct{}, 8)}
+	vuc := newVariantUseCase(b, processor, 4)
+	id := createImage(t, b, uuid.New(), "image")
+
+	const requests = 8
+	errs := make(chan error, requests)
+	for range requests {
+		go func() {
+			_, err := vuc.StatVariant(context.Background(), uuid.Nil, id, domain.VariantMedium)
+			errs <- err
+		}()
+	}
+
+	// The image is decoded once for all the requests
+	<-processor.started
+	time.Sleep(10 * time.Millisecond)
+	close(processor.release)
+	for range requests {
+		assert.NoError(t, <-errs)
+	}
+	calls, _ := processor.stats()
+	assert.Equal(t, 1, calls)
+}
+
+func TestVariantUseCase_StatVariant_Bounded(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	processor := &countingImageProcessor{release: make(chan struct{}), started: make(chan struct{}, 8)}
+	vuc := newVariantUseCase(b, processor, 2)
+
+	const images = 6
+	errs := make(chan error, images)
+	for range images {
+		id := createImage(t, b, uuid.New(), "image")
+		go func() {
+			_, err := vuc.StatVariant(context.Background(), uuid.Nil, id, domain.VariantThumb)
+			errs <- err
+		}()
+	}
+
+	// Requests of other images wait for a free slot
+	<-processor.started
+	<-processor.started
+	time.Sleep(10 * time.Millisecond)
+	close(processor.release)
+	for range images {
+		assert.NoError(t, <-errs)
+	}
+	calls, peak := processor.stats()
+	assert.Equal(t, images, calls)
+	assert.Equal(t, 2, peak)
+}
+
+func TestVariantUseCase_StatVariant_Cancelled(t *testing.T) {
+	b := newBackend(t, nil, 0)
+	processor := &countingImageProcessor{release: make(chan struct{}), started: make(chan struct{}, 8)}
+	vuc := newVariantUseCase(b, processor, 1)
+	first := createImage(t, b, uuid.New(), "image")
+	second := createImage(t, b, uuid.New(), "image")
+
+	done := make(chan error)
+	go func() {
+		_, err := vuc.StatVariant(context.Background(), uuid.Nil, first, domain.VariantThumb)
+		done <- err
+	}()
+	<-processor.started
+
+	// A request waiting for a slot gives up with its context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := vuc.StatVariant(ctx, uuid.Nil, second, domain.VariantThumb)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(processor.release)
+	require.NoError(t, <-done)
+
+	// The variants of the image are made by the next request
+	_, err = vuc.StatVariant(context.Background(), uuid.Nil, second, domain.VariantThumb)
+	assert.NoError(t, err)
+}
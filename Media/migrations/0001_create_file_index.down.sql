@@ -0,0 +1,3 @@
+-- Drop the file index
+DROP TABLE IF EXISTS file_references;
+DROP TABLE IF EXISTS file_meta;
@@ -0,0 +1,36 @@
+-- Create the file index
+-- Tables that were created by the service before it had migrations are kept, their IDs become UUIDs
+CREATE TABLE IF NOT EXISTS file_meta (
+    id           UUID PRIMARY KEY,
+    author_id    UUID        NOT NULL,
+    name         TEXT        NOT NULL DEFAULT '',
+    size         BIGINT      NOT NULL DEFAULT 0,
+    content_type TEXT        NOT NULL DEFAULT '',
+    checksum     TEXT        NOT NULL DEFAULT '',                          -- Hex encoded SHA-256 of the content, empty if unknown
+    visibility   TEXT        NOT NULL DEFAULT 'private',
+    shared_with  TEXT        NOT NULL DEFAULT '',                          -- Comma separated user IDs
+    purpose      TEXT        NOT NULL DEFAULT 'generic',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_at  TIMESTAMP WITH TIME ZONE                                  -- When the file lost its last reference, NULL if it never had one
+);
+
+ALTER TABLE file_meta
+    ALTER COLUMN id TYPE UUID USING id::uuid,
+    ALTER COLUMN author_id TYPE UUID USING author_id::uuid;
+
+CREATE INDEX IF NOT EXISTS idx_file_metas_author_created ON file_meta (author_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_file_meta_checksum ON file_meta (checksum);
+CREATE INDEX IF NOT EXISTS idx_file_meta_purpose ON file_meta (purpose);
+CREATE INDEX IF NOT EXISTS idx_file_meta_created_at ON file_meta (created_at);
+
+-- Create the references to files, like the posts they are attached to
+CREATE TABLE IF NOT EXISTS file_references (
+    file_id    UUID NOT NULL,
+    owner      TEXT NOT NULL,                                              -- Like post:<id> or user:<id>
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (file_id, owner)
+);
+
+ALTER TABLE file_references
+    ALTER COLUMN file_id TYPE UUID USING file_id::uuid;
@@ -0,0 +1,3 @@
+-- Drop the counters
+DROP TABLE IF EXISTS usages;
+DROP TABLE IF EXISTS blob_refs;
@@ -0,0 +1,11 @@
+-- Create the numbers of files referencing each blob, blobs are removed with their last reference
+CREATE TABLE IF NOT EXISTS blob_refs (
+    checksum TEXT PRIMARY KEY,                                             -- Hex encoded SHA-256 of the content
+    refs     BIGINT NOT NULL DEFAULT 0
+);
+
+-- Create the storage used by authors, with the declared size of their unfinished uploads
+CREATE TABLE IF NOT EXISTS usages (
+    author_id UUID PRIMARY KEY,
+    used      BIGINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,217 @@
+package jwtservice
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Errors
+var (
+	ErrUnknownKey = errors.New("unknown key")
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// KeySource provides the public keys tokens are verified with
+type KeySource interface {
+	Key(kid string) (*rsa.PublicKey, error)
+}
+
+// StaticKeySource is a KeySource with a fixed set of keys
+type StaticKeySource struct {
+	keys map[string]*rsa.PublicKey
+}
+
+// NewStaticKeySource creates a new StaticKeySource
+func NewStaticKeySource(keys map[string]*rsa.PublicKey) *StaticKeySource {
+	return &StaticKeySource{
+		keys: keys,
+	}
+}
+
+// NewPEMKeySource creates a StaticKeySource with a single key read from a PEM file
+func NewPEMKeySource(publicKeyPath string) (*StaticKeySource, error) {
+	f, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return NewStaticKeySource(map[string]*rsa.PublicKey{"": key}), nil
+}
+
+// Key returns the key with the given ID
+func (s *StaticKeySource) Key(kid string) (*rsa.PublicKey, error) {
+	return lookupKey(s.keys, kid)
+}
+
+// JWKSKeySource is a KeySource that fetches keys from a JWKS document.
+//
+// Keys are refetched once they are older than the refresh interval, and when a token
+// is signed with an unknown key, so keys can be rotated without restarting the service.
+type JWKSKeySource struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	m         sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSKeySource creates a new JWKSKeySource
+func NewJWKSKeySource(url string, refreshInterval time.Duration, client *http.Client) *JWKSKeySource {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &JWKSKeySource{
+		url:                url,
+		client:             client,
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: 10 * time.Second,
+		keys:               make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Key returns the key with the given ID, fetching the JWKS document if needed
+func (s *JWKSKeySource) Key(kid string) (*rsa.PublicKey, error) {
+	s.m.RLock()
+	key, err := lookupKey(s.keys, kid)
+	stale := time.Since(s.fetchedAt) > s.refreshInterval
+	s.m.RUnlock()
+
+	if err == nil && !stale {
+		return key, nil
+	}
+
+	if refreshErr := s.Refresh(context.Background()); refreshErr != nil {
+		if err == nil {
+			// Keep serving the cached key while the JWKS endpoint is unavailable
+			return key, nil
+		}
+		return nil, refreshErr
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return lookupKey(s.keys, kid)
+}
+
+// Refresh fetches the JWKS document and replaces the cached keys.
+// Refreshes more frequent than once in ten seconds are skipped.
+func (s *JWKSKeySource) Refresh(ctx context.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < s.minRefreshInterval {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys, err := set.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+
+	return nil
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// PublicKeys returns the RSA signing keys of the set by their IDs
+func (s JWKS) PublicKeys() (map[string]*rsa.PublicKey, error) {
+	keys := make(map[string]*rsa.PublicKey, len(s.Keys))
+	for _, k := range s.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, k.Kid)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, k.Kid)
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, k.Kid)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// lookupKey returns the key with the given ID.
+// Tokens without a key ID are accepted only when there is a single key.
+func lookupKey(keys map[string]*rsa.PublicKey, kid string) (*rsa.PublicKey, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	if len(keys) == 1 {
+		for id, key := range keys {
+			if kid == "" || id == "" {
+				return key, nil
+			}
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
@@ -0,0 +1,75 @@
+package jwtservice
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func toJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestVerifier_Parse_JWKS(t *testing.T) {
+	first := generateKey(t)
+	second := generateKey(t)
+
+	var set atomic.Value
+	set.Store(JWKS{Keys: []JWK{toJWK("first", &first.PublicKey)}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(set.Load())
+	}))
+	defer srv.Close()
+
+	keys := NewJWKSKeySource(srv.URL, time.Hour, srv.Client())
+	keys.minRefreshInterval = 0
+	verifier := NewVerifier(keys)
+
+	_, err := verifier.Parse(signToken(t, first, "first"))
+	assert.NoError(t, err)
+
+	// Rotate: publish the second key next to the first one
+	set.Store(JWKS{Keys: []JWK{toJWK("first", &first.PublicKey), toJWK("second", &second.PublicKey)}})
+
+	_, err = verifier.Parse(signToken(t, second, "second"))
+	assert.NoError(t, err)
+
+	_, err = verifier.Parse(signToken(t, first, "first"))
+	assert.NoError(t, err)
+}
+
+func TestVerifier_Parse_JWKS_UnknownKey(t *testing.T) {
+	known := generateKey(t)
+	unknown := generateKey(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(JWKS{Keys: []JWK{
+			toJWK("known", &known.PublicKey),
+			toJWK("other", &generateKey(t).PublicKey),
+		}})
+	}))
+	defer srv.Close()
+
+	verifier := NewVerifier(NewJWKSKeySource(srv.URL, time.Hour, srv.Client()))
+
+	_, err := verifier.Parse(signToken(t, unknown, "unknown"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = verifier.Parse(signToken(t, known, ""))
+	assert.Equal(t, ErrInvalidToken, err)
+}
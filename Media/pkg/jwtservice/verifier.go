@@ -0,0 +1,44 @@
+package jwtservice
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Errors
+var (
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Verifier is a parser for RS256 tokens signed by the SSO service
+type Verifier struct {
+	keys KeySource
+}
+
+// NewVerifier creates a new Verifier
+func NewVerifier(keys KeySource) *Verifier {
+	return &Verifier{
+		keys: keys,
+	}
+}
+
+// Parse parses and verifies an access token string.
+// Tokens are verified with the key of their kid header, so SSO can rotate its keys.
+// Refresh tokens are signed with the same keys, so tokens with a "type" claim are rejected.
+func (v *Verifier) Parse(tokenString string) (*jwt.Token, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if _, ok := claims["type"]; ok {
+		return nil, ErrInvalidToken
+	}
+
+	return token, nil
+}
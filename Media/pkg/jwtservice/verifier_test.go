@@ -0,0 +1,126 @@
+package jwtservice
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return key
+}
+
+func signClaims(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return signed
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	return signClaims(t, jwt.SigningMethodRS256, key, kid, jwt.MapClaims{
+		"sub": "sub",
+		"exp": time.Now().Add(15 * time.Minute).Unix(),
+	})
+}
+
+func TestVerifier_Parse_PEM(t *testing.T) {
+	key := generateKey(t)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	path := filepath.Join(t.TempDir(), "public.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	keys, err := NewPEMKeySource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	token, err := NewVerifier(keys).Parse(signToken(t, key, ""))
+
+	assert.NoError(t, err)
+	sub, err := token.Claims.GetSubject()
+	assert.NoError(t, err)
+	assert.Equal(t, "sub", sub)
+}
+
+func TestVerifier_Parse_Kid(t *testing.T) {
+	first := generateKey(t)
+	second := generateKey(t)
+	verifier := NewVerifier(NewStaticKeySource(map[string]*rsa.PublicKey{
+		"first":  &first.PublicKey,
+		"second": &second.PublicKey,
+	}))
+
+	_, err := verifier.Parse(signToken(t, first, "first"))
+	assert.NoError(t, err)
+
+	_, err = verifier.Parse(signToken(t, second, "second"))
+	assert.NoError(t, err)
+
+	// The key is chosen by the kid, not tried one after the other
+	_, err = verifier.Parse(signToken(t, second, "first"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = verifier.Parse(signToken(t, first, "unknown"))
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestVerifier_Parse_Invalid(t *testing.T) {
+	key := generateKey(t)
+	verifier := NewVerifier(NewStaticKeySource(map[string]*rsa.PublicKey{"": &key.PublicKey}))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "malformed", token: "token"},
+		{name: "foreign key", token: signToken(t, generateKey(t), "")},
+		{name: "HS256", token: signClaims(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+			"sub": "sub",
+			"exp": time.Now().Add(15 * time.Minute).Unix(),
+		})},
+		{name: "expired", token: signClaims(t, jwt.SigningMethodRS256, key, "", jwt.MapClaims{
+			"sub": "sub",
+			"exp": time.Now().Add(-time.Minute).Unix(),
+		})},
+		{name: "refresh token", token: signClaims(t, jwt.SigningMethodRS256, key, "", jwt.MapClaims{
+			"sub":  "sub",
+			"exp":  time.Now().Add(24 * time.Hour).Unix(),
+			"jti":  "jti",
+			"fam":  "fam",
+			"type": "refresh",
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Parse(tt.token)
+
+			assert.Equal(t, ErrInvalidToken, err)
+		})
+	}
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"Posts/internal/infrastructure/broker"
 	"Posts/internal/infrastructure/graph"
+	"Posts/internal/infrastructure/graph/middleware"
 	"Posts/internal/infrastructure/graph/resolvers"
+	"Posts/internal/infrastructure/media"
 	"Posts/internal/infrastructure/repository/sql"
+	"Posts/internal/infrastructure/sso"
 	"Posts/internal/usecases"
 	"Posts/pkg/jwtservice"
+	"context"
 	"fmt"
 	"gorm.io/driver/postgres"
+	"net/http"
 
 	inmemory "Posts/internal/infrastructure/repository/in-memory"
 	defaultLogger "log"
@@ -37,6 +43,7 @@ func main() {
 	log.Info("Logger initialized", slog.Any("env", cfg.Env))
 
 	var db *gorm.DB
+	var connStr string
 	var err error
 
 	// Init database
@@ -45,7 +52,7 @@ func main() {
 	} else {
 		log.Info("Using Postgres database", slog.Any("host", cfg.Postgres.Host))
 
-		connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		connStr = fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 			cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Pass, cfg.Postgres.Name)
 
 		db, err = gorm.Open(postgres.Open(connStr), &gorm.Config{TranslateError: true})
@@ -64,22 +71,54 @@ func main() {
 	// Init repositories
 	var postRepo usecases.PostRepository
 	var commentRepo usecases.CommentRepository
-	var userRepo usecases.UserRepository
+	var revisionRepo usecases.RevisionRepository
 
 	if cfg.UseDatabase == nil || !*cfg.UseDatabase {
 		postRepo = inmemory.NewPostInMemoryRepository(log)
 		commentRepo = inmemory.NewCommentInMemoryRepository(log)
-		userRepo = inmemory.NewUserInMemoryRepository(log)
+		revisionRepo = inmemory.NewRevisionInMemoryRepository(log)
 	} else {
 		postRepo = sql.NewPostSQLRepository(db, log)
 		commentRepo = sql.NewCommentSQLRepository(db, log)
-		userRepo = sql.NewUserSQLRepository(db, log)
+		revisionRepo = sql.NewRevisionSQLRepository(db, log)
+	}
+
+	// Init broker
+	var commentBroker usecases.CommentBroker
+
+	if cfg.UseDatabase == nil || !*cfg.UseDatabase {
+		commentBroker = broker.NewCommentLocalBroker(cfg.Server.SubscriptionBuffer, log)
+	} else {
+		postgresBroker := broker.NewCommentPostgresBroker(db, connStr, commentRepo, cfg.Server.SubscriptionBuffer, log)
+		go postgresBroker.Listen(context.Background())
+		commentBroker = postgresBroker
+	}
+
+	// Init Media client
+	var mediaClient usecases.MediaClient
+
+	if cfg.Media.URL == "" {
+		log.Info("Media is not configured, attachments are disabled")
+	} else {
+		log.Info("Using Media", slog.Any("url", cfg.Media.URL))
+		mediaClient = media.NewClient(cfg.Media.URL, cfg.Media.PublicURL, middleware.GetToken, &http.Client{Timeout: cfg.Media.Timeout})
+	}
+
+	// Init user directory
+	var userDirectory usecases.UserDirectory
+
+	if cfg.SSO.URL == "" {
+		log.Warn("SSO is not configured, users have no names or profiles")
+		userDirectory = sso.AnonymousDirectory{}
+	} else {
+		log.Info("Using SSO", slog.Any("url", cfg.SSO.URL))
+		userDirectory = sso.NewClient(cfg.SSO.URL, &http.Client{Timeout: cfg.SSO.Timeout})
 	}
 
 	// Init UseCases
-	postUseCase := usecases.NewPostUseCase(postRepo)
-	commentUseCase := usecases.NewCommentUseCase(commentRepo)
-	userUseCase := usecases.NewUserUseCase(userRepo)
+	postUseCase := usecases.NewPostUseCase(postRepo, revisionRepo, mediaClient, cfg.Posts.MaxAttachments)
+	commentUseCase := usecases.NewCommentUseCase(commentRepo, postRepo, commentBroker, revisionRepo, cfg.Comments.MaxDepth)
+	userUseCase := usecases.NewUserUseCase(userDirectory, mediaClient)
 
 	// Init Resolver and Schema
 	resolver := resolvers.NewResolver(
@@ -90,16 +129,37 @@ func main() {
 	)
 	schema := graph.NewExecutableSchema(graph.Config{Resolvers: resolver})
 
-	// Init jwt Service
-	jwtGen := jwtservice.NewGenerator(cfg.Tokens.Secret, cfg.Tokens.AccessTTL, cfg.Tokens.RefreshTTL)
+	// Init token parser
+	var tokenParser middleware.TokenParser
+	switch {
+	case cfg.Tokens.JWKSURL != "":
+		log.Info("Using JWKS keys", slog.Any("url", cfg.Tokens.JWKSURL))
+		keys := jwtservice.NewJWKSKeySource(cfg.Tokens.JWKSURL, cfg.Tokens.JWKSRefresh, nil)
+		if err := keys.Refresh(context.Background()); err != nil {
+			log.Warn("Failed to fetch JWKS keys, will retry on first request", slog.Any("error", err.Error()))
+		}
+		tokenParser = jwtservice.NewVerifier(keys)
+	case cfg.Tokens.PublicKeyPath != "":
+		log.Info("Using public key", slog.Any("path", cfg.Tokens.PublicKeyPath))
+		keys, err := jwtservice.NewPEMKeySource(cfg.Tokens.PublicKeyPath)
+		if err != nil {
+			log.Error("Failed to read public key", slog.Any("error", err.Error()))
+			return
+		}
+		tokenParser = jwtservice.NewVerifier(keys)
+	default:
+		log.Info("Using shared secret")
+		tokenParser = jwtservice.NewGenerator(cfg.Tokens.Secret, cfg.Tokens.AccessTTL, cfg.Tokens.RefreshTTL)
+	}
 
 	// Init server
 	srv := graph.NewServer(
 		"8080",
-		jwtGen,
+		tokenParser,
 		log,
 		schema,
 		true,
+		cfg.Env == Production,
 		postUseCase,
 		commentUseCase,
 		userUseCase,
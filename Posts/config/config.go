@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
@@ -14,12 +15,44 @@ type Config struct {
 	UseDatabase *bool    `yaml:"use_database" env-required:"false" env-default:"false"`
 	Postgres    Postgres `yaml:"postgres"`
 	Tokens      Tokens   `yaml:"tokens"`
+	Comments    Comments `yaml:"comments"`
+	Posts       Posts    `yaml:"posts"`
+	Media       Media    `yaml:"media"`
+	SSO         SSO      `yaml:"sso"`
 }
 
 // Server is the configuration for the server.
 type Server struct {
-	Address string        `yaml:"address" env-required:"true"`
-	Timeout time.Duration `yaml:"timeout" env-required:"true"`
+	Address            string        `yaml:"address" env-required:"true"`
+	Timeout            time.Duration `yaml:"timeout" env-required:"true"`
+	SubscriptionBuffer int           `yaml:"subscription_buffer" env-default:"16"` // per-subscriber buffer, slower subscribers are disconnected
+}
+
+// Comments is the configuration for comment threads.
+type Comments struct {
+	MaxDepth int `yaml:"max_depth" env-default:"10"` // maximum nesting of replies, not limited if 0
+}
+
+// Posts is the configuration for posts.
+type Posts struct {
+	MaxAttachments int `yaml:"max_attachments" env-default:"10"` // maximum number of files attached to a post, not limited if 0
+}
+
+// Media is the configuration for the client of the Media service.
+//
+// Attachments are disabled if URL is not set.
+type Media struct {
+	URL       string        `yaml:"url"`
+	PublicURL string        `yaml:"public_url"` // the address clients reach Media at, defaults to URL
+	Timeout   time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+// SSO is the configuration for the client of the HTTP server of SSO, which owns the users.
+//
+// Users have no names or profiles if URL is not set.
+type SSO struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout" env-default:"10s"`
 }
 
 // Postgres is the configuration for the PostgreSQL database.
@@ -32,10 +65,16 @@ type Postgres struct {
 }
 
 // Tokens is the configuration for the JWT tokens.
+//
+// Tokens issued by SSO are verified with RS256 keys from JWKSURL or PublicKeyPath.
+// If neither is set, tokens are verified with the HS256 Secret, which is then required.
 type Tokens struct {
-	Secret     string        `yaml:"secret" env-required:"true"`
-	AccessTTL  time.Duration `yaml:"access_ttl" env-required:"true"`
-	RefreshTTL time.Duration `yaml:"refresh_ttl" env-required:"true"`
+	Secret        string        `yaml:"secret"`
+	PublicKeyPath string        `yaml:"public_key_path"`
+	JWKSURL       string        `yaml:"jwks_url"`
+	JWKSRefresh   time.Duration `yaml:"jwks_refresh" env-default:"5m"`
+	AccessTTL     time.Duration `yaml:"access_ttl" env-required:"true"`
+	RefreshTTL    time.Duration `yaml:"refresh_ttl" env-required:"true"`
 }
 
 // MustParseConfig parses the configuration from the given path.
@@ -47,9 +86,24 @@ func MustParseConfig(path string) Config {
 		panic(err)
 	}
 
+	if err := cfg.Tokens.Validate(); err != nil {
+		panic(err)
+	}
+
 	return cfg
 }
 
+// ErrNoTokenKey is returned when no key to verify tokens with is configured.
+var ErrNoTokenKey = errors.New("tokens: one of jwks_url, public_key_path or secret is required")
+
+// Validate checks that tokens can be verified, an empty HS256 secret would accept tokens signed by anyone.
+func (t Tokens) Validate() error {
+	if t.JWKSURL == "" && t.PublicKeyPath == "" && t.Secret == "" {
+		return ErrNoTokenKey
+	}
+	return nil
+}
+
 // FetchPath fetches the path to the configuration file.
 func FetchPath() string {
 	var path string
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTokens_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens Tokens
+		err    error
+	}{
+		{name: "jwks url", tokens: Tokens{JWKSURL: "http://localhost:8081/.well-known/jwks.json"}},
+		{name: "public key", tokens: Tokens{PublicKeyPath: "keys/public.pem"}},
+		{name: "secret", tokens: Tokens{Secret: "secret"}},
+		{name: "no key", tokens: Tokens{}, err: ErrNoTokenKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.tokens.Validate())
+		})
+	}
+}
+
+func TestMustParseConfig_NoTokenKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "env: test\nuse_database: false\nserver:\n  address: \":8080\"\n  timeout: 10s\ntokens:\n  access_ttl: 15m\n  refresh_ttl: 24h\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assert.PanicsWithValue(t, ErrNoTokenKey, func() { MustParseConfig(path) })
+}
+
+func TestMustParseConfig_Example(t *testing.T) {
+	assert.NotPanics(t, func() { MustParseConfig("example.yaml") })
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Attachment is a file of the Media service attached to a post.
+// Its name, content type and size are copied from the file when it is attached, files do not change.
+type Attachment struct {
+	FileID      uuid.UUID `json:"file_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+}
+
+// MediaVisibilityPublic is the visibility of the files of the Media service anyone can read.
+const MediaVisibilityPublic = "public"
+
+// MediaFile is a file stored by the Media service.
+type MediaFile struct {
+	ID          uuid.UUID   `json:"id"`
+	AuthorID    uuid.UUID   `json:"authorId"`
+	Name        string      `json:"name"`
+	ContentType string      `json:"contentType"`
+	Size        int64       `json:"size"`
+	Access      MediaAccess `json:"access"`
+}
+
+// MediaAccess is who may read a file of the Media service.
+type MediaAccess struct {
+	Visibility string `json:"visibility"` // public, shared or private
+}
+
+// Public reports whether anyone can read the file, like the readers of the posts it is attached to.
+func (f MediaFile) Public() bool {
+	return f.Access.Visibility == MediaVisibilityPublic
+}
+
+// AttachmentOf returns the attachment of a file.
+func AttachmentOf(file MediaFile) Attachment {
+	return Attachment{
+		FileID:      file.ID,
+		Name:        file.Name,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+	}
+}
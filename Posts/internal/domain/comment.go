@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+// DeletedCommentContent replaces the content of deleted comments.
+const DeletedCommentContent = "[deleted]"
+
+// MaxCommentLength is the maximum length of a comment.
+const MaxCommentLength = 2000
+
 // Comment is a comment on a post in the domain.
 type Comment struct {
 	ID        uuid.UUID  `json:"id"`
@@ -14,6 +20,7 @@ type Comment struct {
 	AuthorID  uuid.UUID  `json:"author"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
 }
 
 // GetID returns the ID of the comment.
@@ -25,3 +32,52 @@ func (c *Comment) GetID() uuid.UUID {
 func (c *Comment) SetID(id uuid.UUID) {
 	c.ID = id
 }
+
+// GetCreatedAt returns the creation time of the comment.
+func (c *Comment) GetCreatedAt() time.Time {
+	return c.CreatedAt
+}
+
+// SetCreatedAt sets the creation time of the comment.
+func (c *Comment) SetCreatedAt(createdAt time.Time) {
+	c.CreatedAt = createdAt
+}
+
+// IsDeleted reports whether the comment is deleted.
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// Edit replaces the content of the comment and returns a revision with the previous version.
+// Nothing is edited nor returned if the content stays the same.
+func (c *Comment) Edit(editorID uuid.UUID, content string, at time.Time) (*Revision, error) {
+	if c.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+	if len(content) > MaxCommentLength {
+		return nil, ErrCommentIsTooLong
+	}
+	if content == c.Content {
+		return nil, nil
+	}
+
+	revision := &Revision{
+		SubjectID: c.ID,
+		PostID:    c.PostID,
+		Content:   c.Content,
+		EditorID:  editorID,
+		CreatedAt: at,
+	}
+
+	c.Content = content
+	c.UpdatedAt = at
+
+	return revision, nil
+}
+
+// Delete turns the comment into a tombstone, so replies keep their parent.
+func (c *Comment) Delete(at time.Time) {
+	c.Content = DeletedCommentContent
+	c.UpdatedAt = at
+	c.DeletedAt = &at
+}
@@ -7,4 +7,22 @@ var (
 	ErrNotFound         = errors.New("not found")
 	ErrAlreadyExists    = errors.New("already exists")
 	ErrCommentIsTooLong = errors.New("comment is too long")
+	ErrInvalidPageSize  = errors.New("invalid page size")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrNotAuthor        = errors.New("you are not the author")
+	ErrCommentDeleted   = errors.New("comment is deleted")
+	ErrImpersonation    = errors.New("acting on behalf of another user requires the admin role")
+	ErrPostNotFound     = errors.New("post not found")
+	ErrCommentsDisabled = errors.New("comments are disabled for this post")
+	ErrParentNotFound   = errors.New("parent comment not found")
+	ErrParentMismatch   = errors.New("parent comment belongs to another post")
+	ErrThreadTooDeep    = errors.New("comment thread is too deep")
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrUnauthenticated  = errors.New("not authenticated")
+
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrAttachmentNotOwned  = errors.New("attachments must be files of the author of the post")
+	ErrAttachmentNotPublic = errors.New("attachments must be public files")
+	ErrTooManyAttachments  = errors.New("too many attachments")
+	ErrAttachmentsDisabled = errors.New("attachments are disabled")
 )
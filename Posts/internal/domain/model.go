@@ -1,9 +1,14 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 // Model is a model in the domain.
 type Model interface {
 	GetID() uuid.UUID
 	SetID(id uuid.UUID)
+	GetCreatedAt() time.Time
+	SetCreatedAt(createdAt time.Time)
 }
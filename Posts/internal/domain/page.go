@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"bytes"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Page sizes.
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// Cursor is a position in a list ordered by creation time and ID.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf returns the cursor of a model.
+func CursorOf(m Model) Cursor {
+	return Cursor{CreatedAt: m.GetCreatedAt(), ID: m.GetID()}
+}
+
+// Less reports whether c comes before other.
+func (c Cursor) Less(other Cursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.Before(other.CreatedAt)
+	}
+	return bytes.Compare(c.ID[:], other.ID[:]) < 0
+}
+
+// PageRequest requests a page of a list ordered by creation time and ID.
+//
+// First and After page forward, Last and Before page backward.
+type PageRequest struct {
+	First  int
+	After  *Cursor
+	Last   int
+	Before *Cursor
+}
+
+// Validate checks that the request pages in a single direction with an allowed size.
+func (r PageRequest) Validate() error {
+	if r.First < 0 || r.Last < 0 || r.First > MaxPageSize || r.Last > MaxPageSize {
+		return ErrInvalidPageSize
+	}
+	if (r.First > 0) == (r.Last > 0) {
+		return ErrInvalidPageSize
+	}
+	if r.First > 0 && r.Before != nil || r.Last > 0 && r.After != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// Backward reports whether the request pages backward.
+func (r PageRequest) Backward() bool {
+	return r.Last > 0
+}
+
+// Size returns the number of items requested.
+func (r PageRequest) Size() int {
+	if r.Backward() {
+		return r.Last
+	}
+	return r.First
+}
+
+// Page is a page of a list ordered by creation time and ID.
+type Page[T Model] struct {
+	Items           []T
+	HasNextPage     bool
+	HasPreviousPage bool
+}
+
+// NewPage creates a page from up to Size()+1 items fetched in the direction of the request,
+// the extra item only tells whether there are more items.
+func NewPage[T Model](items []T, r PageRequest) Page[T] {
+	more := len(items) > r.Size()
+	if more {
+		items = items[:r.Size()]
+	}
+
+	if !r.Backward() {
+		return Page[T]{Items: items, HasNextPage: more}
+	}
+
+	reversed := make([]T, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+	return Page[T]{Items: reversed, HasPreviousPage: more}
+}
@@ -7,13 +7,14 @@ import (
 
 // Post is a post in the domain.
 type Post struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	Content       string    `json:"content"`
-	AuthorID      uuid.UUID `json:"author"`
-	AllowComments bool      `json:"allow_comments"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uuid.UUID    `json:"id"`
+	Title         string       `json:"title"`
+	Content       string       `json:"content"`
+	AuthorID      uuid.UUID    `json:"author"`
+	AllowComments bool         `json:"allow_comments"`
+	Attachments   []Attachment `json:"attachments"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
 }
 
 // GetID returns the ID of the post.
@@ -26,6 +27,16 @@ func (p *Post) SetID(id uuid.UUID) {
 	p.ID = id
 }
 
+// GetCreatedAt returns the creation time of the post.
+func (p *Post) GetCreatedAt() time.Time {
+	return p.CreatedAt
+}
+
+// SetCreatedAt sets the creation time of the post.
+func (p *Post) SetCreatedAt(createdAt time.Time) {
+	p.CreatedAt = createdAt
+}
+
 // DisableComments disables comments on the post.
 func (p *Post) DisableComments() {
 	p.AllowComments = false
@@ -35,3 +46,30 @@ func (p *Post) DisableComments() {
 func (p *Post) EnableComments() {
 	p.AllowComments = true
 }
+
+// Edit replaces the title and content of the post and returns a revision with the previous version.
+// Nil values are left unchanged, and nothing is edited nor returned if the post stays the same.
+func (p *Post) Edit(editorID uuid.UUID, title *string, content *string, at time.Time) *Revision {
+	if (title == nil || *title == p.Title) && (content == nil || *content == p.Content) {
+		return nil
+	}
+
+	revision := &Revision{
+		SubjectID: p.ID,
+		PostID:    p.ID,
+		Title:     p.Title,
+		Content:   p.Content,
+		EditorID:  editorID,
+		CreatedAt: at,
+	}
+
+	if title != nil {
+		p.Title = *title
+	}
+	if content != nil {
+		p.Content = *content
+	}
+	p.UpdatedAt = at
+
+	return revision
+}
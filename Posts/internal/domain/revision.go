@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Revision is a previous version of an edited post or comment.
+type Revision struct {
+	ID        uuid.UUID `json:"id"`
+	SubjectID uuid.UUID `json:"subject"` // ID of the post or comment
+	PostID    uuid.UUID `json:"post"`    // the post itself, or the post of the comment, its revisions go with it
+	Title     string    `json:"title"`   // empty for comments
+	Content   string    `json:"content"`
+	EditorID  uuid.UUID `json:"editor"`
+	CreatedAt time.Time `json:"created_at"` // time of the edit that replaced this version
+}
+
+// GetID returns the ID of the revision.
+func (r *Revision) GetID() uuid.UUID {
+	return r.ID
+}
+
+// SetID sets the ID of the revision.
+func (r *Revision) SetID(id uuid.UUID) {
+	r.ID = id
+}
+
+// GetCreatedAt returns the creation time of the revision.
+func (r *Revision) GetCreatedAt() time.Time {
+	return r.CreatedAt
+}
+
+// SetCreatedAt sets the creation time of the revision.
+func (r *Revision) SetCreatedAt(createdAt time.Time) {
+	r.CreatedAt = createdAt
+}
@@ -6,11 +6,30 @@ import (
 )
 
 // User is a user in the domain.
+//
+// Users are owned by SSO, Posts only reads them. Name is the username of the user,
+// and the display name, bio and avatar are their public profile.
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	DisplayName string     `json:"display_name"`
+	Bio         string     `json:"bio"`
+	AvatarID    *uuid.UUID `json:"avatar_id"` // file of the Media service, nil if the user has no avatar
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// DeletedUser returns the user of an ID SSO does not know, the author of posts and comments of deleted users.
+func DeletedUser(id uuid.UUID) *User {
+	return &User{ID: id}
+}
+
+// ShownName returns the name shown for the user, the display name if the user has set one.
+func (u *User) ShownName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Name
 }
 
 // GetID returns the ID of the user.
@@ -22,3 +41,13 @@ func (u *User) GetID() uuid.UUID {
 func (u *User) SetID(id uuid.UUID) {
 	u.ID = id
 }
+
+// GetCreatedAt returns the creation time of the user.
+func (u *User) GetCreatedAt() time.Time {
+	return u.CreatedAt
+}
+
+// SetCreatedAt sets the creation time of the user.
+func (u *User) SetCreatedAt(createdAt time.Time) {
+	u.CreatedAt = createdAt
+}
@@ -0,0 +1,54 @@
+package broker
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/usecases"
+	"Posts/pkg/pubsub"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+var _ usecases.CommentBroker = &CommentLocalBroker{}
+
+// CommentLocalBroker is an in-process broker for comments.
+//
+// It only reaches the subscribers of the current instance.
+type CommentLocalBroker struct {
+	broker *pubsub.Broker[uuid.UUID, *domain.Comment]
+	logger *slog.Logger
+}
+
+// NewCommentLocalBroker creates a new CommentLocalBroker with the given per-subscriber buffer size.
+func NewCommentLocalBroker(buffer int, logger *slog.Logger) *CommentLocalBroker {
+	return &CommentLocalBroker{
+		broker: pubsub.NewBroker[uuid.UUID, *domain.Comment](buffer),
+		logger: logger,
+	}
+}
+
+// Publish delivers a comment to the subscribers of its post.
+func (b *CommentLocalBroker) Publish(ctx context.Context, comment *domain.Comment) {
+	const op = "CommentLocalBroker.Publish"
+
+	if dropped := b.broker.Publish(comment.PostID, comment); dropped > 0 {
+		b.logger.Warn(op, slog.Any("message", "dropped slow subscribers"), slog.Any("count", dropped))
+	}
+}
+
+// Subscribe returns a channel with the new comments of a post.
+func (b *CommentLocalBroker) Subscribe(ctx context.Context, postID uuid.UUID) (<-chan *domain.Comment, error) {
+	sub := b.broker.Subscribe(postID)
+
+	go func() {
+		<-ctx.Done()
+		sub.Unsubscribe()
+	}()
+
+	return sub.C(), nil
+}
+
+// HasSubscribers reports whether a post has subscribers.
+func (b *CommentLocalBroker) HasSubscribers(postID uuid.UUID) bool {
+	return b.broker.Subscribers(postID) > 0
+}
@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"Posts/internal/domain"
+	"Posts/pkg/logger/slogdiscard"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCommentLocalBroker_Publish(t *testing.T) {
+	broker := NewCommentLocalBroker(4, slogdiscard.NewDiscardLogger())
+
+	postID := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comments, err := broker.Subscribe(ctx, postID)
+	assert.NoError(t, err)
+	others, err := broker.Subscribe(ctx, uuid.New())
+	assert.NoError(t, err)
+
+	first := &domain.Comment{ID: uuid.New(), PostID: postID, CreatedAt: time.Now()}
+	second := &domain.Comment{ID: uuid.New(), PostID: postID, CreatedAt: first.CreatedAt}
+	broker.Publish(ctx, first)
+	broker.Publish(ctx, second)
+
+	// Comments with equal creation time are both delivered, in order
+	assert.Equal(t, first, <-comments)
+	assert.Equal(t, second, <-comments)
+	assert.Len(t, others, 0)
+}
+
+func TestCommentLocalBroker_Subscribe_ContextDone(t *testing.T) {
+	broker := NewCommentLocalBroker(4, slogdiscard.NewDiscardLogger())
+
+	postID := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	comments, err := broker.Subscribe(ctx, postID)
+	assert.NoError(t, err)
+	assert.True(t, broker.HasSubscribers(postID))
+
+	cancel()
+
+	_, ok := <-comments
+	assert.False(t, ok)
+	assert.False(t, broker.HasSubscribers(postID))
+}
@@ -0,0 +1,147 @@
+package broker
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/usecases"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+	"log/slog"
+	"time"
+)
+
+// CommentChannel is the Postgres notification channel for new comments.
+const CommentChannel = "comment_added"
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+var _ usecases.CommentBroker = &CommentPostgresBroker{}
+
+// CommentPostgresBroker is a broker for comments backed by Postgres LISTEN/NOTIFY.
+//
+// Publish notifies every instance listening on CommentChannel, each instance
+// loads the comment once and fans it out to its own subscribers.
+type CommentPostgresBroker struct {
+	local      *CommentLocalBroker
+	db         *gorm.DB
+	dsn        string
+	repository usecases.CommentRepository
+	logger     *slog.Logger
+}
+
+// notification is the payload of a comment notification.
+// Only identifiers are sent since the payload is limited to 8000 bytes.
+type notification struct {
+	ID     uuid.UUID `json:"id"`
+	PostID uuid.UUID `json:"post_id"`
+}
+
+// NewCommentPostgresBroker creates a new CommentPostgresBroker.
+// dsn is used for the dedicated listening connection.
+func NewCommentPostgresBroker(
+	db *gorm.DB,
+	dsn string,
+	repository usecases.CommentRepository,
+	buffer int,
+	logger *slog.Logger,
+) *CommentPostgresBroker {
+	return &CommentPostgresBroker{
+		local:      NewCommentLocalBroker(buffer, logger),
+		db:         db,
+		dsn:        dsn,
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Publish notifies the listening instances about a comment.
+func (b *CommentPostgresBroker) Publish(ctx context.Context, comment *domain.Comment) {
+	const op = "CommentPostgresBroker.Publish"
+
+	payload, err := json.Marshal(notification{ID: comment.ID, PostID: comment.PostID})
+	if err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return
+	}
+
+	if err := b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", CommentChannel, string(payload)).Error; err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+	}
+}
+
+// Subscribe returns a channel with the new comments of a post.
+func (b *CommentPostgresBroker) Subscribe(ctx context.Context, postID uuid.UUID) (<-chan *domain.Comment, error) {
+	return b.local.Subscribe(ctx, postID)
+}
+
+// Listen receives notifications until ctx is done, reconnecting when the connection is lost.
+func (b *CommentPostgresBroker) Listen(ctx context.Context) {
+	const op = "CommentPostgresBroker.Listen"
+
+	delay := minReconnectDelay
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.logger.Error(op, slog.Any("error", err.Error()), slog.Any("retry_in", delay.String()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// listen opens a connection and dispatches notifications until an error occurs.
+func (b *CommentPostgresBroker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+CommentChannel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		b.dispatch(ctx, n.Payload)
+	}
+}
+
+// dispatch loads the comment of a notification and delivers it to the local subscribers.
+func (b *CommentPostgresBroker) dispatch(ctx context.Context, payload string) {
+	const op = "CommentPostgresBroker.dispatch"
+
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return
+	}
+
+	if !b.local.HasSubscribers(n.PostID) {
+		return
+	}
+
+	comment, err := b.repository.GetByID(ctx, n.ID)
+	if err != nil {
+		b.logger.Error(op, slog.Any("error", err.Error()))
+		return
+	}
+
+	b.local.Publish(ctx, comment)
+}
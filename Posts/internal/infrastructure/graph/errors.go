@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/middleware"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"log/slog"
+	"runtime/debug"
+)
+
+// Error codes sent to clients in the extensions.code field of errors.
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeForbidden       = "FORBIDDEN"
+	CodeValidation      = "VALIDATION"
+	CodeConflict        = "CONFLICT"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeInternal        = "INTERNAL"
+)
+
+// internalErrorMessage replaces the message of internal errors when they are hidden.
+const internalErrorMessage = "internal server error"
+
+// errPanic is returned for resolvers that panicked.
+var errPanic = errors.New(internalErrorMessage)
+
+// errorCodes maps domain errors to error codes, errors not listed are internal.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{domain.ErrNotFound, CodeNotFound},
+	{domain.ErrPostNotFound, CodeNotFound},
+	{domain.ErrParentNotFound, CodeNotFound},
+	{domain.ErrAttachmentNotFound, CodeNotFound},
+	{domain.ErrNotAuthor, CodeForbidden},
+	{domain.ErrImpersonation, CodeForbidden},
+	{domain.ErrCommentsDisabled, CodeForbidden},
+	{domain.ErrAttachmentNotOwned, CodeForbidden},
+	{domain.ErrInvalidInput, CodeValidation},
+	{domain.ErrCommentIsTooLong, CodeValidation},
+	{domain.ErrInvalidPageSize, CodeValidation},
+	{domain.ErrInvalidCursor, CodeValidation},
+	{domain.ErrParentMismatch, CodeValidation},
+	{domain.ErrThreadTooDeep, CodeValidation},
+	{domain.ErrTooManyAttachments, CodeValidation},
+	{domain.ErrAttachmentNotPublic, CodeValidation},
+	{domain.ErrAttachmentsDisabled, CodeValidation},
+	{domain.ErrAlreadyExists, CodeConflict},
+	{domain.ErrCommentDeleted, CodeConflict},
+	{domain.ErrUnauthenticated, CodeUnauthenticated},
+}
+
+// ErrorCode returns the error code of an error.
+func ErrorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeInternal
+}
+
+// NewErrorPresenter returns an error presenter that adds the error code and the request ID to errors.
+// Internal errors are logged, and their messages are replaced if hideInternal is set.
+func NewErrorPresenter(logger *slog.Logger, hideInternal bool) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]interface{})
+		}
+
+		requestID := middleware.GetRequestID(ctx)
+		if requestID != "" {
+			gqlErr.Extensions["requestId"] = requestID
+		}
+
+		// Errors made by gqlgen itself, such as parsing errors, are sent as is
+		if gqlErr.Err == nil {
+			return gqlErr
+		}
+
+		code := ErrorCode(gqlErr.Err)
+		gqlErr.Extensions["code"] = code
+
+		if code == CodeInternal {
+			logger.Error("internal error",
+				slog.Any("error", gqlErr.Err.Error()),
+				slog.Any("path", gqlErr.Path.String()),
+				slog.Any("request_id", requestID),
+			)
+			if hideInternal {
+				gqlErr.Message = internalErrorMessage
+			}
+		}
+
+		return gqlErr
+	}
+}
+
+// NewRecoverFunc returns a recover function that logs panics of resolvers and turns them into internal errors.
+func NewRecoverFunc(logger *slog.Logger) graphql.RecoverFunc {
+	return func(ctx context.Context, err interface{}) error {
+		logger.Error("panic in resolver",
+			slog.Any("error", fmt.Sprint(err)),
+			slog.Any("request_id", middleware.GetRequestID(ctx)),
+			slog.Any("stack", string(debug.Stack())),
+		)
+		return errPanic
+	}
+}
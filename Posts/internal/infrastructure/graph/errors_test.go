@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/middleware"
+	"Posts/pkg/logger/slogdiscard"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{domain.ErrNotFound, CodeNotFound},
+		{domain.ErrNotAuthor, CodeForbidden},
+		{domain.ErrAttachmentNotOwned, CodeForbidden},
+		{domain.ErrCommentIsTooLong, CodeValidation},
+		{fmt.Errorf("%w: bad uuid", domain.ErrInvalidInput), CodeValidation},
+		{domain.ErrAlreadyExists, CodeConflict},
+		{domain.ErrUnauthenticated, CodeUnauthenticated},
+		{errors.New("connection refused"), CodeInternal},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, ErrorCode(tt.err), tt.err.Error())
+	}
+}
+
+// withRequestID returns a context with a request ID set by the RequestID middleware.
+func withRequestID(t *testing.T, requestID string) context.Context {
+	var ctx context.Context
+	handler := middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/query", nil)
+	r.Header.Set(middleware.RequestIDHeader, requestID)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, requestID, w.Header().Get(middleware.RequestIDHeader))
+	return ctx
+}
+
+func TestErrorPresenter(t *testing.T) {
+	ctx := withRequestID(t, "request-id")
+
+	presenter := NewErrorPresenter(slogdiscard.NewDiscardLogger(), true)
+
+	gqlErr := presenter(ctx, domain.ErrNotAuthor)
+	assert.Equal(t, domain.ErrNotAuthor.Error(), gqlErr.Message)
+	assert.Equal(t, CodeForbidden, gqlErr.Extensions["code"])
+	assert.Equal(t, "request-id", gqlErr.Extensions["requestId"])
+
+	gqlErr = presenter(ctx, errors.New("pq: password authentication failed"))
+	assert.Equal(t, internalErrorMessage, gqlErr.Message)
+	assert.Equal(t, CodeInternal, gqlErr.Extensions["code"])
+}
+
+func TestErrorPresenter_ShowInternal(t *testing.T) {
+	presenter := NewErrorPresenter(slogdiscard.NewDiscardLogger(), false)
+
+	gqlErr := presenter(context.Background(), errors.New("connection refused"))
+	assert.Equal(t, "connection refused", gqlErr.Message)
+	assert.Equal(t, CodeInternal, gqlErr.Extensions["code"])
+	assert.NotContains(t, gqlErr.Extensions, "requestId")
+}
+
+func TestRecoverFunc(t *testing.T) {
+	recoverFunc := NewRecoverFunc(slogdiscard.NewDiscardLogger())
+	presenter := NewErrorPresenter(slogdiscard.NewDiscardLogger(), true)
+
+	gqlErr := presenter(context.Background(), recoverFunc(context.Background(), "nil pointer dereference"))
+	assert.Equal(t, internalErrorMessage, gqlErr.Message)
+	assert.Equal(t, CodeInternal, gqlErr.Extensions["code"])
+}
@@ -1,17 +1,29 @@
 package middleware
 
 import (
-	"Posts/pkg/jwtservice"
 	"context"
+	"github.com/golang-jwt/jwt/v5"
 	"log/slog"
 	"net/http"
 	"strings"
 )
 
-const userIDKey key = "userID"
+const (
+	userIDKey key = "userID"
+	rolesKey  key = "roles"
+	tokenKey  key = "token"
+)
+
+// RoleAdmin is the role of users that may act on behalf of other users.
+const RoleAdmin = "admin"
+
+// TokenParser parses and verifies access tokens.
+type TokenParser interface {
+	Parse(tokenString string) (*jwt.Token, error)
+}
 
 // Auth is a middleware that checks if the user is authenticated.
-func Auth(jwtGen *jwtservice.Service, logger *slog.Logger) func(next http.Handler) http.Handler {
+func Auth(tokenParser TokenParser, logger *slog.Logger) func(next http.Handler) http.Handler {
 	const op = "Auth"
 
 	logger = logger.With(slog.Any("op", op))
@@ -37,7 +49,7 @@ func Auth(jwtGen *jwtservice.Service, logger *slog.Logger) func(next http.Handle
 			token = strings.TrimPrefix(token, "Bearer ")
 
 			// Get the user ID from the token
-			parsedToken, err := jwtGen.Parse(token)
+			parsedToken, err := tokenParser.Parse(token)
 			if err != nil {
 				logger.Error("Failed to parse token", slog.String("error", err.Error()))
 				http.Error(w, "failed to parse token", http.StatusUnauthorized)
@@ -51,9 +63,11 @@ func Auth(jwtGen *jwtservice.Service, logger *slog.Logger) func(next http.Handle
 				return
 			}
 
-			// Add the user ID to the request context
+			// Add the user ID, roles and token to the request context, the token is passed on to other services
 			ctx := r.Context()
 			ctx = context.WithValue(ctx, userIDKey, userID)
+			ctx = context.WithValue(ctx, rolesKey, rolesFromClaims(parsedToken.Claims))
+			ctx = context.WithValue(ctx, tokenKey, token)
 			r = r.WithContext(ctx)
 
 			// Call the next handler
@@ -63,6 +77,43 @@ func Auth(jwtGen *jwtservice.Service, logger *slog.Logger) func(next http.Handle
 }
 
 // GetUserID returns the user ID from the request context.
+// It is empty if the request is not authenticated.
 func GetUserID(ctx context.Context) string {
-	return ctx.Value(userIDKey).(string)
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
+// GetToken returns the access token of the request context.
+// It is empty if the request is not authenticated.
+func GetToken(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey).(string)
+	return token
+}
+
+// HasRole reports whether the user of the request context has a role.
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(rolesKey).([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// rolesFromClaims returns the roles in the "roles" claim.
+func rolesFromClaims(claims jwt.Claims) []string {
+	mapClaims, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+
+	rawRoles, _ := mapClaims["roles"].([]interface{})
+	roles := make([]string, 0, len(rawRoles))
+	for _, rawRole := range rawRoles {
+		if role, ok := rawRole.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
 }
@@ -8,18 +8,41 @@ import (
 	"github.com/google/uuid"
 )
 
+// A file of the Media service attached to a post.
+type Attachment struct {
+	FileID      uuid.UUID `json:"fileId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int       `json:"size"`
+	// The URL of the content of the file in the Media service.
+	URL string `json:"url"`
+}
+
 type Comment struct {
-	ID        uuid.UUID  `json:"id"`
-	PostID    uuid.UUID  `json:"postId"`
-	ParentID  *uuid.UUID `json:"parentId,omitempty"`
-	Content   string     `json:"content"`
-	AuthorID  uuid.UUID  `json:"authorId"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
-	Author    *User      `json:"author,omitempty"`
-	Post      *Post      `json:"post,omitempty"`
-	Parent    *Comment   `json:"parent,omitempty"`
-	Children  []*Comment `json:"children,omitempty"`
+	ID                 uuid.UUID          `json:"id"`
+	PostID             uuid.UUID          `json:"postId"`
+	ParentID           *uuid.UUID         `json:"parentId,omitempty"`
+	Content            string             `json:"content"`
+	AuthorID           uuid.UUID          `json:"authorId"`
+	Deleted            bool               `json:"deleted"`
+	CreatedAt          time.Time          `json:"createdAt"`
+	UpdatedAt          time.Time          `json:"updatedAt"`
+	Author             *User              `json:"author,omitempty"`
+	Post               *Post              `json:"post,omitempty"`
+	Parent             *Comment           `json:"parent,omitempty"`
+	Children           []*Comment         `json:"children,omitempty"`
+	ChildrenConnection *CommentConnection `json:"childrenConnection"`
+	EditHistory        []*Revision        `json:"editHistory"`
+}
+
+type CommentConnection struct {
+	Edges    []*CommentEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type CommentEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Comment `json:"node"`
 }
 
 type Mutation struct {
@@ -29,40 +52,92 @@ type NewComment struct {
 	PostID   uuid.UUID  `json:"postId"`
 	ParentID *uuid.UUID `json:"parentId,omitempty"`
 	Content  string     `json:"content"`
-	AuthorID uuid.UUID  `json:"authorId"`
+	// Defaults to the authenticated user, only admins may set another user.
+	AuthorID *uuid.UUID `json:"authorId,omitempty"`
 }
 
 type NewPost struct {
-	Title         string    `json:"title"`
-	Content       string    `json:"content"`
-	AuthorID      uuid.UUID `json:"authorId"`
-	AllowComments *bool     `json:"allowComments,omitempty"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// Defaults to the authenticated user, only admins may set another user.
+	AuthorID      *uuid.UUID `json:"authorId,omitempty"`
+	AllowComments *bool      `json:"allowComments,omitempty"`
+	// IDs of public files of the author in the Media service, posts are public so other files are rejected.
+	AttachmentIds []uuid.UUID `json:"attachmentIds,omitempty"`
 }
 
-type NewUser struct {
-	Name string `json:"name"`
+// Information about a page of a connection.
+// Lists are ordered by creation time, oldest first.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
 }
 
 type Post struct {
-	ID            uuid.UUID  `json:"id"`
-	Title         string     `json:"title"`
-	Content       string     `json:"content"`
-	AuthorID      uuid.UUID  `json:"authorId"`
-	AllowComments bool       `json:"allowComments"`
-	CreatedAt     time.Time  `json:"createdAt"`
-	UpdatedAt     time.Time  `json:"updatedAt"`
-	Comments      []*Comment `json:"comments"`
-	Author        *User      `json:"author"`
+	ID                 uuid.UUID          `json:"id"`
+	Title              string             `json:"title"`
+	Content            string             `json:"content"`
+	AuthorID           uuid.UUID          `json:"authorId"`
+	AllowComments      bool               `json:"allowComments"`
+	Attachments        []*Attachment      `json:"attachments"`
+	CreatedAt          time.Time          `json:"createdAt"`
+	UpdatedAt          time.Time          `json:"updatedAt"`
+	Comments           []*Comment         `json:"comments"`
+	CommentsConnection *CommentConnection `json:"commentsConnection"`
+	Author             *User              `json:"author"`
+	EditHistory        []*Revision        `json:"editHistory"`
+}
+
+type PostConnection struct {
+	Edges    []*PostEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type PostEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Post  `json:"node"`
 }
 
 type Query struct {
 }
 
+// A previous version of an edited post or comment.
+// createdAt is the time of the edit that replaced this version.
+type Revision struct {
+	ID        uuid.UUID `json:"id"`
+	Title     *string   `json:"title,omitempty"`
+	Content   string    `json:"content"`
+	EditorID  uuid.UUID `json:"editorId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type Subscription struct {
 }
 
+type UpdateComment struct {
+	Content string `json:"content"`
+}
+
+// Fields left out are unchanged, at least one must be set. A post that stays the same gets no revision.
+type UpdatePost struct {
+	Title   *string `json:"title,omitempty"`
+	Content *string `json:"content,omitempty"`
+}
+
+// A user of SSO, which owns users and their profiles.
 type User struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Posts []*Post   `json:"posts,omitempty"`
+	ID uuid.UUID `json:"id"`
+	// The username of the user.
+	Name string `json:"name"`
+	// The name shown for the user, the username if the user has not set one.
+	DisplayName string `json:"displayName"`
+	Bio         string `json:"bio"`
+	// ID of the avatar file in the Media service.
+	AvatarID *uuid.UUID `json:"avatarId,omitempty"`
+	// The URL of the content of the avatar in the Media service.
+	AvatarURL       *string         `json:"avatarUrl,omitempty"`
+	Posts           []*Post         `json:"posts,omitempty"`
+	PostsConnection *PostConnection `json:"postsConnection"`
 }
@@ -11,7 +11,6 @@ import (
 	"Posts/internal/utils/mappers"
 	"context"
 	"log/slog"
-	"time"
 
 	"github.com/google/uuid"
 )
@@ -66,11 +65,77 @@ func (r *commentResolver) Children(ctx context.Context, obj *model.Comment, limi
 	return modelComments, nil
 }
 
+// ChildrenConnection is the resolver for the childrenConnection field.
+func (r *commentResolver) ChildrenConnection(ctx context.Context, obj *model.Comment, first *int, after *string, last *int, before *string) (*model.CommentConnection, error) {
+	page, err := mappers.ConnectionArgsToDomainPageRequest(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cuc.GetChildrenPage(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelCommentConnection(result), nil
+}
+
+// EditHistory is the resolver for the editHistory field.
+func (r *commentResolver) EditHistory(ctx context.Context, obj *model.Comment) ([]*model.Revision, error) {
+	revisions, err := r.cuc.GetHistory(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	modelRevisions := make([]*model.Revision, 0, len(revisions))
+	for _, revision := range revisions {
+		modelRevisions = append(modelRevisions, mappers.DomainToModelRevision(revision))
+	}
+
+	return modelRevisions, nil
+}
+
 // CreateComment is the resolver for the createComment field.
 func (r *mutationResolver) CreateComment(ctx context.Context, input model.NewComment) (*model.Comment, error) {
+	authorID, err := r.authorID(ctx, input.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
 	comment := mappers.CreateDTOToDomainComment(&input)
+	comment.AuthorID = authorID
 
-	err := r.cuc.Create(ctx, comment)
+	err = r.cuc.Create(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelComment(comment), nil
+}
+
+// UpdateComment is the resolver for the updateComment field.
+func (r *mutationResolver) UpdateComment(ctx context.Context, id uuid.UUID, input model.UpdateComment) (*model.Comment, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := r.cuc.UpdateByAuthor(ctx, userID, id, input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelComment(comment), nil
+}
+
+// DeleteComment is the resolver for the deleteComment field.
+func (r *mutationResolver) DeleteComment(ctx context.Context, id uuid.UUID) (*model.Comment, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := r.cuc.DeleteByAuthor(ctx, userID, id)
 	if err != nil {
 		return nil, err
 	}
@@ -103,42 +168,44 @@ func (r *queryResolver) Comments(ctx context.Context, postID uuid.UUID, limit *i
 	return modelComments, nil
 }
 
+// CommentsConnection is the resolver for the commentsConnection field.
+func (r *queryResolver) CommentsConnection(ctx context.Context, postID uuid.UUID, first *int, after *string, last *int, before *string) (*model.CommentConnection, error) {
+	page, err := mappers.ConnectionArgsToDomainPageRequest(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cuc.GetByPostIDPage(ctx, postID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelCommentConnection(result), nil
+}
+
 // CommentAdded is the resolver for the commentAdded field.
 func (r *subscriptionResolver) CommentAdded(ctx context.Context, postID uuid.UUID, limit *int) (<-chan *model.Comment, error) {
 	const op = "commentResolver.CommentAdded"
 
+	comments, err := r.cuc.Subscribe(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
 	commentChan := make(chan *model.Comment)
-	ticker := time.NewTicker(1 * time.Second)
-	lastSeen := time.Now()
 	log := r.logger.With(slog.Any("operation", op))
 
 	go func() {
-		for {
+		defer close(commentChan)
+
+		for comment := range comments {
 			select {
+			case commentChan <- mappers.DomainToModelComment(comment):
 			case <-ctx.Done():
-				log.Debug("context done")
-				ticker.Stop()
-				close(commentChan)
 				return
-			case <-ticker.C:
-				comment, err := r.cuc.GetLastComment(ctx, postID, lastSeen, *limit)
-				if err != nil {
-					log.Error("failed to get last comment", slog.Any("error", err.Error()))
-					continue
-				}
-
-				log.Debug("got new comments", slog.Any("count", len(comment)))
-
-				for _, comment := range comment {
-					if comment.CreatedAt.After(lastSeen) {
-						lastSeen = comment.CreatedAt
-					}
-
-					commentChan <- mappers.DomainToModelComment(comment)
-				}
-				log.Debug("sent new comments", slog.Any("count", len(comment)))
 			}
 		}
+		log.Debug("subscription closed")
 	}()
 
 	return commentChan, nil
@@ -5,20 +5,31 @@ package resolvers
 // Code generated by github.com/99designs/gqlgen version v0.17.47
 
 import (
+	"Posts/internal/domain"
 	"Posts/internal/infrastructure/graph"
 	"Posts/internal/infrastructure/graph/middleware"
 	"Posts/internal/infrastructure/graph/model"
 	"Posts/internal/utils/mappers"
 	"context"
-	"errors"
 	"github.com/google/uuid"
 )
 
+// URL is the resolver for the url field.
+func (r *attachmentResolver) URL(ctx context.Context, obj *model.Attachment) (string, error) {
+	return r.puc.AttachmentURL(obj.FileID), nil
+}
+
 // CreatePost is the resolver for the createPost field.
 func (r *mutationResolver) CreatePost(ctx context.Context, input model.NewPost) (*model.Post, error) {
+	authorID, err := r.authorID(ctx, input.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
 	post := mappers.CreateDTOToDomainPost(&input)
+	post.AuthorID = authorID
 
-	err := r.puc.Create(ctx, post)
+	err = r.puc.Create(ctx, post)
 	if err != nil {
 		return nil, err
 	}
@@ -28,8 +39,10 @@ func (r *mutationResolver) CreatePost(ctx context.Context, input model.NewPost)
 
 // DisableComments is the resolver for the disableComments field.
 func (r *mutationResolver) DisableComments(ctx context.Context, postID uuid.UUID) (*model.Post, error) {
-	strUserID := middleware.GetUserID(ctx)
-	userID := uuid.MustParse(strUserID)
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	post, err := r.puc.GetByID(ctx, postID)
 	if err != nil {
@@ -37,7 +50,7 @@ func (r *mutationResolver) DisableComments(ctx context.Context, postID uuid.UUID
 	}
 
 	if post.AuthorID != userID {
-		return nil, errors.New("you are not the author of this post")
+		return nil, domain.ErrNotAuthor
 	}
 
 	post.DisableComments()
@@ -52,8 +65,10 @@ func (r *mutationResolver) DisableComments(ctx context.Context, postID uuid.UUID
 
 // EnableComments is the resolver for the enableComments field.
 func (r *mutationResolver) EnableComments(ctx context.Context, postID uuid.UUID) (*model.Post, error) {
-	strUserID := middleware.GetUserID(ctx)
-	userID := uuid.MustParse(strUserID)
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	post, err := r.puc.GetByID(ctx, postID)
 	if err != nil {
@@ -61,7 +76,7 @@ func (r *mutationResolver) EnableComments(ctx context.Context, postID uuid.UUID)
 	}
 
 	if post.AuthorID != userID {
-		return nil, errors.New("you are not the author of this post")
+		return nil, domain.ErrNotAuthor
 	}
 
 	post.EnableComments()
@@ -74,6 +89,35 @@ func (r *mutationResolver) EnableComments(ctx context.Context, postID uuid.UUID)
 	return mappers.DomainToModelPost(post), nil
 }
 
+// UpdatePost is the resolver for the updatePost field.
+func (r *mutationResolver) UpdatePost(ctx context.Context, id uuid.UUID, input model.UpdatePost) (*model.Post, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := r.puc.UpdateByAuthor(ctx, userID, id, input.Title, input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelPost(post), nil
+}
+
+// DeletePost is the resolver for the deletePost field.
+func (r *mutationResolver) DeletePost(ctx context.Context, id uuid.UUID) (bool, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.puc.DeleteByAuthor(ctx, userID, id); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // Comments is the resolver for the comments field.
 func (r *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int, offset *int) ([]*model.Comment, error) {
 	comments, err := r.cuc.GetByPostID(ctx, obj.ID, *limit, *offset)
@@ -89,6 +133,21 @@ func (r *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int
 	return modelComments, nil
 }
 
+// CommentsConnection is the resolver for the commentsConnection field.
+func (r *postResolver) CommentsConnection(ctx context.Context, obj *model.Post, first *int, after *string, last *int, before *string) (*model.CommentConnection, error) {
+	page, err := mappers.ConnectionArgsToDomainPageRequest(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cuc.GetByPostIDPage(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelCommentConnection(result), nil
+}
+
 // Author is the resolver for the author field.
 func (r *postResolver) Author(ctx context.Context, obj *model.Post) (*model.User, error) {
 	user, err := middleware.GetUserLoader(ctx).Load(obj.AuthorID)
@@ -99,6 +158,21 @@ func (r *postResolver) Author(ctx context.Context, obj *model.Post) (*model.User
 	return mappers.DomainToModelUser(user), nil
 }
 
+// EditHistory is the resolver for the editHistory field.
+func (r *postResolver) EditHistory(ctx context.Context, obj *model.Post) ([]*model.Revision, error) {
+	revisions, err := r.puc.GetHistory(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	modelRevisions := make([]*model.Revision, 0, len(revisions))
+	for _, revision := range revisions {
+		modelRevisions = append(modelRevisions, mappers.DomainToModelRevision(revision))
+	}
+
+	return modelRevisions, nil
+}
+
 // Post is the resolver for the post field.
 func (r *queryResolver) Post(ctx context.Context, id uuid.UUID) (*model.Post, error) {
 	post, err := r.puc.GetByID(ctx, id)
@@ -124,7 +198,26 @@ func (r *queryResolver) Posts(ctx context.Context, limit *int, offset *int) ([]*
 	return modelPosts, nil
 }
 
+// PostsConnection is the resolver for the postsConnection field.
+func (r *queryResolver) PostsConnection(ctx context.Context, first *int, after *string, last *int, before *string) (*model.PostConnection, error) {
+	page, err := mappers.ConnectionArgsToDomainPageRequest(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.puc.GetPage(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelPostConnection(result), nil
+}
+
+// Attachment returns graph.AttachmentResolver implementation.
+func (r *Resolver) Attachment() graph.AttachmentResolver { return &attachmentResolver{r} }
+
 // Post returns graph.PostResolver implementation.
 func (r *Resolver) Post() graph.PostResolver { return &postResolver{r} }
 
+type attachmentResolver struct{ *Resolver }
 type postResolver struct{ *Resolver }
@@ -1,7 +1,11 @@
 package resolvers
 
 import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/middleware"
 	usecaseInterfaces "Posts/internal/interfaces/usecases"
+	"context"
+	"github.com/google/uuid"
 	"log/slog"
 )
 
@@ -33,3 +37,34 @@ func NewResolver(
 		logger: logger,
 	}
 }
+
+// currentUserID returns the ID of the authenticated user.
+func currentUserID(ctx context.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(middleware.GetUserID(ctx))
+	if err != nil {
+		return uuid.Nil, domain.ErrUnauthenticated
+	}
+	return userID, nil
+}
+
+// authorID returns the ID of the author of a new post or comment.
+// It is the authenticated user, unless an admin requests to act on behalf of another existing user.
+func (r *Resolver) authorID(ctx context.Context, requested *uuid.UUID) (uuid.UUID, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if requested == nil || *requested == userID {
+		return userID, nil
+	}
+
+	if !middleware.HasRole(ctx, middleware.RoleAdmin) {
+		return uuid.Nil, domain.ErrImpersonation
+	}
+	if _, err := r.uuc.GetByID(ctx, *requested); err != nil {
+		return uuid.Nil, err
+	}
+	r.logger.Info("acting on behalf of another user", slog.Any("admin", userID), slog.Any("author", *requested))
+
+	return *requested, nil
+}
@@ -0,0 +1,90 @@
+package resolvers
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/middleware"
+	"Posts/internal/infrastructure/graph/model"
+	"Posts/internal/interfaces/usecases/mocks"
+	"Posts/pkg/logger/slogdiscard"
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// claimsParser is a TokenParser that accepts any token with fixed claims.
+type claimsParser jwt.MapClaims
+
+func (p claimsParser) Parse(tokenString string) (*jwt.Token, error) {
+	return &jwt.Token{Claims: jwt.MapClaims(p), Valid: true}, nil
+}
+
+// withUser returns a context authenticated by the Auth middleware as the user with the roles.
+func withUser(t *testing.T, userID uuid.UUID, roles ...interface{}) context.Context {
+	claims := claimsParser{"sub": userID.String()}
+	if len(roles) > 0 {
+		claims["roles"] = roles
+	}
+
+	var ctx context.Context
+	handler := middleware.Auth(claims, slogdiscard.NewDiscardLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if ctx == nil {
+		t.Fatal("request was not authenticated")
+	}
+	return ctx
+}
+
+func TestMutationResolver_CreatePost_Author(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	unknownID := uuid.New()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		authorID *uuid.UUID
+		want     uuid.UUID
+		err      error
+	}{
+		{name: "default author from the token", ctx: withUser(t, userID), want: userID},
+		{name: "own ID", ctx: withUser(t, userID), authorID: &userID, want: userID},
+		{name: "non-admin on behalf of another user", ctx: withUser(t, userID), authorID: &otherID, err: domain.ErrImpersonation},
+		{name: "admin on behalf of another user", ctx: withUser(t, userID, middleware.RoleAdmin), authorID: &otherID, want: otherID},
+		{name: "admin on behalf of an unknown user", ctx: withUser(t, userID, middleware.RoleAdmin), authorID: &unknownID, err: domain.ErrNotFound},
+		{name: "unauthenticated", ctx: context.Background(), err: domain.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			puc := &mocks.PostUseCase{}
+			uuc := &mocks.UserUseCase{}
+			puc.On("Create", mock.Anything, mock.Anything).Return(nil)
+			uuc.On("GetByID", mock.Anything, otherID).Return(&domain.User{ID: otherID}, nil)
+			uuc.On("GetByID", mock.Anything, unknownID).Return(nil, domain.ErrNotFound)
+
+			resolver := &mutationResolver{NewResolver(puc, nil, uuc, slogdiscard.NewDiscardLogger())}
+			post, err := resolver.CreatePost(tt.ctx, model.NewPost{Title: "title", Content: "content", AuthorID: tt.authorID})
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				puc.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, post.AuthorID)
+			puc.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(post *domain.Post) bool {
+				return post.AuthorID == tt.want
+			}))
+		})
+	}
+}
@@ -13,18 +13,6 @@ import (
 	"github.com/google/uuid"
 )
 
-// CreateUser is the resolver for the createUser field.
-func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
-	usr := mappers.CreateDTOToDomainUser(&input)
-
-	err := r.uuc.Create(ctx, usr)
-	if err != nil {
-		return nil, err
-	}
-
-	return mappers.DomainToModelUser(usr), nil
-}
-
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, id uuid.UUID) (*model.User, error) {
 	usr, err := r.uuc.GetByID(ctx, id)
@@ -35,19 +23,9 @@ func (r *queryResolver) User(ctx context.Context, id uuid.UUID) (*model.User, er
 	return mappers.DomainToModelUser(usr), nil
 }
 
-// Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, limit *int, offset *int) ([]*model.User, error) {
-	users, err := r.uuc.GetAll(ctx, *limit, *offset)
-	if err != nil {
-		return nil, err
-	}
-
-	var modelUsers []*model.User
-	for _, user := range users {
-		modelUsers = append(modelUsers, mappers.DomainToModelUser(user))
-	}
-
-	return modelUsers, nil
+// AvatarURL is the resolver for the avatarUrl field.
+func (r *userResolver) AvatarURL(ctx context.Context, obj *model.User) (*string, error) {
+	return r.uuc.AvatarURL(mappers.ModelToDomainUser(obj)), nil
 }
 
 // Posts is the resolver for the posts field.
@@ -65,6 +43,21 @@ func (r *userResolver) Posts(ctx context.Context, obj *model.User, limit *int, o
 	return modelPosts, nil
 }
 
+// PostsConnection is the resolver for the postsConnection field.
+func (r *userResolver) PostsConnection(ctx context.Context, obj *model.User, first *int, after *string, last *int, before *string) (*model.PostConnection, error) {
+	page, err := mappers.ConnectionArgsToDomainPageRequest(first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.puc.GetByAuthorIDPage(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return mappers.DomainToModelPostConnection(result), nil
+}
+
 // User returns graph.UserResolver implementation.
 func (r *Resolver) User() graph.UserResolver { return &userResolver{r} }
 
@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"Posts/internal/domain"
+	"fmt"
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/google/uuid"
 	"time"
@@ -13,9 +15,13 @@ func MarshalUUID(v uuid.UUID) graphql.Marshaler {
 
 // UnmarshalUUID unmarshals a UUID from a string.
 func UnmarshalUUID(v interface{}) (uuid.UUID, error) {
-	id, err := uuid.Parse(v.(string))
+	s, ok := v.(string)
+	if !ok {
+		return uuid.UUID{}, fmt.Errorf("%w: UUID must be a string", domain.ErrInvalidInput)
+	}
+	id, err := uuid.Parse(s)
 	if err != nil {
-		return uuid.UUID{}, err
+		return uuid.UUID{}, fmt.Errorf("%w: %s", domain.ErrInvalidInput, err)
 	}
 	return id, nil
 }
@@ -27,9 +33,13 @@ func MarshalDateTime(v time.Time) graphql.Marshaler {
 
 // UnmarshalDateTime unmarshals a time.Time from a string.
 func UnmarshalDateTime(v interface{}) (time.Time, error) {
-	t, err := time.Parse(time.RFC3339, v.(string))
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: DateTime must be a string", domain.ErrInvalidInput)
+	}
+	t, err := time.Parse(time.RFC3339, s)
 	if err != nil {
-		return time.Time{}, err
+		return time.Time{}, fmt.Errorf("%w: %s", domain.ErrInvalidInput, err)
 	}
 	return t, nil
 }
@@ -3,7 +3,6 @@ package graph
 import (
 	"Posts/internal/infrastructure/graph/middleware"
 	"Posts/internal/interfaces/usecases"
-	"Posts/pkg/jwtservice"
 	"context"
 	"errors"
 	"github.com/99designs/gqlgen/graphql"
@@ -23,11 +22,12 @@ const defaultPort = "8080"
 // Server is a GraphQL server.
 type Server struct {
 	port             string
-	jwtGen           *jwtservice.Service
+	tokenParser      middleware.TokenParser
 	logger           *slog.Logger
 	schema           graphql.ExecutableSchema
 	srv              http.Server
 	enablePlayground bool
+	hideErrors       bool
 
 	postUseCase    usecases.PostUseCase
 	commentUseCase usecases.CommentUseCase
@@ -37,20 +37,22 @@ type Server struct {
 // NewServer creates a new server.
 func NewServer(
 	port string,
-	jwtGen *jwtservice.Service,
+	tokenParser middleware.TokenParser,
 	logger *slog.Logger,
 	schema graphql.ExecutableSchema,
 	enablePlayground bool,
+	hideErrors bool,
 	postUseCase usecases.PostUseCase,
 	commentUseCase usecases.CommentUseCase,
 	userUseCase usecases.UserUseCase,
 ) *Server {
 	return &Server{
 		port:             port,
-		jwtGen:           jwtGen,
+		tokenParser:      tokenParser,
 		logger:           logger,
 		schema:           schema,
 		enablePlayground: enablePlayground,
+		hideErrors:       hideErrors,
 		postUseCase:      postUseCase,
 		commentUseCase:   commentUseCase,
 		userUseCase:      userUseCase,
@@ -71,10 +73,13 @@ func (s *Server) Run() error {
 
 	graphQlHandler := handler.NewDefaultServer(s.schema)
 	graphQlHandler.Use(extension.FixedComplexityLimit(1000))
+	graphQlHandler.SetErrorPresenter(NewErrorPresenter(s.logger, s.hideErrors))
+	graphQlHandler.SetRecoverFunc(NewRecoverFunc(s.logger))
 
 	queryRouter := router.PathPrefix("/query").Subrouter()
+	queryRouter.Use(middleware.RequestID())
 	queryRouter.Use(middleware.DataLoader(s.postUseCase, s.commentUseCase, s.userUseCase, s.logger))
-	queryRouter.Use(middleware.Auth(s.jwtGen, s.logger))
+	queryRouter.Use(middleware.Auth(s.tokenParser, s.logger))
 	queryRouter.Handle("", graphQlHandler)
 
 	s.logger.Info("starting server", slog.Any("port", s.port))
@@ -6,7 +6,9 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"log/slog"
+	"sort"
 	"sync"
+	"time"
 )
 
 var _ usecases.AbstractRepositoryInterface[domain.Model] = &AbstractInMemoryRepository[domain.Model]{}
@@ -34,6 +36,10 @@ func (r *AbstractInMemoryRepository[T]) Create(ctx context.Context, entity T) er
 	if _, ok := r.entities[entity.GetID()]; ok {
 		return domain.ErrAlreadyExists
 	}
+	// Like gorm, fill in the creation time so lists have a stable order
+	if entity.GetCreatedAt().IsZero() {
+		entity.SetCreatedAt(time.Now())
+	}
 
 	r.entities[entity.GetID()] = entity
 	return nil
@@ -92,16 +98,62 @@ func (r *AbstractInMemoryRepository[T]) GetAll(ctx context.Context, limit int, o
 	r.m.RLock()
 	defer r.m.RUnlock()
 
-	keys := make([]uuid.UUID, 0)
-	for key := range r.entities {
-		keys = append(keys, key)
+	return window(r.sorted(nil), limit, offset), nil
+}
+
+// GetPage returns a page of entities.
+func (r *AbstractInMemoryRepository[T]) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[T], error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return r.page(nil, page), nil
+}
+
+// sorted returns the entities matching filter ordered by creation time and ID.
+// A nil filter matches every entity. The caller must hold the lock.
+func (r *AbstractInMemoryRepository[T]) sorted(filter func(T) bool) []T {
+	entities := make([]T, 0, len(r.entities))
+	for _, entity := range r.entities {
+		if filter == nil || filter(entity) {
+			entities = append(entities, entity)
+		}
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return domain.CursorOf(entities[i]).Less(domain.CursorOf(entities[j]))
+	})
+	return entities
+}
+
+// page returns a page of the entities matching filter. The caller must hold the lock.
+func (r *AbstractInMemoryRepository[T]) page(filter func(T) bool, page domain.PageRequest) domain.Page[T] {
+	entities := r.sorted(filter)
+
+	items := make([]T, 0, page.Size()+1)
+	if page.Backward() {
+		for i := len(entities) - 1; i >= 0 && len(items) <= page.Size(); i-- {
+			if page.Before == nil || domain.CursorOf(entities[i]).Less(*page.Before) {
+				items = append(items, entities[i])
+			}
+		}
+	} else {
+		for i := 0; i < len(entities) && len(items) <= page.Size(); i++ {
+			if page.After == nil || page.After.Less(domain.CursorOf(entities[i])) {
+				items = append(items, entities[i])
+			}
+		}
 	}
 
-	i := offset
-	entities := make([]T, 0)
-	for i < len(keys) && len(entities) < limit {
-		entities = append(entities, r.entities[keys[i]])
-		i++
+	return domain.NewPage(items, page)
+}
+
+// window returns at most limit entities starting at offset.
+func window[T any](entities []T, limit int, offset int) []T {
+	if offset >= len(entities) {
+		return make([]T, 0)
 	}
-	return entities, nil
+	entities = entities[offset:]
+	if limit < len(entities) {
+		entities = entities[:limit]
+	}
+	return entities
 }
@@ -28,21 +28,15 @@ func (r *CommentInMemoryRepository) GetChildren(ctx context.Context, commentID u
 	r.m.RLock()
 	defer r.m.RUnlock()
 
-	var keys []uuid.UUID
-	for key := range r.entities {
-		keys = append(keys, key)
-	}
+	return window(r.sorted(isChildOf(commentID)), limit, offset), nil
+}
 
-	var comments []*domain.Comment
-	i := offset
-	for i < len(keys) && len(comments) < limit {
-		if r.entities[keys[i]].ParentID != nil && *r.entities[keys[i]].ParentID == commentID {
-			comments = append(comments, r.entities[keys[i]])
-		}
-		i++
-	}
+// GetChildrenPage returns a page of children of a comment.
+func (r *CommentInMemoryRepository) GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
 
-	return comments, nil
+	return r.page(isChildOf(commentID), page), nil
 }
 
 // GetByPostID returns all comments for a post.
@@ -50,20 +44,15 @@ func (r *CommentInMemoryRepository) GetByPostID(ctx context.Context, postID uuid
 	r.m.RLock()
 	defer r.m.RUnlock()
 
-	var keys []uuid.UUID
-	for key := range r.entities {
-		keys = append(keys, key)
-	}
+	return window(r.sorted(isRootOf(postID)), limit, offset), nil
+}
 
-	var comments []*domain.Comment
-	i := offset
-	for i < len(keys) && len(comments) < limit {
-		if r.entities[keys[i]].PostID == postID && r.entities[keys[i]].ParentID == nil {
-			comments = append(comments, r.entities[keys[i]])
-		}
-		i++
-	}
-	return comments, nil
+// GetByPostIDPage returns a page of comments for a post.
+func (r *CommentInMemoryRepository) GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return r.page(isRootOf(postID), page), nil
 }
 
 // GetLastComment returns the last comments of a post.
@@ -71,18 +60,22 @@ func (r *CommentInMemoryRepository) GetLastComment(ctx context.Context, postID u
 	r.m.RLock()
 	defer r.m.RUnlock()
 
-	var keys []uuid.UUID
-	for key := range r.entities {
-		keys = append(keys, key)
+	comments := r.sorted(func(comment *domain.Comment) bool {
+		return comment.PostID == postID && comment.CreatedAt.After(lastSeen)
+	})
+	return window(comments, limit, 0), nil
+}
+
+// isChildOf matches the children of a comment.
+func isChildOf(commentID uuid.UUID) func(*domain.Comment) bool {
+	return func(comment *domain.Comment) bool {
+		return comment.ParentID != nil && *comment.ParentID == commentID
 	}
+}
 
-	var comments []*domain.Comment
-	i := len(keys) - 1
-	for i >= 0 && len(comments) < limit {
-		if r.entities[keys[i]].PostID == postID && r.entities[keys[i]].CreatedAt.After(lastSeen) {
-			comments = append(comments, r.entities[keys[i]])
-		}
-		i--
+// isRootOf matches the top level comments of a post.
+func isRootOf(postID uuid.UUID) func(*domain.Comment) bool {
+	return func(comment *domain.Comment) bool {
+		return comment.PostID == postID && comment.ParentID == nil
 	}
-	return comments, nil
 }
@@ -27,20 +27,20 @@ func (r *PostInMemoryRepository) GetByAuthorID(ctx context.Context, userID uuid.
 	r.m.RLock()
 	defer r.m.RUnlock()
 
-	var posts []*domain.Post
+	return window(r.sorted(isByAuthor(userID)), limit, offset), nil
+}
 
-	var keys []uuid.UUID
-	for key := range r.entities {
-		keys = append(keys, key)
-	}
+// GetByAuthorIDPage returns a page of posts by a user.
+func (r *PostInMemoryRepository) GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
 
-	i := offset
-	for i < len(keys) && len(posts) < limit {
-		if r.entities[keys[i]].AuthorID == userID {
-			posts = append(posts, r.entities[keys[i]])
-		}
-		i++
-	}
+	return r.page(isByAuthor(userID), page), nil
+}
 
-	return posts, nil
+// isByAuthor matches the posts of a user.
+func isByAuthor(userID uuid.UUID) func(*domain.Post) bool {
+	return func(post *domain.Post) bool {
+		return post.AuthorID == userID
+	}
 }
@@ -197,3 +197,39 @@ func TestPostInMemoryRepository_GetByAuthorID(t *testing.T) {
 	assert.Equal(t, 1, len(posts))
 	assert.Equal(t, postID, posts[0].ID)
 }
+
+func TestPostInMemoryRepository_GetByAuthorIDPage(t *testing.T) {
+	rep := setupPostInMemoryRepository(t)
+
+	authorID := uuid.New()
+	var ids []uuid.UUID
+	for i := 0; i < 5; i++ {
+		post := &domain.Post{ID: uuid.New(), AuthorID: authorID, Title: "Test post"}
+		ids = append(ids, post.ID)
+		assert.NoError(t, rep.Create(context.Background(), post))
+	}
+	assert.NoError(t, rep.Create(context.Background(), &domain.Post{ID: uuid.New(), AuthorID: uuid.New()}))
+
+	first, err := rep.GetByAuthorIDPage(context.Background(), authorID, domain.PageRequest{First: 3})
+	assert.NoError(t, err)
+	assert.True(t, first.HasNextPage)
+	assert.Len(t, first.Items, 3)
+
+	cursor := domain.CursorOf(first.Items[2])
+	second, err := rep.GetByAuthorIDPage(context.Background(), authorID, domain.PageRequest{First: 3, After: &cursor})
+	assert.NoError(t, err)
+	assert.False(t, second.HasNextPage)
+	assert.Len(t, second.Items, 2)
+
+	var got []uuid.UUID
+	for _, post := range append(first.Items, second.Items...) {
+		got = append(got, post.ID)
+	}
+	assert.Equal(t, ids, got)
+
+	last, err := rep.GetByAuthorIDPage(context.Background(), authorID, domain.PageRequest{Last: 2})
+	assert.NoError(t, err)
+	assert.True(t, last.HasPreviousPage)
+	assert.Equal(t, ids[3], last.Items[0].ID)
+	assert.Equal(t, ids[4], last.Items[1].ID)
+}
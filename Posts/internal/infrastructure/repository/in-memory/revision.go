@@ -0,0 +1,46 @@
+package inmemory
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+var _ usecases.RevisionRepository = &RevisionInMemoryRepository{}
+
+// RevisionInMemoryRepository is a repository for revisions.
+type RevisionInMemoryRepository struct {
+	AbstractInMemoryRepository[*domain.Revision]
+}
+
+// NewRevisionInMemoryRepository creates a new RevisionInMemoryRepository.
+func NewRevisionInMemoryRepository(logger *slog.Logger) *RevisionInMemoryRepository {
+	return &RevisionInMemoryRepository{
+		AbstractInMemoryRepository: NewAbstractInMemoryRepository[*domain.Revision](logger),
+	}
+}
+
+// GetBySubjectID returns all revisions of a post or comment.
+func (r *RevisionInMemoryRepository) GetBySubjectID(ctx context.Context, subjectID uuid.UUID) ([]*domain.Revision, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return r.sorted(func(revision *domain.Revision) bool {
+		return revision.SubjectID == subjectID
+	}), nil
+}
+
+// DeleteByPostID deletes the revisions of a post and of its comments.
+func (r *RevisionInMemoryRepository) DeleteByPostID(ctx context.Context, postID uuid.UUID) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for id, revision := range r.entities {
+		if revision.PostID == postID {
+			delete(r.entities, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package inmemory
+
+import (
+	"Posts/internal/domain"
+	"Posts/pkg/logger/slogdiscard"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRevisionInMemoryRepository_DeleteByPostID(t *testing.T) {
+	rep := NewRevisionInMemoryRepository(slogdiscard.NewDiscardLogger())
+	ctx := context.Background()
+
+	postID := uuid.New()
+	commentID := uuid.New()
+	other := &domain.Revision{ID: uuid.New(), SubjectID: uuid.New(), PostID: uuid.New()}
+	assert.NoError(t, rep.Create(ctx, &domain.Revision{ID: uuid.New(), SubjectID: postID, PostID: postID}))
+	assert.NoError(t, rep.Create(ctx, &domain.Revision{ID: uuid.New(), SubjectID: commentID, PostID: postID}))
+	assert.NoError(t, rep.Create(ctx, other))
+
+	assert.NoError(t, rep.DeleteByPostID(ctx, postID))
+
+	revisions, err := rep.GetBySubjectID(ctx, postID)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+	revisions, err = rep.GetBySubjectID(ctx, commentID)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+	revisions, err = rep.GetBySubjectID(ctx, other.SubjectID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 1)
+}
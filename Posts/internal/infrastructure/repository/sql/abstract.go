@@ -10,6 +10,9 @@ import (
 	"log/slog"
 )
 
+// order is the order lists are returned in, it matches the keyset of domain.Cursor.
+const order = "created_at, id"
+
 // Entity is an interface for entities.
 type Entity interface{}
 
@@ -117,7 +120,7 @@ func (r *AbstractSQLRepository[TModel, TEntity]) GetByIds(ctx context.Context, i
 func (r *AbstractSQLRepository[TModel, TEntity]) GetAll(ctx context.Context, limit int, offset int) ([]TModel, error) {
 	const op = "AbstractSQLRepository.GetAll"
 	var entities []*TEntity
-	if err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order(order).Offset(offset).Limit(limit).Find(&entities).Error; err != nil {
 		r.logger.Error(op, slog.Any("error", err.Error()))
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrNotFound
@@ -130,3 +133,35 @@ func (r *AbstractSQLRepository[TModel, TEntity]) GetAll(ctx context.Context, lim
 	}
 	return models, nil
 }
+
+// GetPage returns a page of entities.
+func (r *AbstractSQLRepository[TModel, TEntity]) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[TModel], error) {
+	return r.findPage(ctx, r.db, page)
+}
+
+// findPage returns a page of the entities matching query using keyset pagination on (created_at, id).
+func (r *AbstractSQLRepository[TModel, TEntity]) findPage(ctx context.Context, query *gorm.DB, page domain.PageRequest) (domain.Page[TModel], error) {
+	const op = "AbstractSQLRepository.findPage"
+	if page.Backward() {
+		if page.Before != nil {
+			query = query.Where("(created_at, id) < (?, ?)", page.Before.CreatedAt, page.Before.ID)
+		}
+		query = query.Order("created_at DESC, id DESC")
+	} else {
+		if page.After != nil {
+			query = query.Where("(created_at, id) > (?, ?)", page.After.CreatedAt, page.After.ID)
+		}
+		query = query.Order(order)
+	}
+
+	var entities []*TEntity
+	if err := query.WithContext(ctx).Limit(page.Size() + 1).Find(&entities).Error; err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return domain.Page[TModel]{}, err
+	}
+	models := make([]TModel, 0, len(entities))
+	for _, entity := range entities {
+		models = append(models, r.entityToModel(entity))
+	}
+	return domain.NewPage(models, page), nil
+}
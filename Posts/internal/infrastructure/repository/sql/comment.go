@@ -33,7 +33,7 @@ func (r *CommentSQLRepository) GetByPostID(ctx context.Context, postID uuid.UUID
 	const op = "CommentSQLRepository.GetByPostID"
 	var comments []*domain.Comment
 	var commentEntities []*entities.Comment
-	if err := r.db.WithContext(ctx).Where("post_id = ? AND parent_id IS NULL", postID).Limit(limit).Offset(offset).Find(&commentEntities).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("post_id = ? AND parent_id IS NULL", postID).Order(order).Limit(limit).Offset(offset).Find(&commentEntities).Error; err != nil {
 		r.logger.Error(op, slog.Any("error", err.Error()))
 		return nil, err
 	}
@@ -43,12 +43,17 @@ func (r *CommentSQLRepository) GetByPostID(ctx context.Context, postID uuid.UUID
 	return comments, nil
 }
 
+// GetByPostIDPage returns a page of comments for a post.
+func (r *CommentSQLRepository) GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	return r.findPage(ctx, r.db.Where("post_id = ? AND parent_id IS NULL", postID), page)
+}
+
 // GetChildren returns all children of a comment.
 func (r *CommentSQLRepository) GetChildren(ctx context.Context, commentID uuid.UUID, limit int, offset int) ([]*domain.Comment, error) {
 	const op = "CommentSQLRepository.GetChildren"
 	var comments []*domain.Comment
 	var commentEntities []*entities.Comment
-	if err := r.db.WithContext(ctx).Where("parent_id = ?", commentID).Limit(limit).Offset(offset).Find(&commentEntities).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("parent_id = ?", commentID).Order(order).Limit(limit).Offset(offset).Find(&commentEntities).Error; err != nil {
 		r.logger.Error(op, slog.Any("error", err.Error()))
 		return nil, err
 	}
@@ -58,12 +63,17 @@ func (r *CommentSQLRepository) GetChildren(ctx context.Context, commentID uuid.U
 	return comments, nil
 }
 
+// GetChildrenPage returns a page of children of a comment.
+func (r *CommentSQLRepository) GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	return r.findPage(ctx, r.db.Where("parent_id = ?", commentID), page)
+}
+
 // GetLastComment returns the last comments of a post.
 func (r *CommentSQLRepository) GetLastComment(ctx context.Context, postID uuid.UUID, lastSeen time.Time, limit int) ([]*domain.Comment, error) {
 	const op = "CommentSQLRepository.GetLastComment"
 	var comments []*domain.Comment
 	var commentEntities []*entities.Comment
-	if err := r.db.WithContext(ctx).Where("post_id = ? AND created_at > ?", postID, lastSeen).Limit(limit).Find(&commentEntities).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("post_id = ? AND created_at > ?", postID, lastSeen).Order(order).Limit(limit).Find(&commentEntities).Error; err != nil {
 		r.logger.Error(op, slog.Any("error", err.Error()))
 		return nil, err
 	}
@@ -124,3 +124,81 @@ func TestCommentSQLRepository_GetLastComment(t *testing.T) {
 	assert.Equal(t, 1, len(comments))
 	assert.Equal(t, commentID, comments[0].ID)
 }
+
+func TestCommentSQLRepository_GetByPostIDPage(t *testing.T) {
+	_, commentRepo := setupCommentSQLRepository(t)
+
+	postID := uuid.New()
+	createdAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	// Comments sharing a creation time are ordered by ID
+	var ids []uuid.UUID
+	for i := 0; i < 5; i++ {
+		comment := &domain.Comment{
+			ID:        uuid.New(),
+			PostID:    postID,
+			Content:   "Test comment",
+			CreatedAt: createdAt.Add(time.Duration(i/2) * time.Second),
+		}
+		ids = append(ids, comment.ID)
+		assert.NoError(t, commentRepo.Create(context.Background(), comment))
+	}
+	for i := 0; i < len(ids); i += 2 {
+		if i+1 < len(ids) && ids[i+1].String() < ids[i].String() {
+			ids[i], ids[i+1] = ids[i+1], ids[i]
+		}
+	}
+
+	var forward []uuid.UUID
+	page := domain.PageRequest{First: 2}
+	for {
+		result, err := commentRepo.GetByPostIDPage(context.Background(), postID, page)
+		assert.NoError(t, err)
+		for _, comment := range result.Items {
+			forward = append(forward, comment.ID)
+		}
+		if !result.HasNextPage {
+			break
+		}
+		cursor := domain.CursorOf(result.Items[len(result.Items)-1])
+		page.After = &cursor
+	}
+	assert.Equal(t, ids, forward)
+
+	var backward []uuid.UUID
+	page = domain.PageRequest{Last: 2}
+	for {
+		result, err := commentRepo.GetByPostIDPage(context.Background(), postID, page)
+		assert.NoError(t, err)
+		backward = append(idsOf(result.Items), backward...)
+		if !result.HasPreviousPage {
+			break
+		}
+		cursor := domain.CursorOf(result.Items[0])
+		page.Before = &cursor
+	}
+	assert.Equal(t, ids, backward)
+}
+
+func idsOf(comments []*domain.Comment) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	return ids
+}
+
+func TestCommentSQLRepository_Update_Tombstone(t *testing.T) {
+	_, commentRepo := setupCommentSQLRepository(t)
+
+	comment := &domain.Comment{ID: uuid.New(), PostID: uuid.New(), Content: "Test comment"}
+	assert.NoError(t, commentRepo.Create(context.Background(), comment))
+
+	comment.Delete(time.Now())
+	assert.NoError(t, commentRepo.Update(context.Background(), comment))
+
+	stored, err := commentRepo.GetByID(context.Background(), comment.ID)
+	assert.NoError(t, err)
+	assert.True(t, stored.IsDeleted())
+	assert.Equal(t, domain.DeletedCommentContent, stored.Content)
+}
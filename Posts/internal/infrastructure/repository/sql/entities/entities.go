@@ -14,21 +14,36 @@ type Comment struct {
 	Content   string     `json:"content"`
 	CreatedAt time.Time  `json:"createdAt"`
 	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt"`
 }
 
 // Post is a post in gorm.
 type Post struct {
-	ID            uuid.UUID `json:"id" gorm:"primary_key"`
-	AuthorID      uuid.UUID `json:"authorId"`
-	Title         string    `json:"title"`
-	Content       string    `json:"content"`
-	AllowComments bool      `json:"allowComments"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID            uuid.UUID    `json:"id" gorm:"primary_key"`
+	AuthorID      uuid.UUID    `json:"authorId"`
+	Title         string       `json:"title"`
+	Content       string       `json:"content"`
+	AllowComments bool         `json:"allowComments"`
+	Attachments   []Attachment `json:"attachments" gorm:"serializer:json"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
 }
 
-// User is a user in gorm.
-type User struct {
-	ID   uuid.UUID `json:"id" gorm:"primary_key"`
-	Name string    `json:"name"`
+// Attachment is a file attached to a post, stored in the attachments column of its post.
+type Attachment struct {
+	FileID      uuid.UUID `json:"fileId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+}
+
+// Revision is a previous version of a post or comment in gorm.
+type Revision struct {
+	ID        uuid.UUID `json:"id" gorm:"primary_key"`
+	SubjectID uuid.UUID `json:"subjectId"`
+	PostID    uuid.UUID `json:"postId"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	EditorID  uuid.UUID `json:"editorId"`
+	CreatedAt time.Time `json:"createdAt"`
 }
@@ -32,7 +32,7 @@ func (r *PostSQLRepository) GetByAuthorID(ctx context.Context, userID uuid.UUID,
 	const op = "PostSQLRepository.GetByAuthorID"
 	var posts []*domain.Post
 	var postEntities []*entities.Post
-	if err := r.db.WithContext(ctx).Where("author_id = ?", userID).Limit(limit).Offset(offset).Find(&postEntities).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("author_id = ?", userID).Order(order).Limit(limit).Offset(offset).Find(&postEntities).Error; err != nil {
 		r.logger.Error(op, slog.Any("error", err.Error()))
 		return nil, err
 	}
@@ -40,5 +40,9 @@ func (r *PostSQLRepository) GetByAuthorID(ctx context.Context, userID uuid.UUID,
 		posts = append(posts, r.entityToModel(entity))
 	}
 	return posts, nil
+}
 
+// GetByAuthorIDPage returns a page of posts by a user.
+func (r *PostSQLRepository) GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	return r.findPage(ctx, r.db.Where("author_id = ?", userID), page)
 }
@@ -56,3 +56,25 @@ func TestPostSQLRepository_GetByAuthorID(t *testing.T) {
 	assert.Equal(t, 1, len(posts))
 	assert.Equal(t, postID, posts[0].ID)
 }
+
+func TestPostSQLRepository_Attachments(t *testing.T) {
+	rep := setupPostSQLRepository(t)
+
+	post := &domain.Post{
+		ID:       uuid.New(),
+		AuthorID: uuid.New(),
+		Title:    "Test post",
+		Content:  "Test content",
+		Attachments: []domain.Attachment{
+			{FileID: uuid.New(), Name: "cat.png", ContentType: "image/png", Size: 1024},
+		},
+	}
+	if err := rep.Create(context.Background(), post); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := rep.GetByID(context.Background(), post.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, post.Attachments, found.Attachments)
+}
@@ -0,0 +1,53 @@
+package sql
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/repository/sql/entities"
+	"Posts/internal/usecases"
+	"Posts/internal/utils/mappers"
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log/slog"
+)
+
+var _ usecases.RevisionRepository = &RevisionSQLRepository{}
+
+// RevisionSQLRepository is a repository for revisions.
+type RevisionSQLRepository struct {
+	AbstractSQLRepository[*domain.Revision, entities.Revision]
+}
+
+// NewRevisionSQLRepository creates a new RevisionSQLRepository.
+func NewRevisionSQLRepository(db *gorm.DB, logger *slog.Logger) *RevisionSQLRepository {
+	return &RevisionSQLRepository{
+		AbstractSQLRepository: NewAbstractSQLRepository[*domain.Revision, entities.Revision](
+			db, logger, mappers.DomainToEntityRevision, mappers.EntityToDomainRevision,
+		),
+	}
+}
+
+// GetBySubjectID returns all revisions of a post or comment.
+func (r *RevisionSQLRepository) GetBySubjectID(ctx context.Context, subjectID uuid.UUID) ([]*domain.Revision, error) {
+	const op = "RevisionSQLRepository.GetBySubjectID"
+	revisions := make([]*domain.Revision, 0)
+	var revisionEntities []*entities.Revision
+	if err := r.db.WithContext(ctx).Where("subject_id = ?", subjectID).Order(order).Find(&revisionEntities).Error; err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return nil, err
+	}
+	for _, entity := range revisionEntities {
+		revisions = append(revisions, r.entityToModel(entity))
+	}
+	return revisions, nil
+}
+
+// DeleteByPostID deletes the revisions of a post and of its comments.
+func (r *RevisionSQLRepository) DeleteByPostID(ctx context.Context, postID uuid.UUID) error {
+	const op = "RevisionSQLRepository.DeleteByPostID"
+	if err := r.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&entities.Revision{}).Error; err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package sql
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/repository/sql/entities"
+	"Posts/pkg/logger/slogdiscard"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+	"time"
+)
+
+func setupRevisionSQLRepository(t *testing.T) *RevisionSQLRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&entities.Revision{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewRevisionSQLRepository(db, slogdiscard.NewDiscardLogger())
+}
+
+func TestRevisionSQLRepository_GetBySubjectID(t *testing.T) {
+	rep := setupRevisionSQLRepository(t)
+
+	subjectID := uuid.New()
+	editedAt := time.Now().UTC()
+	for i, content := range []string{"first", "second"} {
+		err := rep.Create(context.Background(), &domain.Revision{
+			ID:        uuid.New(),
+			SubjectID: subjectID,
+			Content:   content,
+			EditorID:  uuid.New(),
+			CreatedAt: editedAt.Add(time.Duration(i) * time.Second),
+		})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, rep.Create(context.Background(), &domain.Revision{ID: uuid.New(), SubjectID: uuid.New()}))
+
+	revisions, err := rep.GetBySubjectID(context.Background(), subjectID)
+
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, "first", revisions[0].Content)
+	assert.Equal(t, "second", revisions[1].Content)
+}
+
+func TestRevisionSQLRepository_DeleteByPostID(t *testing.T) {
+	rep := setupRevisionSQLRepository(t)
+	ctx := context.Background()
+
+	postID := uuid.New()
+	commentID := uuid.New()
+	other := &domain.Revision{ID: uuid.New(), SubjectID: uuid.New(), PostID: uuid.New(), Content: "other"}
+	assert.NoError(t, rep.Create(ctx, &domain.Revision{ID: uuid.New(), SubjectID: postID, PostID: postID, Content: "post"}))
+	assert.NoError(t, rep.Create(ctx, &domain.Revision{ID: uuid.New(), SubjectID: commentID, PostID: postID, Content: "comment"}))
+	assert.NoError(t, rep.Create(ctx, other))
+
+	// The revisions of the post and of its comments are deleted
+	assert.NoError(t, rep.DeleteByPostID(ctx, postID))
+
+	revisions, err := rep.GetBySubjectID(ctx, postID)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+	revisions, err = rep.GetBySubjectID(ctx, commentID)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+	revisions, err = rep.GetBySubjectID(ctx, other.SubjectID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 1)
+}
@@ -0,0 +1,28 @@
+package sso
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/usecases"
+	"context"
+	"github.com/google/uuid"
+)
+
+var _ usecases.UserDirectory = AnonymousDirectory{}
+
+// AnonymousDirectory is a user directory for deployments without SSO, such as local development.
+// Every ID is a user, without a name or a profile.
+type AnonymousDirectory struct{}
+
+// GetByID returns a user with the ID.
+func (AnonymousDirectory) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id}, nil
+}
+
+// GetByIds returns users with the IDs.
+func (AnonymousDirectory) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	users := make([]*domain.User, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, &domain.User{ID: id})
+	}
+	return users, nil
+}
@@ -0,0 +1,118 @@
+package sso
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/usecases"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var _ usecases.UserDirectory = &Client{}
+
+// maxProfiles is the maximum number of profiles SSO returns at once.
+const maxProfiles = 100
+
+// Client reads the public profiles of users from the HTTP server of SSO.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// NewClient creates a new Client of the SSO HTTP server at url.
+func NewClient(url string, client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		client: client,
+	}
+}
+
+// profile is the public profile of a user served by SSO.
+type profile struct {
+	ID          uuid.UUID  `json:"id"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"displayName"`
+	Bio         string     `json:"bio"`
+	AvatarID    *uuid.UUID `json:"avatarId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func (p profile) user() *domain.User {
+	return &domain.User{
+		ID:          p.ID,
+		Name:        p.Username,
+		DisplayName: p.DisplayName,
+		Bio:         p.Bio,
+		AvatarID:    p.AvatarID,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+// GetByID returns a user.
+func (c *Client) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	var p profile
+	if err := c.get(ctx, "/users/"+id.String(), &p); err != nil {
+		return nil, err
+	}
+	return p.user(), nil
+}
+
+// GetByIds returns the users among ids that exist.
+func (c *Client) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	users := make([]*domain.User, 0, len(ids))
+	for start := 0; start < len(ids); start += maxProfiles {
+		query := url.Values{}
+		for _, id := range ids[start:min(start+maxProfiles, len(ids))] {
+			query.Add("id", id.String())
+		}
+
+		var list struct {
+			Users []profile `json:"users"`
+		}
+		if err := c.get(ctx, "/users?"+query.Encode(), &list); err != nil {
+			return nil, err
+		}
+		for _, p := range list.Users {
+			users = append(users, p.user())
+		}
+	}
+	return users, nil
+}
+
+// get decodes the JSON response of SSO at path into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach sso: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return domain.ErrNotFound
+	default:
+		return fmt.Errorf("sso: unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode sso response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package sso
+
+import (
+	"Posts/internal/domain"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	avatarID := uuid.New()
+	profiles := make(map[string]profile)
+	var ids []uuid.UUID
+	for i := 0; i < maxProfiles+1; i++ {
+		p := profile{ID: uuid.New(), Username: "user", DisplayName: "User", Bio: "bio", AvatarID: &avatarID}
+		profiles[p.ID.String()] = p
+		ids = append(ids, p.ID)
+	}
+	missing := uuid.New()
+	ids = append(ids, missing)
+
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		p, ok := profiles[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(p)
+	})
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()["id"]
+		assert.LessOrEqual(t, len(query), maxProfiles)
+
+		list := struct {
+			Users []profile `json:"users"`
+		}{Users: []profile{}}
+		for _, id := range query {
+			if p, ok := profiles[id]; ok {
+				list.Users = append(list.Users, p)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", nil)
+	ctx := context.Background()
+
+	user, err := client.GetByID(ctx, ids[0])
+	assert.NoError(t, err)
+	assert.Equal(t, ids[0], user.ID)
+	assert.Equal(t, "User", user.DisplayName)
+	assert.Equal(t, &avatarID, user.AvatarID)
+
+	_, err = client.GetByID(ctx, missing)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	users, err := client.GetByIds(ctx, ids)
+	assert.NoError(t, err)
+	assert.Len(t, users, maxProfiles+1)
+	assert.Equal(t, 2, requests)
+}
@@ -4,6 +4,7 @@ import (
 	"Posts/internal/domain"
 	"context"
 	"github.com/google/uuid"
+	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=AbstractRepositoryInterface
@@ -16,6 +17,7 @@ type AbstractRepositoryInterface[T domain.Model] interface {
 	GetByID(ctx context.Context, id uuid.UUID) (T, error)
 	GetByIds(ctx context.Context, ids []uuid.UUID) ([]T, error)
 	GetAll(ctx context.Context, limit int, offset int) ([]T, error)
+	GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[T], error)
 }
 
 // AbstractUseCaseInterface is an interface for use cases.
@@ -26,6 +28,7 @@ type AbstractUseCaseInterface[T domain.Model] interface {
 	GetByID(ctx context.Context, id uuid.UUID) (T, error)
 	GetByIds(ctx context.Context, ids []uuid.UUID) ([]T, error)
 	GetAll(ctx context.Context, limit int, offset int) ([]T, error)
+	GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[T], error)
 }
 
 var _ AbstractUseCaseInterface[domain.Model] = &AbstractUseCase[domain.Model]{}
@@ -43,6 +46,10 @@ func NewAbstractUseCase[T domain.Model](repository AbstractRepositoryInterface[T
 // Create creates a new entity.
 func (uc *AbstractUseCase[T]) Create(ctx context.Context, entity T) error {
 	entity.SetID(uuid.New())
+	if entity.GetCreatedAt().IsZero() {
+		// Postgres keeps microseconds, so cursors of created and loaded entities match
+		entity.SetCreatedAt(time.Now().UTC().Truncate(time.Microsecond))
+	}
 	return uc.repository.Create(ctx, entity)
 }
 
@@ -70,3 +77,11 @@ func (uc *AbstractUseCase[T]) GetByIds(ctx context.Context, ids []uuid.UUID) ([]
 func (uc *AbstractUseCase[T]) GetAll(ctx context.Context, limit int, offset int) ([]T, error) {
 	return uc.repository.GetAll(ctx, limit, offset)
 }
+
+// GetPage returns a page of entities.
+func (uc *AbstractUseCase[T]) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[T], error) {
+	if err := page.Validate(); err != nil {
+		return domain.Page[T]{}, err
+	}
+	return uc.repository.GetPage(ctx, page)
+}
@@ -14,5 +14,11 @@ type CommentUseCase interface {
 	AbstractUseCaseInterface[*domain.Comment]
 	GetChildren(ctx context.Context, commentID uuid.UUID, limit int, offset int) ([]*domain.Comment, error)
 	GetByPostID(ctx context.Context, postID uuid.UUID, limit int, offset int) ([]*domain.Comment, error)
+	GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error)
+	GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error)
 	GetLastComment(ctx context.Context, postID uuid.UUID, lastSeen time.Time, limit int) ([]*domain.Comment, error)
+	Subscribe(ctx context.Context, postID uuid.UUID) (<-chan *domain.Comment, error)
+	UpdateByAuthor(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, content string) (*domain.Comment, error)
+	DeleteByAuthor(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*domain.Comment, error)
+	GetHistory(ctx context.Context, commentID uuid.UUID) ([]*domain.Revision, error)
 }
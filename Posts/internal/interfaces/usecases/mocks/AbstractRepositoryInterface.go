@@ -140,6 +140,34 @@ func (_m *AbstractRepositoryInterface[T]) GetByIds(ctx context.Context, ids []uu
 	return r0, r1
 }
 
+// GetPage provides a mock function with given fields: ctx, page
+func (_m *AbstractRepositoryInterface[T]) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[T], error) {
+	ret := _m.Called(ctx, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPage")
+	}
+
+	var r0 domain.Page[T]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) (domain.Page[T], error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) domain.Page[T]); ok {
+		r0 = rf(ctx, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[T])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, entity
 func (_m *AbstractRepositoryInterface[T]) Update(ctx context.Context, entity T) error {
 	ret := _m.Called(ctx, entity)
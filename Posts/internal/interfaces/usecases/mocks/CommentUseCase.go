@@ -46,6 +46,32 @@ func (_m *CommentUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
+// DeleteByAuthor provides a mock function with given fields: ctx, userID, commentID
+func (_m *CommentUseCase) DeleteByAuthor(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*domain.Comment, error) {
+	ret := _m.Called(ctx, userID, commentID)
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*domain.Comment, error)); ok {
+		return rf(ctx, userID, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *domain.Comment); ok {
+		r0 = rf(ctx, userID, commentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields: ctx, limit, offset
 func (_m *CommentUseCase) GetAll(ctx context.Context, limit int, offset int) ([]*domain.Comment, error) {
 	ret := _m.Called(ctx, limit, offset)
@@ -150,6 +176,30 @@ func (_m *CommentUseCase) GetByPostID(ctx context.Context, postID uuid.UUID, lim
 	return r0, r1
 }
 
+// GetByPostIDPage provides a mock function with given fields: ctx, postID, page
+func (_m *CommentUseCase) GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	ret := _m.Called(ctx, postID, page)
+
+	var r0 domain.Page[*domain.Comment]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) (domain.Page[*domain.Comment], error)); ok {
+		return rf(ctx, postID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) domain.Page[*domain.Comment]); ok {
+		r0 = rf(ctx, postID, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Comment])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, postID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChildren provides a mock function with given fields: ctx, commentID, limit, offset
 func (_m *CommentUseCase) GetChildren(ctx context.Context, commentID uuid.UUID, limit int, offset int) ([]*domain.Comment, error) {
 	ret := _m.Called(ctx, commentID, limit, offset)
@@ -176,6 +226,56 @@ func (_m *CommentUseCase) GetChildren(ctx context.Context, commentID uuid.UUID,
 	return r0, r1
 }
 
+// GetChildrenPage provides a mock function with given fields: ctx, commentID, page
+func (_m *CommentUseCase) GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	ret := _m.Called(ctx, commentID, page)
+
+	var r0 domain.Page[*domain.Comment]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) (domain.Page[*domain.Comment], error)); ok {
+		return rf(ctx, commentID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) domain.Page[*domain.Comment]); ok {
+		r0 = rf(ctx, commentID, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Comment])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, commentID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistory provides a mock function with given fields: ctx, commentID
+func (_m *CommentUseCase) GetHistory(ctx context.Context, commentID uuid.UUID) ([]*domain.Revision, error) {
+	ret := _m.Called(ctx, commentID)
+
+	var r0 []*domain.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.Revision, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.Revision); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastComment provides a mock function with given fields: ctx, postID, lastSeen, limit
 func (_m *CommentUseCase) GetLastComment(ctx context.Context, postID uuid.UUID, lastSeen time.Time, limit int) ([]*domain.Comment, error) {
 	ret := _m.Called(ctx, postID, lastSeen, limit)
@@ -202,6 +302,56 @@ func (_m *CommentUseCase) GetLastComment(ctx context.Context, postID uuid.UUID,
 	return r0, r1
 }
 
+// GetPage provides a mock function with given fields: ctx, page
+func (_m *CommentUseCase) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 domain.Page[*domain.Comment]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) (domain.Page[*domain.Comment], error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) domain.Page[*domain.Comment]); ok {
+		r0 = rf(ctx, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Comment])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields: ctx, postID
+func (_m *CommentUseCase) Subscribe(ctx context.Context, postID uuid.UUID) (<-chan *domain.Comment, error) {
+	ret := _m.Called(ctx, postID)
+
+	var r0 <-chan *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (<-chan *domain.Comment, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) <-chan *domain.Comment); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, entity
 func (_m *CommentUseCase) Update(ctx context.Context, entity *domain.Comment) error {
 	ret := _m.Called(ctx, entity)
@@ -216,6 +366,32 @@ func (_m *CommentUseCase) Update(ctx context.Context, entity *domain.Comment) er
 	return r0
 }
 
+// UpdateByAuthor provides a mock function with given fields: ctx, userID, commentID, content
+func (_m *CommentUseCase) UpdateByAuthor(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, content string) (*domain.Comment, error) {
+	ret := _m.Called(ctx, userID, commentID, content)
+
+	var r0 *domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (*domain.Comment, error)); ok {
+		return rf(ctx, userID, commentID, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) *domain.Comment); ok {
+		r0 = rf(ctx, userID, commentID, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, commentID, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewCommentUseCase interface {
 	mock.TestingT
 	Cleanup(func())
@@ -16,6 +16,24 @@ type PostUseCase struct {
 	mock.Mock
 }
 
+// AttachmentURL provides a mock function with given fields: fileID
+func (_m *PostUseCase) AttachmentURL(fileID uuid.UUID) string {
+	ret := _m.Called(fileID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachmentURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(uuid.UUID) string); ok {
+		r0 = rf(fileID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, entity
 func (_m *PostUseCase) Create(ctx context.Context, entity *domain.Post) error {
 	ret := _m.Called(ctx, entity)
@@ -52,6 +70,24 @@ func (_m *PostUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
+// DeleteByAuthor provides a mock function with given fields: ctx, userID, postID
+func (_m *PostUseCase) DeleteByAuthor(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByAuthor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, postID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAll provides a mock function with given fields: ctx, limit, offset
 func (_m *PostUseCase) GetAll(ctx context.Context, limit int, offset int) ([]*domain.Post, error) {
 	ret := _m.Called(ctx, limit, offset)
@@ -112,6 +148,34 @@ func (_m *PostUseCase) GetByAuthorID(ctx context.Context, userID uuid.UUID, limi
 	return r0, r1
 }
 
+// GetByAuthorIDPage provides a mock function with given fields: ctx, userID, page
+func (_m *PostUseCase) GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAuthorIDPage")
+	}
+
+	var r0 domain.Page[*domain.Post]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) (domain.Page[*domain.Post], error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) domain.Page[*domain.Post]); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Post])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *PostUseCase) GetByID(ctx context.Context, id uuid.UUID) (*domain.Post, error) {
 	ret := _m.Called(ctx, id)
@@ -172,6 +236,64 @@ func (_m *PostUseCase) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain
 	return r0, r1
 }
 
+// GetHistory provides a mock function with given fields: ctx, postID
+func (_m *PostUseCase) GetHistory(ctx context.Context, postID uuid.UUID) ([]*domain.Revision, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 []*domain.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*domain.Revision, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*domain.Revision); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPage provides a mock function with given fields: ctx, page
+func (_m *PostUseCase) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	ret := _m.Called(ctx, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPage")
+	}
+
+	var r0 domain.Page[*domain.Post]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) (domain.Page[*domain.Post], error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) domain.Page[*domain.Post]); ok {
+		r0 = rf(ctx, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Post])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, entity
 func (_m *PostUseCase) Update(ctx context.Context, entity *domain.Post) error {
 	ret := _m.Called(ctx, entity)
@@ -190,6 +312,36 @@ func (_m *PostUseCase) Update(ctx context.Context, entity *domain.Post) error {
 	return r0
 }
 
+// UpdateByAuthor provides a mock function with given fields: ctx, userID, postID, title, content
+func (_m *PostUseCase) UpdateByAuthor(ctx context.Context, userID uuid.UUID, postID uuid.UUID, title *string, content *string) (*domain.Post, error) {
+	ret := _m.Called(ctx, userID, postID, title, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateByAuthor")
+	}
+
+	var r0 *domain.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *string, *string) (*domain.Post, error)); ok {
+		return rf(ctx, userID, postID, title, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *string, *string) *domain.Post); ok {
+		r0 = rf(ctx, userID, postID, title, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, *string, *string) error); ok {
+		r1 = rf(ctx, userID, postID, title, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewPostUseCase creates a new instance of PostUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPostUseCase(t interface {
@@ -16,70 +16,24 @@ type UserUseCase struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, entity
-func (_m *UserUseCase) Create(ctx context.Context, entity *domain.User) error {
-	ret := _m.Called(ctx, entity)
+// AvatarURL provides a mock function with given fields: user
+func (_m *UserUseCase) AvatarURL(user *domain.User) *string {
+	ret := _m.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for AvatarURL")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
-		r0 = rf(ctx, entity)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// Delete provides a mock function with given fields: ctx, id
-func (_m *UserUseCase) Delete(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
-
-	if len(ret) == 0 {
-		panic("no return value specified for Delete")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// GetAll provides a mock function with given fields: ctx, limit, offset
-func (_m *UserUseCase) GetAll(ctx context.Context, limit int, offset int) ([]*domain.User, error) {
-	ret := _m.Called(ctx, limit, offset)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetAll")
-	}
-
-	var r0 []*domain.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*domain.User, error)); ok {
-		return rf(ctx, limit, offset)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*domain.User); ok {
-		r0 = rf(ctx, limit, offset)
+	var r0 *string
+	if rf, ok := ret.Get(0).(func(*domain.User) *string); ok {
+		r0 = rf(user)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.User)
+			r0 = ret.Get(0).(*string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
-		r1 = rf(ctx, limit, offset)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
+	return r0
 }
 
 // GetByID provides a mock function with given fields: ctx, id
@@ -142,24 +96,6 @@ func (_m *UserUseCase) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, entity
-func (_m *UserUseCase) Update(ctx context.Context, entity *domain.User) error {
-	ret := _m.Called(ctx, entity)
-
-	if len(ret) == 0 {
-		panic("no return value specified for Update")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
-		r0 = rf(ctx, entity)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // NewUserUseCase creates a new instance of UserUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserUseCase(t interface {
@@ -12,4 +12,9 @@ import (
 type PostUseCase interface {
 	AbstractUseCaseInterface[*domain.Post]
 	GetByAuthorID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*domain.Post, error)
+	GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error)
+	UpdateByAuthor(ctx context.Context, userID uuid.UUID, postID uuid.UUID, title *string, content *string) (*domain.Post, error)
+	DeleteByAuthor(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error
+	GetHistory(ctx context.Context, postID uuid.UUID) ([]*domain.Revision, error)
+	AttachmentURL(fileID uuid.UUID) string
 }
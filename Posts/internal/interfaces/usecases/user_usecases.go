@@ -2,11 +2,15 @@ package usecases
 
 import (
 	"Posts/internal/domain"
+	"context"
+	"github.com/google/uuid"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=UserUseCase
 
 // UserUseCase is a use case for users.
 type UserUseCase interface {
-	AbstractUseCaseInterface[*domain.User]
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error)
+	AvatarURL(user *domain.User) *string
 }
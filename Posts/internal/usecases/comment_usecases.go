@@ -4,32 +4,54 @@ import (
 	"Posts/internal/domain"
 	usecaseInterfaces "Posts/internal/interfaces/usecases"
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=CommentRepository
+//go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=CommentBroker
 
 // CommentRepository is a repository for comments.
 type CommentRepository interface {
 	usecaseInterfaces.AbstractRepositoryInterface[*domain.Comment]
 	GetChildren(ctx context.Context, commentID uuid.UUID, limit int, offset int) ([]*domain.Comment, error)
 	GetByPostID(ctx context.Context, postID uuid.UUID, limit int, offset int) ([]*domain.Comment, error)
+	GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error)
+	GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error)
 	GetLastComment(ctx context.Context, postID uuid.UUID, lastSeen time.Time, limit int) ([]*domain.Comment, error)
 }
 
+// CommentBroker delivers new comments to the subscribers of a post.
+type CommentBroker interface {
+	// Publish delivers a comment to the subscribers of its post.
+	// Delivery is best effort, implementations log their errors instead of failing the caller.
+	Publish(ctx context.Context, comment *domain.Comment)
+	// Subscribe returns a channel with the new comments of a post.
+	// The channel is closed when ctx is done or when the subscriber falls behind.
+	Subscribe(ctx context.Context, postID uuid.UUID) (<-chan *domain.Comment, error)
+}
+
 var _ usecaseInterfaces.CommentUseCase = &CommentUseCase{}
 
 // CommentUseCase is a use case for comments.
 type CommentUseCase struct {
 	Repository CommentRepository
+	Posts      PostRepository
+	Broker     CommentBroker
+	Revisions  RevisionRepository
+	MaxDepth   int // maximum number of ancestors of a comment, not limited if not positive
 	usecaseInterfaces.AbstractUseCase[*domain.Comment]
 }
 
 // NewCommentUseCase creates a new CommentUseCase.
-func NewCommentUseCase(repository CommentRepository) *CommentUseCase {
+func NewCommentUseCase(repository CommentRepository, posts PostRepository, broker CommentBroker, revisions RevisionRepository, maxDepth int) *CommentUseCase {
 	return &CommentUseCase{
 		Repository:      repository,
+		Posts:           posts,
+		Broker:          broker,
+		Revisions:       revisions,
+		MaxDepth:        maxDepth,
 		AbstractUseCase: usecaseInterfaces.NewAbstractUseCase[*domain.Comment](repository),
 	}
 }
@@ -44,16 +66,162 @@ func (uc *CommentUseCase) GetByPostID(ctx context.Context, postID uuid.UUID, lim
 	return uc.Repository.GetByPostID(ctx, postID, limit, offset)
 }
 
+// GetChildrenPage returns a page of children of a comment.
+func (uc *CommentUseCase) GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	if err := page.Validate(); err != nil {
+		return domain.Page[*domain.Comment]{}, err
+	}
+	return uc.Repository.GetChildrenPage(ctx, commentID, page)
+}
+
+// GetByPostIDPage returns a page of comments of a post.
+func (uc *CommentUseCase) GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	if err := page.Validate(); err != nil {
+		return domain.Page[*domain.Comment]{}, err
+	}
+	return uc.Repository.GetByPostIDPage(ctx, postID, page)
+}
+
 // Create creates a new comment.
 func (uc *CommentUseCase) Create(ctx context.Context, entity *domain.Comment) error {
-	if len(entity.Content) > 2000 {
+	if len(entity.Content) > domain.MaxCommentLength {
 		return domain.ErrCommentIsTooLong
 	}
+	if err := uc.checkThread(ctx, entity); err != nil {
+		return err
+	}
 	entity.SetID(uuid.New())
-	return uc.AbstractUseCase.Create(ctx, entity)
+	if err := uc.AbstractUseCase.Create(ctx, entity); err != nil {
+		return err
+	}
+
+	uc.Broker.Publish(ctx, entity)
+
+	return nil
+}
+
+// checkThread checks that the comment can be added to its post and to the thread of its parent.
+func (uc *CommentUseCase) checkThread(ctx context.Context, comment *domain.Comment) error {
+	post, err := uc.Posts.GetByID(ctx, comment.PostID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrPostNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !post.AllowComments {
+		return domain.ErrCommentsDisabled
+	}
+
+	if comment.ParentID == nil {
+		return nil
+	}
+
+	parent, err := uc.Repository.GetByID(ctx, *comment.ParentID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrParentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if parent.PostID != comment.PostID {
+		return domain.ErrParentMismatch
+	}
+
+	if uc.MaxDepth <= 0 {
+		return nil
+	}
+
+	// Walk up the thread until the root or until the comment is known to be too deep
+	depth := 1
+	for ancestor := parent; ancestor.ParentID != nil; depth++ {
+		if depth >= uc.MaxDepth {
+			return domain.ErrThreadTooDeep
+		}
+		ancestor, err = uc.Repository.GetByID(ctx, *ancestor.ParentID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel with the new comments of a post.
+func (uc *CommentUseCase) Subscribe(ctx context.Context, postID uuid.UUID) (<-chan *domain.Comment, error) {
+	return uc.Broker.Subscribe(ctx, postID)
 }
 
 // GetLastComment returns the last comments of a post.
 func (uc *CommentUseCase) GetLastComment(ctx context.Context, postID uuid.UUID, lastSeen time.Time, limit int) ([]*domain.Comment, error) {
 	return uc.Repository.GetLastComment(ctx, postID, lastSeen, limit)
 }
+
+// UpdateByAuthor edits a comment of the user and keeps the previous version in its history.
+// A comment that stays the same is not edited.
+func (uc *CommentUseCase) UpdateByAuthor(ctx context.Context, userID uuid.UUID, commentID uuid.UUID, content string) (*domain.Comment, error) {
+	comment, err := uc.Repository.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, domain.ErrNotAuthor
+	}
+
+	previous := *comment
+	revision, err := comment.Edit(userID, content, time.Now().UTC().Truncate(time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return comment, nil
+	}
+
+	if err := uc.Repository.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	// The revision is written once the edit is, a failed one puts the previous version back
+	revision.SetID(uuid.New())
+	if err := uc.Revisions.Create(ctx, revision); err != nil {
+		_ = uc.Repository.Update(ctx, &previous)
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// DeleteByAuthor turns a comment of the user into a tombstone, its replies are kept.
+func (uc *CommentUseCase) DeleteByAuthor(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*domain.Comment, error) {
+	comment, err := uc.Repository.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, domain.ErrNotAuthor
+	}
+	if comment.IsDeleted() {
+		return comment, nil
+	}
+
+	comment.Delete(time.Now().UTC().Truncate(time.Microsecond))
+	if err := uc.Repository.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetHistory returns the previous versions of a comment, oldest first.
+// Deleted comments have no history.
+func (uc *CommentUseCase) GetHistory(ctx context.Context, commentID uuid.UUID) ([]*domain.Revision, error) {
+	comment, err := uc.Repository.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return []*domain.Revision{}, nil
+	}
+
+	return uc.Revisions.GetBySubjectID(ctx, commentID)
+}
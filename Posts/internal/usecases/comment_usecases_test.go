@@ -1,8 +1,10 @@
 package usecases
 
 import (
+	"Posts/internal/domain"
 	"Posts/internal/usecases/mocks"
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -11,7 +13,7 @@ import (
 
 func TestCommentUseCase_GetByPostID(t *testing.T) {
 	repo := &mocks.CommentRepository{}
-	uc := NewCommentUseCase(repo)
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, &mocks.RevisionRepository{}, 0)
 
 	repo.On("GetByPostID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
@@ -23,7 +25,7 @@ func TestCommentUseCase_GetByPostID(t *testing.T) {
 
 func TestCommentUseCase_GetChildren(t *testing.T) {
 	repo := &mocks.CommentRepository{}
-	uc := NewCommentUseCase(repo)
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, &mocks.RevisionRepository{}, 0)
 
 	repo.On("GetChildren", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
@@ -32,3 +34,221 @@ func TestCommentUseCase_GetChildren(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestCommentUseCase_Create_Publishes(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	broker := &mocks.CommentBroker{}
+	posts := &mocks.PostRepository{}
+	uc := NewCommentUseCase(repo, posts, broker, &mocks.RevisionRepository{}, 0)
+
+	comment := &domain.Comment{Content: "content"}
+
+	posts.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Post{AllowComments: true}, nil)
+	repo.On("Create", mock.Anything, comment).Return(nil)
+	broker.On("Publish", mock.Anything, comment).Return()
+
+	err := uc.Create(context.Background(), comment)
+
+	assert.NoError(t, err)
+	broker.AssertCalled(t, "Publish", mock.Anything, comment)
+}
+
+func TestCommentUseCase_Create_NotPublishedOnError(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	broker := &mocks.CommentBroker{}
+	posts := &mocks.PostRepository{}
+	uc := NewCommentUseCase(repo, posts, broker, &mocks.RevisionRepository{}, 0)
+
+	posts.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Post{AllowComments: true}, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("error"))
+
+	err := uc.Create(context.Background(), &domain.Comment{Content: "content"})
+
+	assert.Error(t, err)
+	broker.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestCommentUseCase_UpdateByAuthor(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, revisions, 0)
+
+	authorID := uuid.New()
+	comment := &domain.Comment{ID: uuid.New(), AuthorID: authorID, PostID: uuid.New(), Content: "before"}
+
+	repo.On("GetByID", mock.Anything, comment.ID).Return(comment, nil)
+	repo.On("Update", mock.Anything, comment).Return(nil)
+	revisions.On("Create", mock.Anything, mock.MatchedBy(func(revision *domain.Revision) bool {
+		return revision.SubjectID == comment.ID && revision.PostID == comment.PostID &&
+			revision.Content == "before" && revision.EditorID == authorID
+	})).Return(nil)
+
+	updated, err := uc.UpdateByAuthor(context.Background(), authorID, comment.ID, "after")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "after", updated.Content)
+	revisions.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestCommentUseCase_UpdateByAuthor_Unchanged(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, revisions, 0)
+
+	authorID := uuid.New()
+	comment := &domain.Comment{ID: uuid.New(), AuthorID: authorID, Content: "content"}
+	repo.On("GetByID", mock.Anything, comment.ID).Return(comment, nil)
+
+	updated, err := uc.UpdateByAuthor(context.Background(), authorID, comment.ID, "content")
+
+	assert.NoError(t, err)
+	assert.Equal(t, comment, updated)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	revisions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCommentUseCase_UpdateByAuthor_RevisionError(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, revisions, 0)
+
+	authorID := uuid.New()
+	comment := &domain.Comment{ID: uuid.New(), AuthorID: authorID, Content: "before"}
+	failure := errors.New("connection refused")
+	repo.On("GetByID", mock.Anything, comment.ID).Return(comment, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	revisions.On("Create", mock.Anything, mock.Anything).Return(failure)
+
+	// The edit is written first, and undone when its revision can not be
+	_, err := uc.UpdateByAuthor(context.Background(), authorID, comment.ID, "after")
+
+	assert.ErrorIs(t, err, failure)
+	repo.AssertNumberOfCalls(t, "Update", 2)
+	restored := repo.Calls[len(repo.Calls)-1].Arguments.Get(1).(*domain.Comment)
+	assert.Equal(t, "before", restored.Content)
+}
+
+func TestCommentUseCase_UpdateByAuthor_NotAuthor(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, revisions, 0)
+
+	comment := &domain.Comment{ID: uuid.New(), AuthorID: uuid.New(), Content: "before"}
+	repo.On("GetByID", mock.Anything, comment.ID).Return(comment, nil)
+
+	_, err := uc.UpdateByAuthor(context.Background(), uuid.New(), comment.ID, "after")
+
+	assert.ErrorIs(t, err, domain.ErrNotAuthor)
+	assert.Equal(t, "before", comment.Content)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCommentUseCase_DeleteByAuthor(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewCommentUseCase(repo, &mocks.PostRepository{}, &mocks.CommentBroker{}, revisions, 0)
+
+	authorID := uuid.New()
+	comment := &domain.Comment{ID: uuid.New(), AuthorID: authorID, Content: "content"}
+
+	repo.On("GetByID", mock.Anything, comment.ID).Return(comment, nil)
+	repo.On("Update", mock.Anything, comment).Return(nil)
+
+	deleted, err := uc.DeleteByAuthor(context.Background(), authorID, comment.ID)
+
+	assert.NoError(t, err)
+	assert.True(t, deleted.IsDeleted())
+	assert.Equal(t, domain.DeletedCommentContent, deleted.Content)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	// Deleted comments can not be edited and have no history
+	_, err = uc.UpdateByAuthor(context.Background(), authorID, comment.ID, "after")
+	assert.ErrorIs(t, err, domain.ErrCommentDeleted)
+
+	history, err := uc.GetHistory(context.Background(), comment.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+	revisions.AssertNotCalled(t, "GetBySubjectID", mock.Anything, mock.Anything)
+}
+
+func TestCommentUseCase_Create_PostNotFound(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	posts := &mocks.PostRepository{}
+	uc := NewCommentUseCase(repo, posts, &mocks.CommentBroker{}, &mocks.RevisionRepository{}, 0)
+
+	posts.On("GetByID", mock.Anything, mock.Anything).Return(nil, domain.ErrNotFound)
+
+	err := uc.Create(context.Background(), &domain.Comment{PostID: uuid.New(), Content: "content"})
+
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCommentUseCase_Create_CommentsDisabled(t *testing.T) {
+	repo := &mocks.CommentRepository{}
+	posts := &mocks.PostRepository{}
+	uc := NewCommentUseCase(repo, posts, &mocks.CommentBroker{}, &mocks.RevisionRepository{}, 0)
+
+	posts.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Post{AllowComments: false}, nil)
+
+	err := uc.Create(context.Background(), &domain.Comment{PostID: uuid.New(), Content: "content"})
+
+	assert.ErrorIs(t, err, domain.ErrCommentsDisabled)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCommentUseCase_Create_Parent(t *testing.T) {
+	postID := uuid.New()
+	rootID := uuid.New()
+	replyID := uuid.New()
+	otherID := uuid.New()
+
+	comments := map[uuid.UUID]*domain.Comment{
+		rootID:  {ID: rootID, PostID: postID},
+		replyID: {ID: replyID, PostID: postID, ParentID: &rootID},
+		otherID: {ID: otherID, PostID: uuid.New()},
+	}
+
+	tests := []struct {
+		name     string
+		parentID uuid.UUID
+		maxDepth int
+		err      error
+	}{
+		{name: "reply", parentID: rootID, maxDepth: 2},
+		{name: "nested reply", parentID: replyID, maxDepth: 2},
+		{name: "unlimited depth", parentID: replyID, maxDepth: 0},
+		{name: "too deep", parentID: replyID, maxDepth: 1, err: domain.ErrThreadTooDeep},
+		{name: "parent not found", parentID: uuid.New(), maxDepth: 2, err: domain.ErrParentNotFound},
+		{name: "parent of another post", parentID: otherID, maxDepth: 2, err: domain.ErrParentMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.CommentRepository{}
+			posts := &mocks.PostRepository{}
+			broker := &mocks.CommentBroker{}
+			uc := NewCommentUseCase(repo, posts, broker, &mocks.RevisionRepository{}, tt.maxDepth)
+
+			posts.On("GetByID", mock.Anything, postID).Return(&domain.Post{ID: postID, AllowComments: true}, nil)
+			repo.On("GetByID", mock.Anything, mock.Anything).Return(func(_ context.Context, id uuid.UUID) (*domain.Comment, error) {
+				if comment, ok := comments[id]; ok {
+					return comment, nil
+				}
+				return nil, domain.ErrNotFound
+			})
+			repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			broker.On("Publish", mock.Anything, mock.Anything).Return()
+
+			parentID := tt.parentID
+			err := uc.Create(context.Background(), &domain.Comment{PostID: postID, ParentID: &parentID, Content: "content"})
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
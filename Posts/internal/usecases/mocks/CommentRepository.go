@@ -174,6 +174,34 @@ func (_m *CommentRepository) GetByPostID(ctx context.Context, postID uuid.UUID,
 	return r0, r1
 }
 
+// GetByPostIDPage provides a mock function with given fields: ctx, postID, page
+func (_m *CommentRepository) GetByPostIDPage(ctx context.Context, postID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	ret := _m.Called(ctx, postID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByPostIDPage")
+	}
+
+	var r0 domain.Page[*domain.Comment]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) (domain.Page[*domain.Comment], error)); ok {
+		return rf(ctx, postID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) domain.Page[*domain.Comment]); ok {
+		r0 = rf(ctx, postID, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Comment])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, postID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChildren provides a mock function with given fields: ctx, commentID, limit, offset
 func (_m *CommentRepository) GetChildren(ctx context.Context, commentID uuid.UUID, limit int, offset int) ([]*domain.Comment, error) {
 	ret := _m.Called(ctx, commentID, limit, offset)
@@ -204,6 +232,34 @@ func (_m *CommentRepository) GetChildren(ctx context.Context, commentID uuid.UUI
 	return r0, r1
 }
 
+// GetChildrenPage provides a mock function with given fields: ctx, commentID, page
+func (_m *CommentRepository) GetChildrenPage(ctx context.Context, commentID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	ret := _m.Called(ctx, commentID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChildrenPage")
+	}
+
+	var r0 domain.Page[*domain.Comment]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) (domain.Page[*domain.Comment], error)); ok {
+		return rf(ctx, commentID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) domain.Page[*domain.Comment]); ok {
+		r0 = rf(ctx, commentID, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Comment])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, commentID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastComment provides a mock function with given fields: ctx, postID, lastSeen, limit
 func (_m *CommentRepository) GetLastComment(ctx context.Context, postID uuid.UUID, lastSeen time.Time, limit int) ([]*domain.Comment, error) {
 	ret := _m.Called(ctx, postID, lastSeen, limit)
@@ -234,6 +290,34 @@ func (_m *CommentRepository) GetLastComment(ctx context.Context, postID uuid.UUI
 	return r0, r1
 }
 
+// GetPage provides a mock function with given fields: ctx, page
+func (_m *CommentRepository) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[*domain.Comment], error) {
+	ret := _m.Called(ctx, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPage")
+	}
+
+	var r0 domain.Page[*domain.Comment]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) (domain.Page[*domain.Comment], error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) domain.Page[*domain.Comment]); ok {
+		r0 = rf(ctx, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Comment])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, entity
 func (_m *CommentRepository) Update(ctx context.Context, entity *domain.Comment) error {
 	ret := _m.Called(ctx, entity)
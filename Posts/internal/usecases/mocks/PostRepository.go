@@ -112,6 +112,34 @@ func (_m *PostRepository) GetByAuthorID(ctx context.Context, userID uuid.UUID, l
 	return r0, r1
 }
 
+// GetByAuthorIDPage provides a mock function with given fields: ctx, userID, page
+func (_m *PostRepository) GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	ret := _m.Called(ctx, userID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAuthorIDPage")
+	}
+
+	var r0 domain.Page[*domain.Post]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) (domain.Page[*domain.Post], error)); ok {
+		return rf(ctx, userID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.PageRequest) domain.Page[*domain.Post]); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Post])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *PostRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Post, error) {
 	ret := _m.Called(ctx, id)
@@ -172,6 +200,34 @@ func (_m *PostRepository) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*dom
 	return r0, r1
 }
 
+// GetPage provides a mock function with given fields: ctx, page
+func (_m *PostRepository) GetPage(ctx context.Context, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	ret := _m.Called(ctx, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPage")
+	}
+
+	var r0 domain.Page[*domain.Post]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) (domain.Page[*domain.Post], error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) domain.Page[*domain.Post]); ok {
+		r0 = rf(ctx, page)
+	} else {
+		r0 = ret.Get(0).(domain.Page[*domain.Post])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, entity
 func (_m *PostRepository) Update(ctx context.Context, entity *domain.Post) error {
 	ret := _m.Called(ctx, entity)
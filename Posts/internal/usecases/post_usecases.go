@@ -5,6 +5,7 @@ import (
 	usecaseInterfaces "Posts/internal/interfaces/usecases"
 	"context"
 	"github.com/google/uuid"
+	"time"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=PostRepository
@@ -13,25 +14,206 @@ import (
 type PostRepository interface {
 	usecaseInterfaces.AbstractRepositoryInterface[*domain.Post]
 	GetByAuthorID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*domain.Post, error)
+	GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error)
+}
+
+// MediaClient uses the files of the Media service as a user, with the credentials of the authenticated user,
+// who must be that user or an admin acting on their behalf.
+type MediaClient interface {
+	// GetFile returns a file, domain.ErrAttachmentNotFound if it does not exist or the user may not read it.
+	GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.MediaFile, error)
+	// AddReference records that owner uses a file of the user, so Media keeps it.
+	AddReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error
+	// RemoveReference removes a reference added by AddReference.
+	// It returns domain.ErrAttachmentNotFound if there is no such reference.
+	RemoveReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error
+	// FileURL returns the URL of the content of a file.
+	FileURL(id uuid.UUID) string
 }
 
 var _ usecaseInterfaces.PostUseCase = &PostUseCase{}
 
 // PostUseCase is a use case for posts.
 type PostUseCase struct {
-	Repository PostRepository
+	Repository     PostRepository
+	Revisions      RevisionRepository
+	Media          MediaClient // attachments are disabled if nil
+	MaxAttachments int         // not limited if 0
 	usecaseInterfaces.AbstractUseCase[*domain.Post]
 }
 
 // NewPostUseCase creates a new PostUseCase.
-func NewPostUseCase(repository PostRepository) *PostUseCase {
+func NewPostUseCase(repository PostRepository, revisions RevisionRepository, media MediaClient, maxAttachments int) *PostUseCase {
 	return &PostUseCase{
 		Repository:      repository,
+		Revisions:       revisions,
+		Media:           media,
+		MaxAttachments:  maxAttachments,
 		AbstractUseCase: usecaseInterfaces.NewAbstractUseCase[*domain.Post](repository),
 	}
 }
 
+// Create creates a new post with the files given by the file IDs of its attachments.
+// The files must be public files of the author, their attachments are resolved through Media as the author,
+// and Media keeps them while the post exists.
+func (uc *PostUseCase) Create(ctx context.Context, post *domain.Post) error {
+	if len(post.Attachments) == 0 {
+		return uc.AbstractUseCase.Create(ctx, post)
+	}
+
+	attachments, err := uc.resolveAttachments(ctx, post.AuthorID, post.Attachments)
+	if err != nil {
+		return err
+	}
+	post.Attachments = attachments
+
+	// The references name the post, so it gets its ID before the abstract use case would give it one
+	post.SetID(uuid.New())
+	if post.CreatedAt.IsZero() {
+		post.SetCreatedAt(time.Now().UTC().Truncate(time.Microsecond))
+	}
+
+	owner := attachmentOwner(post.ID)
+	for i, attachment := range post.Attachments {
+		if err := uc.Media.AddReference(ctx, post.AuthorID, attachment.FileID, owner); err != nil {
+			uc.removeReferences(ctx, post.AuthorID, owner, post.Attachments[:i])
+			return err
+		}
+	}
+
+	if err := uc.Repository.Create(ctx, post); err != nil {
+		uc.removeReferences(ctx, post.AuthorID, owner, post.Attachments)
+		return err
+	}
+	return nil
+}
+
+// AttachmentURL returns the URL of the content of an attached file, empty if attachments are disabled.
+func (uc *PostUseCase) AttachmentURL(fileID uuid.UUID) string {
+	if uc.Media == nil {
+		return ""
+	}
+	return uc.Media.FileURL(fileID)
+}
+
 // GetByAuthorID returns all posts by a user.
 func (uc *PostUseCase) GetByAuthorID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*domain.Post, error) {
 	return uc.Repository.GetByAuthorID(ctx, userID, limit, offset)
 }
+
+// GetByAuthorIDPage returns a page of posts by a user.
+func (uc *PostUseCase) GetByAuthorIDPage(ctx context.Context, userID uuid.UUID, page domain.PageRequest) (domain.Page[*domain.Post], error) {
+	if err := page.Validate(); err != nil {
+		return domain.Page[*domain.Post]{}, err
+	}
+	return uc.Repository.GetByAuthorIDPage(ctx, userID, page)
+}
+
+// UpdateByAuthor edits a post of the user and keeps the previous version in its history.
+// Nil values are left unchanged, at least one must be set. A post that stays the same is not edited.
+func (uc *PostUseCase) UpdateByAuthor(ctx context.Context, userID uuid.UUID, postID uuid.UUID, title *string, content *string) (*domain.Post, error) {
+	if title == nil && content == nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	post, err := uc.Repository.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != userID {
+		return nil, domain.ErrNotAuthor
+	}
+
+	previous := *post
+	revision := post.Edit(userID, title, content, time.Now().UTC().Truncate(time.Microsecond))
+	if revision == nil {
+		return post, nil
+	}
+
+	if err := uc.Repository.Update(ctx, post); err != nil {
+		return nil, err
+	}
+
+	// The revision is written once the edit is, a failed one puts the previous version back
+	revision.SetID(uuid.New())
+	if err := uc.Revisions.Create(ctx, revision); err != nil {
+		_ = uc.Repository.Update(ctx, &previous)
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// DeleteByAuthor deletes a post of the user with the history of the post and of its comments.
+func (uc *PostUseCase) DeleteByAuthor(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
+	post, err := uc.Repository.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.AuthorID != userID {
+		return domain.ErrNotAuthor
+	}
+
+	// Media may remove the files once they are not referenced, so the references go only with the post
+	if err := uc.Repository.Delete(ctx, postID); err != nil {
+		return err
+	}
+	uc.removeReferences(ctx, post.AuthorID, attachmentOwner(post.ID), post.Attachments)
+	return uc.Revisions.DeleteByPostID(ctx, postID)
+}
+
+// GetHistory returns the previous versions of a post, oldest first.
+func (uc *PostUseCase) GetHistory(ctx context.Context, postID uuid.UUID) ([]*domain.Revision, error) {
+	return uc.Revisions.GetBySubjectID(ctx, postID)
+}
+
+// resolveAttachments returns the attachments of public files of the author, given by their file IDs.
+// Posts are public, so files their readers could not read are not attached. Repeated files are attached once.
+func (uc *PostUseCase) resolveAttachments(ctx context.Context, authorID uuid.UUID, requested []domain.Attachment) ([]domain.Attachment, error) {
+	if uc.Media == nil {
+		return nil, domain.ErrAttachmentsDisabled
+	}
+
+	attachments := make([]domain.Attachment, 0, len(requested))
+	seen := make(map[uuid.UUID]bool, len(requested))
+	for _, attachment := range requested {
+		if seen[attachment.FileID] {
+			continue
+		}
+		seen[attachment.FileID] = true
+
+		if uc.MaxAttachments > 0 && len(attachments) == uc.MaxAttachments {
+			return nil, domain.ErrTooManyAttachments
+		}
+
+		file, err := uc.Media.GetFile(ctx, authorID, attachment.FileID)
+		if err != nil {
+			return nil, err
+		}
+		if file.AuthorID != authorID {
+			return nil, domain.ErrAttachmentNotOwned
+		}
+		if !file.Public() {
+			return nil, domain.ErrAttachmentNotPublic
+		}
+		attachments = append(attachments, domain.AttachmentOf(file))
+	}
+
+	return attachments, nil
+}
+
+// removeReferences removes the references of owner to the files of attachments of the author.
+// It is best effort, files left referenced are only kept longer by Media.
+func (uc *PostUseCase) removeReferences(ctx context.Context, authorID uuid.UUID, owner string, attachments []domain.Attachment) {
+	if uc.Media == nil {
+		return
+	}
+	for _, attachment := range attachments {
+		_ = uc.Media.RemoveReference(ctx, authorID, attachment.FileID, owner)
+	}
+}
+
+// attachmentOwner returns the owner of the references of a post to its attachments.
+func attachmentOwner(postID uuid.UUID) string {
+	return "post:" + postID.String()
+}
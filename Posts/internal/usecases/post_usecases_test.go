@@ -1,8 +1,10 @@
 package usecases
 
 import (
+	"Posts/internal/domain"
 	"Posts/internal/usecases/mocks"
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -11,7 +13,7 @@ import (
 
 func TestPostUseCase_GetByAuthorID(t *testing.T) {
 	repo := &mocks.PostRepository{}
-	uc := NewPostUseCase(repo)
+	uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, nil, 0)
 
 	repo.On("GetByAuthorID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
@@ -20,3 +22,308 @@ func TestPostUseCase_GetByAuthorID(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestPostUseCase_GetByAuthorIDPage_Invalid(t *testing.T) {
+	repo := &mocks.PostRepository{}
+	uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, nil, 0)
+
+	_, err := uc.GetByAuthorIDPage(context.Background(), uuid.New(), domain.PageRequest{First: domain.MaxPageSize + 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidPageSize)
+
+	_, err = uc.GetByAuthorIDPage(context.Background(), uuid.New(), domain.PageRequest{First: 1, Last: 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidPageSize)
+
+	repo.AssertNotCalled(t, "GetByAuthorIDPage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostUseCase_UpdateByAuthor(t *testing.T) {
+	repo := &mocks.PostRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewPostUseCase(repo, revisions, nil, 0)
+
+	authorID := uuid.New()
+	post := &domain.Post{ID: uuid.New(), AuthorID: authorID, Title: "title", Content: "before"}
+
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	repo.On("Update", mock.Anything, post).Return(nil)
+	revisions.On("Create", mock.Anything, mock.MatchedBy(func(revision *domain.Revision) bool {
+		return revision.SubjectID == post.ID && revision.Title == "title" && revision.Content == "before"
+	})).Return(nil)
+
+	content := "after"
+	updated, err := uc.UpdateByAuthor(context.Background(), authorID, post.ID, nil, &content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "title", updated.Title)
+	assert.Equal(t, "after", updated.Content)
+	revisions.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestPostUseCase_UpdateByAuthor_Unchanged(t *testing.T) {
+	repo := &mocks.PostRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewPostUseCase(repo, revisions, nil, 0)
+
+	authorID := uuid.New()
+	post := &domain.Post{ID: uuid.New(), AuthorID: authorID, Title: "title", Content: "content"}
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+
+	// Nothing to edit is rejected
+	_, err := uc.UpdateByAuthor(context.Background(), authorID, post.ID, nil, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+
+	// The same values leave the post and its history as they are
+	title, content := "title", "content"
+	updated, err := uc.UpdateByAuthor(context.Background(), authorID, post.ID, &title, &content)
+	assert.NoError(t, err)
+	assert.Equal(t, post, updated)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	revisions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPostUseCase_UpdateByAuthor_Errors(t *testing.T) {
+	authorID := uuid.New()
+	failure := errors.New("connection refused")
+	content := "after"
+
+	// A failed edit writes no revision
+	repo := &mocks.PostRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewPostUseCase(repo, revisions, nil, 0)
+	post := &domain.Post{ID: uuid.New(), AuthorID: authorID, Title: "title", Content: "before"}
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(failure)
+
+	_, err := uc.UpdateByAuthor(context.Background(), authorID, post.ID, nil, &content)
+	assert.ErrorIs(t, err, failure)
+	revisions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// A failed revision puts the previous version back
+	repo = &mocks.PostRepository{}
+	revisions = &mocks.RevisionRepository{}
+	uc = NewPostUseCase(repo, revisions, nil, 0)
+	post = &domain.Post{ID: uuid.New(), AuthorID: authorID, Title: "title", Content: "before"}
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	revisions.On("Create", mock.Anything, mock.Anything).Return(failure)
+
+	_, err = uc.UpdateByAuthor(context.Background(), authorID, post.ID, nil, &content)
+	assert.ErrorIs(t, err, failure)
+	repo.AssertNumberOfCalls(t, "Update", 2)
+	restored := repo.Calls[len(repo.Calls)-1].Arguments.Get(1).(*domain.Post)
+	assert.Equal(t, "before", restored.Content)
+}
+
+func TestPostUseCase_DeleteByAuthor_DeletesHistory(t *testing.T) {
+	repo := &mocks.PostRepository{}
+	revisions := &mocks.RevisionRepository{}
+	uc := NewPostUseCase(repo, revisions, nil, 0)
+
+	authorID := uuid.New()
+	post := &domain.Post{ID: uuid.New(), AuthorID: authorID}
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	repo.On("Delete", mock.Anything, post.ID).Return(nil)
+	revisions.On("DeleteByPostID", mock.Anything, post.ID).Return(nil)
+
+	err := uc.DeleteByAuthor(context.Background(), authorID, post.ID)
+
+	assert.NoError(t, err)
+	revisions.AssertCalled(t, "DeleteByPostID", mock.Anything, post.ID)
+}
+
+func TestPostUseCase_DeleteByAuthor_NotAuthor(t *testing.T) {
+	repo := &mocks.PostRepository{}
+	uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, nil, 0)
+
+	post := &domain.Post{ID: uuid.New(), AuthorID: uuid.New()}
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+
+	err := uc.DeleteByAuthor(context.Background(), uuid.New(), post.ID)
+
+	assert.ErrorIs(t, err, domain.ErrNotAuthor)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// fakeMedia is an in-process Media service with the files and references of its maps.
+// Users read public files and their own ones, and reference their own files.
+type fakeMedia struct {
+	files      map[uuid.UUID]domain.MediaFile
+	references map[uuid.UUID]map[string]bool
+}
+
+func newFakeMedia(files ...domain.MediaFile) *fakeMedia {
+	media := &fakeMedia{
+		files:      make(map[uuid.UUID]domain.MediaFile),
+		references: make(map[uuid.UUID]map[string]bool),
+	}
+	for _, file := range files {
+		media.files[file.ID] = file
+	}
+	return media
+}
+
+func (m *fakeMedia) GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.MediaFile, error) {
+	file, ok := m.files[id]
+	if !ok || (!file.Public() && file.AuthorID != userID) {
+		return domain.MediaFile{}, domain.ErrAttachmentNotFound
+	}
+	return file, nil
+}
+
+func (m *fakeMedia) AddReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	file, ok := m.files[fileID]
+	if !ok {
+		return domain.ErrAttachmentNotFound
+	}
+	if file.AuthorID != userID {
+		return domain.ErrAttachmentNotOwned
+	}
+	if m.references[fileID] == nil {
+		m.references[fileID] = make(map[string]bool)
+	}
+	m.references[fileID][owner] = true
+	return nil
+}
+
+func (m *fakeMedia) RemoveReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	if !m.references[fileID][owner] || m.files[fileID].AuthorID != userID {
+		return domain.ErrAttachmentNotFound
+	}
+	delete(m.references[fileID], owner)
+	return nil
+}
+
+func (m *fakeMedia) FileURL(id uuid.UUID) string {
+	return "https://media.test/files/" + id.String()
+}
+
+// publicFile returns a public file of the author.
+func publicFile(authorID uuid.UUID) domain.MediaFile {
+	return domain.MediaFile{ID: uuid.New(), AuthorID: authorID, Access: domain.MediaAccess{Visibility: domain.MediaVisibilityPublic}}
+}
+
+// newPostWithAttachments returns a new post of the author with attachments of files given by their IDs.
+func newPostWithAttachments(authorID uuid.UUID, fileIDs ...uuid.UUID) *domain.Post {
+	post := &domain.Post{AuthorID: authorID, Title: "title", Content: "content"}
+	for _, fileID := range fileIDs {
+		post.Attachments = append(post.Attachments, domain.Attachment{FileID: fileID})
+	}
+	return post
+}
+
+func TestPostUseCase_Create_Attachments(t *testing.T) {
+	authorID := uuid.New()
+	public := domain.MediaAccess{Visibility: domain.MediaVisibilityPublic}
+	image := domain.MediaFile{ID: uuid.New(), AuthorID: authorID, Name: "cat.png", ContentType: "image/png", Size: 1024, Access: public}
+	document := domain.MediaFile{ID: uuid.New(), AuthorID: authorID, Name: "notes.pdf", ContentType: "application/pdf", Size: 2048, Access: public}
+	media := newFakeMedia(image, document)
+
+	repo := &mocks.PostRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, media, 10)
+
+	post := newPostWithAttachments(authorID, image.ID, document.ID, image.ID)
+	err := uc.Create(context.Background(), post)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, post.ID)
+	assert.Equal(t, []domain.Attachment{domain.AttachmentOf(image), domain.AttachmentOf(document)}, post.Attachments)
+	assert.True(t, media.references[image.ID]["post:"+post.ID.String()])
+	assert.True(t, media.references[document.ID]["post:"+post.ID.String()])
+	assert.Equal(t, "https://media.test/files/"+image.ID.String(), uc.AttachmentURL(image.ID))
+}
+
+func TestPostUseCase_Create_InvalidAttachments(t *testing.T) {
+	authorID := uuid.New()
+	own := publicFile(authorID)
+	ownToo := publicFile(authorID)
+	other := publicFile(uuid.New())
+	private := domain.MediaFile{ID: uuid.New(), AuthorID: authorID, Access: domain.MediaAccess{Visibility: "private"}}
+	shared := domain.MediaFile{ID: uuid.New(), AuthorID: authorID, Access: domain.MediaAccess{Visibility: "shared"}}
+
+	tests := []struct {
+		name  string
+		media MediaClient
+		post  *domain.Post
+		err   error
+	}{
+		{"not found", newFakeMedia(own), newPostWithAttachments(authorID, own.ID, uuid.New()), domain.ErrAttachmentNotFound},
+		{"not owned", newFakeMedia(own, other), newPostWithAttachments(authorID, own.ID, other.ID), domain.ErrAttachmentNotOwned},
+		{"private", newFakeMedia(own, private), newPostWithAttachments(authorID, own.ID, private.ID), domain.ErrAttachmentNotPublic},
+		{"shared", newFakeMedia(own, shared), newPostWithAttachments(authorID, own.ID, shared.ID), domain.ErrAttachmentNotPublic},
+		{"too many", newFakeMedia(own, ownToo), newPostWithAttachments(authorID, own.ID, ownToo.ID, uuid.New()), domain.ErrTooManyAttachments},
+		{"disabled", nil, newPostWithAttachments(authorID, own.ID), domain.ErrAttachmentsDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.PostRepository{}
+			uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, tt.media, 2)
+
+			err := uc.Create(context.Background(), tt.post)
+
+			assert.ErrorIs(t, err, tt.err)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			if media, ok := tt.media.(*fakeMedia); ok {
+				assert.Empty(t, media.references[own.ID])
+			}
+		})
+	}
+}
+
+func TestPostUseCase_Create_RepositoryError(t *testing.T) {
+	authorID := uuid.New()
+	file := publicFile(authorID)
+	media := newFakeMedia(file)
+
+	repo := &mocks.PostRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+	uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, media, 0)
+
+	err := uc.Create(context.Background(), newPostWithAttachments(authorID, file.ID))
+
+	assert.Error(t, err)
+	assert.Empty(t, media.references[file.ID])
+}
+
+func TestPostUseCase_DeleteByAuthor_RemovesReferences(t *testing.T) {
+	authorID := uuid.New()
+	file := publicFile(authorID)
+	media := newFakeMedia(file)
+
+	repo := &mocks.PostRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	revisions := &mocks.RevisionRepository{}
+	uc := NewPostUseCase(repo, revisions, media, 0)
+
+	post := newPostWithAttachments(authorID, file.ID)
+	assert.NoError(t, uc.Create(context.Background(), post))
+	assert.Len(t, media.references[file.ID], 1)
+
+	repo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+	repo.On("Delete", mock.Anything, post.ID).Return(nil)
+	revisions.On("DeleteByPostID", mock.Anything, post.ID).Return(nil)
+
+	err := uc.DeleteByAuthor(context.Background(), authorID, post.ID)
+
+	assert.NoError(t, err)
+	assert.Empty(t, media.references[file.ID])
+}
+
+func TestPostUseCase_Create_OnBehalfOfAuthor(t *testing.T) {
+	authorID := uuid.New()
+	file := publicFile(authorID)
+	media := newFakeMedia(file)
+
+	repo := &mocks.PostRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	uc := NewPostUseCase(repo, &mocks.RevisionRepository{}, media, 0)
+
+	// Media is used as the author of the post, whoever creates it, so admins acting for them attach their files
+	post := newPostWithAttachments(authorID, file.ID)
+	err := uc.Create(context.Background(), post)
+
+	assert.NoError(t, err)
+	assert.True(t, media.references[file.ID]["post:"+post.ID.String()])
+}
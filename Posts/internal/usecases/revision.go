@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"Posts/internal/domain"
+	usecaseInterfaces "Posts/internal/interfaces/usecases"
+	"context"
+	"github.com/google/uuid"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=RevisionRepository
+
+// RevisionRepository is a repository for revisions of posts and comments.
+type RevisionRepository interface {
+	usecaseInterfaces.AbstractRepositoryInterface[*domain.Revision]
+	GetBySubjectID(ctx context.Context, subjectID uuid.UUID) ([]*domain.Revision, error)
+	DeleteByPostID(ctx context.Context, postID uuid.UUID) error
+}
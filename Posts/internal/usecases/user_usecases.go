@@ -3,27 +3,71 @@ package usecases
 import (
 	"Posts/internal/domain"
 	usecaseInterfaces "Posts/internal/interfaces/usecases"
+	"context"
+	"errors"
+	"github.com/google/uuid"
 )
 
-//go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=UserRepository
+//go:generate go run github.com/vektra/mockery/v2@v2.40.2 --name=UserDirectory
 
-// UserRepository is a repository for users.
-type UserRepository interface {
-	usecaseInterfaces.AbstractRepositoryInterface[*domain.User]
+// UserDirectory reads users from SSO, which owns them.
+type UserDirectory interface {
+	// GetByID returns a user, domain.ErrNotFound if it does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// GetByIds returns the users among ids that exist, in any order.
+	GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error)
 }
 
 var _ usecaseInterfaces.UserUseCase = &UserUseCase{}
 
 // UserUseCase is a use case for users.
 type UserUseCase struct {
-	Repository UserRepository
-	usecaseInterfaces.AbstractUseCase[*domain.User]
+	Directory UserDirectory
+	Media     MediaClient // avatars have no URL if nil
 }
 
 // NewUserUseCase creates a new UserUseCase.
-func NewUserUseCase(repository UserRepository) *UserUseCase {
+func NewUserUseCase(directory UserDirectory, media MediaClient) *UserUseCase {
 	return &UserUseCase{
-		Repository:      repository,
-		AbstractUseCase: usecaseInterfaces.NewAbstractUseCase[*domain.User](repository),
+		Directory: directory,
+		Media:     media,
 	}
 }
+
+// GetByID returns a user.
+func (uc *UserUseCase) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return uc.Directory.GetByID(ctx, id)
+}
+
+// GetByIds returns the users of ids, in the same order.
+// Users deleted from SSO are returned as domain.DeletedUser, so their posts and comments keep an author.
+func (uc *UserUseCase) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	found, err := uc.Directory.GetByIds(ctx, ids)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.User, len(found))
+	for _, user := range found {
+		byID[user.ID] = user
+	}
+
+	users := make([]*domain.User, 0, len(ids))
+	for _, id := range ids {
+		user, ok := byID[id]
+		if !ok {
+			user = domain.DeletedUser(id)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// AvatarURL returns the URL of the avatar of a user, nil if the user has no avatar or Media is not configured.
+func (uc *UserUseCase) AvatarURL(user *domain.User) *string {
+	if user.AvatarID == nil || uc.Media == nil {
+		return nil
+	}
+	url := uc.Media.FileURL(*user.AvatarID)
+	return &url
+}
@@ -4,68 +4,69 @@ import (
 	"Posts/internal/domain"
 	"Posts/internal/usecases/mocks"
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
 )
 
-func TestUserUseCase_Create(t *testing.T) {
-	repo := &mocks.UserRepository{}
-	uc := NewUserUseCase(repo)
+func TestUserUseCase_GetByID(t *testing.T) {
+	directory := &mocks.UserDirectory{}
+	uc := NewUserUseCase(directory, nil)
 
-	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	directory.On("GetByID", mock.Anything, mock.Anything).Return(nil, nil)
 
-	entity := &domain.User{}
-	err := uc.Create(context.Background(), entity)
+	id := uuid.UUID{}
+	_, err := uc.GetByID(context.Background(), id)
 
 	assert.NoError(t, err)
 }
 
-func TestUserUseCase_Update(t *testing.T) {
-	repo := &mocks.UserRepository{}
-	uc := NewUserUseCase(repo)
-
-	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
-
-	entity := &domain.User{}
-	err := uc.Update(context.Background(), entity)
+func TestUserUseCase_GetByIds(t *testing.T) {
+	directory := &mocks.UserDirectory{}
+	uc := NewUserUseCase(directory, nil)
 
-	assert.NoError(t, err)
-}
+	first := &domain.User{ID: uuid.New(), Name: "first"}
+	second := &domain.User{ID: uuid.New(), Name: "second"}
+	deletedID := uuid.New()
+	ids := []uuid.UUID{second.ID, deletedID, first.ID}
 
-func TestUserUseCase_Delete(t *testing.T) {
-	repo := &mocks.UserRepository{}
-	uc := NewUserUseCase(repo)
+	directory.On("GetByIds", mock.Anything, ids).Return([]*domain.User{first, second}, nil)
 
-	repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
-
-	id := uuid.UUID{}
-	err := uc.Delete(context.Background(), id)
+	users, err := uc.GetByIds(context.Background(), ids)
 
 	assert.NoError(t, err)
+	if assert.Len(t, users, 3) {
+		assert.Equal(t, second, users[0])
+		assert.Equal(t, domain.DeletedUser(deletedID), users[1])
+		assert.Equal(t, first, users[2])
+	}
 }
 
-func TestUserUseCase_GetByID(t *testing.T) {
-	repo := &mocks.UserRepository{}
-	uc := NewUserUseCase(repo)
+func TestUserUseCase_GetByIds_Error(t *testing.T) {
+	directory := &mocks.UserDirectory{}
+	uc := NewUserUseCase(directory, nil)
 
-	repo.On("GetByID", mock.Anything, mock.Anything).Return(nil, nil)
+	errDirectory := errors.New("sso is unavailable")
+	directory.On("GetByIds", mock.Anything, mock.Anything).Return(nil, errDirectory)
 
-	id := uuid.UUID{}
-	_, err := uc.GetByID(context.Background(), id)
+	_, err := uc.GetByIds(context.Background(), []uuid.UUID{uuid.New()})
 
-	assert.NoError(t, err)
+	assert.ErrorIs(t, err, errDirectory)
 }
 
-func TestUserUseCase_GetByIds(t *testing.T) {
-	repo := &mocks.UserRepository{}
-	uc := NewUserUseCase(repo)
-
-	repo.On("GetByIds", mock.Anything, mock.Anything).Return(nil, nil)
+func TestUserUseCase_AvatarURL(t *testing.T) {
+	avatarID := uuid.New()
+	withAvatar := &domain.User{ID: uuid.New(), AvatarID: &avatarID}
+	withoutAvatar := &domain.User{ID: uuid.New()}
 
-	var ids []uuid.UUID
-	_, err := uc.GetByIds(context.Background(), ids)
+	uc := NewUserUseCase(&mocks.UserDirectory{}, newFakeMedia())
+	if url := uc.AvatarURL(withAvatar); assert.NotNil(t, url) {
+		assert.Equal(t, "https://media.test/files/"+avatarID.String(), *url)
+	}
+	assert.Nil(t, uc.AvatarURL(withoutAvatar))
 
-	assert.NoError(t, err)
+	uc = NewUserUseCase(&mocks.UserDirectory{}, nil)
+	assert.Nil(t, uc.AvatarURL(withAvatar))
 }
@@ -0,0 +1,64 @@
+package mappers
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/model"
+	"Posts/internal/infrastructure/repository/sql/entities"
+)
+
+// ModelToDomainAttachments maps model.Attachments to domain.Attachments.
+func ModelToDomainAttachments(dtos []*model.Attachment) []domain.Attachment {
+	attachments := make([]domain.Attachment, 0, len(dtos))
+	for _, dto := range dtos {
+		attachments = append(attachments, domain.Attachment{
+			FileID:      dto.FileID,
+			Name:        dto.Name,
+			ContentType: dto.ContentType,
+			Size:        int64(dto.Size),
+		})
+	}
+	return attachments
+}
+
+// DomainToModelAttachments maps domain.Attachments to model.Attachments.
+// Their URLs are resolved by the attachment resolver.
+func DomainToModelAttachments(attachments []domain.Attachment) []*model.Attachment {
+	dtos := make([]*model.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		dtos = append(dtos, &model.Attachment{
+			FileID:      attachment.FileID,
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Size:        int(attachment.Size),
+		})
+	}
+	return dtos
+}
+
+// DomainToEntityAttachments maps domain.Attachments to entities.Attachments.
+func DomainToEntityAttachments(attachments []domain.Attachment) []entities.Attachment {
+	result := make([]entities.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		result = append(result, entities.Attachment{
+			FileID:      attachment.FileID,
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+	return result
+}
+
+// EntityToDomainAttachments maps entities.Attachments to domain.Attachments.
+func EntityToDomainAttachments(entityAttachments []entities.Attachment) []domain.Attachment {
+	attachments := make([]domain.Attachment, 0, len(entityAttachments))
+	for _, attachment := range entityAttachments {
+		attachments = append(attachments, domain.Attachment{
+			FileID:      attachment.FileID,
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+	return attachments
+}
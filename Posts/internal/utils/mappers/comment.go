@@ -27,6 +27,7 @@ func DomainToModelComment(domain *domain.Comment) *model.Comment {
 		ParentID:  domain.ParentID,
 		Content:   domain.Content,
 		AuthorID:  domain.AuthorID,
+		Deleted:   domain.IsDeleted(),
 		CreatedAt: domain.CreatedAt,
 		UpdatedAt: domain.UpdatedAt,
 	}
@@ -42,6 +43,7 @@ func DomainToEntityComment(domain *domain.Comment) *entities.Comment {
 		AuthorID:  domain.AuthorID,
 		CreatedAt: domain.CreatedAt,
 		UpdatedAt: domain.UpdatedAt,
+		DeletedAt: domain.DeletedAt,
 	}
 }
 
@@ -55,6 +57,7 @@ func EntityToDomainComment(entity *entities.Comment) *domain.Comment {
 		AuthorID:  entity.AuthorID,
 		CreatedAt: entity.CreatedAt,
 		UpdatedAt: entity.UpdatedAt,
+		DeletedAt: entity.DeletedAt,
 	}
 }
 
@@ -64,6 +67,5 @@ func CreateDTOToDomainComment(dto *model.NewComment) *domain.Comment {
 		PostID:   dto.PostID,
 		ParentID: dto.ParentID,
 		Content:  dto.Content,
-		AuthorID: dto.AuthorID,
 	}
 }
@@ -0,0 +1,113 @@
+package mappers
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/model"
+	"encoding/base64"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+// EncodeCursor encodes a domain.Cursor as an opaque string.
+func EncodeCursor(cursor domain.Cursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodes a string made by EncodeCursor.
+func DecodeCursor(cursor string) (domain.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return domain.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return domain.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return domain.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Cursor{}, domain.ErrInvalidCursor
+	}
+
+	return domain.Cursor{CreatedAt: t, ID: u}, nil
+}
+
+// ConnectionArgsToDomainPageRequest maps the arguments of a connection field to a domain.PageRequest.
+// Without first and last, the first DefaultPageSize items are requested.
+func ConnectionArgsToDomainPageRequest(first *int, after *string, last *int, before *string) (domain.PageRequest, error) {
+	var page domain.PageRequest
+
+	if first != nil {
+		page.First = *first
+	}
+	if last != nil {
+		page.Last = *last
+	}
+	if first == nil && last == nil {
+		page.First = domain.DefaultPageSize
+	}
+
+	if after != nil {
+		cursor, err := DecodeCursor(*after)
+		if err != nil {
+			return page, err
+		}
+		page.After = &cursor
+	}
+	if before != nil {
+		cursor, err := DecodeCursor(*before)
+		if err != nil {
+			return page, err
+		}
+		page.Before = &cursor
+	}
+
+	return page, nil
+}
+
+// DomainToModelPageInfo maps a domain.Page to a model.PageInfo.
+func DomainToModelPageInfo[T domain.Model](page domain.Page[T]) *model.PageInfo {
+	pageInfo := &model.PageInfo{
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: page.HasPreviousPage,
+	}
+	if len(page.Items) > 0 {
+		start := EncodeCursor(domain.CursorOf(page.Items[0]))
+		end := EncodeCursor(domain.CursorOf(page.Items[len(page.Items)-1]))
+		pageInfo.StartCursor = &start
+		pageInfo.EndCursor = &end
+	}
+	return pageInfo
+}
+
+// DomainToModelPostConnection maps a domain.Page of posts to a model.PostConnection.
+func DomainToModelPostConnection(page domain.Page[*domain.Post]) *model.PostConnection {
+	edges := make([]*model.PostEdge, 0, len(page.Items))
+	for _, post := range page.Items {
+		edges = append(edges, &model.PostEdge{
+			Cursor: EncodeCursor(domain.CursorOf(post)),
+			Node:   DomainToModelPost(post),
+		})
+	}
+	return &model.PostConnection{Edges: edges, PageInfo: DomainToModelPageInfo(page)}
+}
+
+// DomainToModelCommentConnection maps a domain.Page of comments to a model.CommentConnection.
+func DomainToModelCommentConnection(page domain.Page[*domain.Comment]) *model.CommentConnection {
+	edges := make([]*model.CommentEdge, 0, len(page.Items))
+	for _, comment := range page.Items {
+		edges = append(edges, &model.CommentEdge{
+			Cursor: EncodeCursor(domain.CursorOf(comment)),
+			Node:   DomainToModelComment(comment),
+		})
+	}
+	return &model.CommentConnection{Edges: edges, PageInfo: DomainToModelPageInfo(page)}
+}
@@ -14,6 +14,7 @@ func ModelToDomainPost(dto *model.Post) *domain.Post {
 		Content:       dto.Content,
 		AuthorID:      dto.AuthorID,
 		AllowComments: dto.AllowComments,
+		Attachments:   ModelToDomainAttachments(dto.Attachments),
 		CreatedAt:     dto.CreatedAt,
 		UpdatedAt:     dto.UpdatedAt,
 	}
@@ -27,6 +28,7 @@ func DomainToModelPost(domain *domain.Post) *model.Post {
 		Content:       domain.Content,
 		AuthorID:      domain.AuthorID,
 		AllowComments: domain.AllowComments,
+		Attachments:   DomainToModelAttachments(domain.Attachments),
 		CreatedAt:     domain.CreatedAt,
 		UpdatedAt:     domain.UpdatedAt,
 	}
@@ -40,6 +42,7 @@ func DomainToEntityPost(domain *domain.Post) *entities.Post {
 		Content:       domain.Content,
 		AuthorID:      domain.AuthorID,
 		AllowComments: domain.AllowComments,
+		Attachments:   DomainToEntityAttachments(domain.Attachments),
 		CreatedAt:     domain.CreatedAt,
 		UpdatedAt:     domain.UpdatedAt,
 	}
@@ -53,6 +56,7 @@ func EntityToDomainPost(entity *entities.Post) *domain.Post {
 		Content:       entity.Content,
 		AuthorID:      entity.AuthorID,
 		AllowComments: entity.AllowComments,
+		Attachments:   EntityToDomainAttachments(entity.Attachments),
 		CreatedAt:     entity.CreatedAt,
 		UpdatedAt:     entity.UpdatedAt,
 	}
@@ -64,10 +68,14 @@ func CreateDTOToDomainPost(dto *model.NewPost) *domain.Post {
 	if dto.AllowComments != nil {
 		allowComments = *dto.AllowComments
 	}
+	attachments := make([]domain.Attachment, 0, len(dto.AttachmentIds))
+	for _, fileID := range dto.AttachmentIds {
+		attachments = append(attachments, domain.Attachment{FileID: fileID})
+	}
 	return &domain.Post{
 		Title:         dto.Title,
 		Content:       dto.Content,
-		AuthorID:      dto.AuthorID,
 		AllowComments: allowComments,
+		Attachments:   attachments,
 	}
 }
@@ -0,0 +1,47 @@
+package mappers
+
+import (
+	"Posts/internal/domain"
+	"Posts/internal/infrastructure/graph/model"
+	"Posts/internal/infrastructure/repository/sql/entities"
+)
+
+// DomainToModelRevision maps a domain.Revision to a model.Revision.
+func DomainToModelRevision(domain *domain.Revision) *model.Revision {
+	revision := &model.Revision{
+		ID:        domain.ID,
+		Content:   domain.Content,
+		EditorID:  domain.EditorID,
+		CreatedAt: domain.CreatedAt,
+	}
+	if domain.Title != "" {
+		revision.Title = &domain.Title
+	}
+	return revision
+}
+
+// DomainToEntityRevision maps a domain.Revision to an entities.Revision.
+func DomainToEntityRevision(domain *domain.Revision) *entities.Revision {
+	return &entities.Revision{
+		ID:        domain.ID,
+		SubjectID: domain.SubjectID,
+		PostID:    domain.PostID,
+		Title:     domain.Title,
+		Content:   domain.Content,
+		EditorID:  domain.EditorID,
+		CreatedAt: domain.CreatedAt,
+	}
+}
+
+// EntityToDomainRevision maps an entities.Revision to a domain.Revision.
+func EntityToDomainRevision(entity *entities.Revision) *domain.Revision {
+	return &domain.Revision{
+		ID:        entity.ID,
+		SubjectID: entity.SubjectID,
+		PostID:    entity.PostID,
+		Title:     entity.Title,
+		Content:   entity.Content,
+		EditorID:  entity.EditorID,
+		CreatedAt: entity.CreatedAt,
+	}
+}
@@ -0,0 +1,11 @@
+-- Drop keyset pagination indexes
+DROP INDEX IF EXISTS idx_comments_parent_id_created_at_id;
+DROP INDEX IF EXISTS idx_comments_post_id_created_at_id;
+DROP INDEX IF EXISTS idx_posts_author_id_created_at_id;
+DROP INDEX IF EXISTS idx_posts_created_at_id;
+DROP INDEX IF EXISTS idx_users_created_at_id;
+
+-- Drop timestamps from users table
+ALTER TABLE users
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
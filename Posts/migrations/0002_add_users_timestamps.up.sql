@@ -0,0 +1,11 @@
+-- Add timestamps to users table
+ALTER TABLE users
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+-- Keyset pagination indexes on (created_at, id)
+CREATE INDEX idx_users_created_at_id ON users (created_at, id);
+CREATE INDEX idx_posts_created_at_id ON posts (created_at, id);
+CREATE INDEX idx_posts_author_id_created_at_id ON posts (author_id, created_at, id);
+CREATE INDEX idx_comments_post_id_created_at_id ON comments (post_id, created_at, id) WHERE parent_id IS NULL;
+CREATE INDEX idx_comments_parent_id_created_at_id ON comments (parent_id, created_at, id);
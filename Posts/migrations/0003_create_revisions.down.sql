@@ -0,0 +1,5 @@
+-- Drop revisions table
+DROP TABLE IF EXISTS revisions;
+
+-- Drop comment tombstones
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
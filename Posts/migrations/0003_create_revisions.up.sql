@@ -0,0 +1,15 @@
+-- Soft delete comments as tombstones
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Create revisions table
+CREATE TABLE revisions (
+                           id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                           subject_id UUID NOT NULL,
+                           title VARCHAR(100) NOT NULL DEFAULT '',
+                           content TEXT NOT NULL,
+                           editor_id UUID NOT NULL,
+                           created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                           CONSTRAINT fk_editor_revision FOREIGN KEY(editor_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_revisions_subject_id_created_at_id ON revisions (subject_id, created_at, id);
@@ -0,0 +1,2 @@
+-- Drop post attachments
+ALTER TABLE posts DROP COLUMN IF EXISTS attachments;
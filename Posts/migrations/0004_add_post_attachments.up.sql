@@ -0,0 +1,2 @@
+-- Attachments are snapshots of Media files, kept with their post
+ALTER TABLE posts ADD COLUMN attachments JSONB NOT NULL DEFAULT '[]';
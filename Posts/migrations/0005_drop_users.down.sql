@@ -0,0 +1,21 @@
+-- Recreate users table, with the authors and editors of the existing content
+CREATE TABLE users (
+                       id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                       name VARCHAR(100) NOT NULL,
+                       created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+                       updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_users_created_at_id ON users (created_at, id);
+
+INSERT INTO users (id, name)
+SELECT author_id, '' FROM posts
+UNION
+SELECT author_id, '' FROM comments
+UNION
+SELECT editor_id, '' FROM revisions;
+
+-- Restore foreign keys to users
+ALTER TABLE posts ADD CONSTRAINT fk_author FOREIGN KEY(author_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE comments ADD CONSTRAINT fk_author_comment FOREIGN KEY(author_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER TABLE revisions ADD CONSTRAINT fk_editor_revision FOREIGN KEY(editor_id) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,7 @@
+-- Users are owned by SSO, authors are no longer checked against a local copy
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_author;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS fk_author_comment;
+ALTER TABLE revisions DROP CONSTRAINT IF EXISTS fk_editor_revision;
+
+-- Drop users table
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,4 @@
+-- Drop the post of revisions
+DROP INDEX IF EXISTS idx_revisions_post_id;
+ALTER TABLE revisions DROP CONSTRAINT IF EXISTS fk_post_revision;
+ALTER TABLE revisions DROP COLUMN IF EXISTS post_id;
@@ -0,0 +1,13 @@
+-- Revisions belong to a post, directly or through a comment, and are deleted with it
+ALTER TABLE revisions ADD COLUMN post_id UUID;
+
+UPDATE revisions SET post_id = subject_id WHERE subject_id IN (SELECT id FROM posts);
+UPDATE revisions SET post_id = comments.post_id FROM comments WHERE revisions.subject_id = comments.id;
+
+-- Drop the history of posts that were already deleted
+DELETE FROM revisions WHERE post_id IS NULL;
+
+ALTER TABLE revisions ALTER COLUMN post_id SET NOT NULL;
+ALTER TABLE revisions ADD CONSTRAINT fk_post_revision FOREIGN KEY(post_id) REFERENCES posts(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE INDEX idx_revisions_post_id ON revisions (post_id);
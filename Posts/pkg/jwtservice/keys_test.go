@@ -0,0 +1,166 @@
+package jwtservice
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return key
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "sub",
+		"exp": time.Now().Add(accessTTL).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return signed
+}
+
+func toJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestVerifier_Parse_PEM(t *testing.T) {
+	key := generateKey(t)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	path := filepath.Join(t.TempDir(), "public.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	keys, err := NewPEMKeySource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	token, err := NewVerifier(keys).Parse(signToken(t, key, ""))
+
+	assert.NoError(t, err)
+	sub, err := token.Claims.GetSubject()
+	assert.NoError(t, err)
+	assert.Equal(t, "sub", sub)
+}
+
+func TestVerifier_Parse_RejectsHS256(t *testing.T) {
+	key := generateKey(t)
+	verifier := NewVerifier(NewStaticKeySource(map[string]*rsa.PublicKey{"": &key.PublicKey}))
+
+	accessToken, _, err := NewGenerator(secret, accessTTL, refreshTTL).NewPair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = verifier.Parse(accessToken)
+
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestVerifier_Parse_RejectsRefreshToken(t *testing.T) {
+	key := generateKey(t)
+	verifier := NewVerifier(NewStaticKeySource(map[string]*rsa.PublicKey{"": &key.PublicKey}))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub":  "sub",
+		"exp":  time.Now().Add(refreshTTL).Unix(),
+		"jti":  "jti",
+		"fam":  "fam",
+		"type": "refresh",
+	})
+	refreshToken, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = verifier.Parse(refreshToken)
+
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestVerifier_Parse_JWKS(t *testing.T) {
+	first := generateKey(t)
+	second := generateKey(t)
+
+	var set atomic.Value
+	set.Store(JWKS{Keys: []JWK{toJWK("first", &first.PublicKey)}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(set.Load())
+	}))
+	defer srv.Close()
+
+	keys := NewJWKSKeySource(srv.URL, time.Hour, srv.Client())
+	keys.minRefreshInterval = 0
+	verifier := NewVerifier(keys)
+
+	_, err := verifier.Parse(signToken(t, first, "first"))
+	assert.NoError(t, err)
+
+	// Rotate: publish the second key next to the first one
+	set.Store(JWKS{Keys: []JWK{toJWK("first", &first.PublicKey), toJWK("second", &second.PublicKey)}})
+
+	_, err = verifier.Parse(signToken(t, second, "second"))
+	assert.NoError(t, err)
+
+	_, err = verifier.Parse(signToken(t, first, "first"))
+	assert.NoError(t, err)
+}
+
+func TestVerifier_Parse_JWKS_UnknownKey(t *testing.T) {
+	known := generateKey(t)
+	unknown := generateKey(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(JWKS{Keys: []JWK{
+			toJWK("known", &known.PublicKey),
+			toJWK("other", &generateKey(t).PublicKey),
+		}})
+	}))
+	defer srv.Close()
+
+	verifier := NewVerifier(NewJWKSKeySource(srv.URL, time.Hour, srv.Client()))
+
+	_, err := verifier.Parse(signToken(t, unknown, "unknown"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = verifier.Parse(signToken(t, known, ""))
+	assert.Equal(t, ErrInvalidToken, err)
+}
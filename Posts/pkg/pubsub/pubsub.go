@@ -0,0 +1,112 @@
+package pubsub
+
+import (
+	"sync"
+)
+
+// Broker is a generic in-process publish/subscribe broker.
+//
+// Every subscriber gets its own bounded buffer. Publish never blocks:
+// a subscriber whose buffer is full is considered too slow, it is
+// unsubscribed and its channel is closed, so it can reconnect instead
+// of silently missing messages.
+type Broker[key comparable, T any] struct {
+	buffer int
+	topics map[key]map[*Subscription[key, T]]struct{}
+	mu     sync.Mutex
+}
+
+// Subscription is a subscription to a topic.
+type Subscription[key comparable, T any] struct {
+	broker *Broker[key, T]
+	topic  key
+	ch     chan T
+	once   sync.Once
+}
+
+// NewBroker creates a new Broker with the given per-subscriber buffer size.
+func NewBroker[key comparable, T any](buffer int) *Broker[key, T] {
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	return &Broker[key, T]{
+		buffer: buffer,
+		topics: make(map[key]map[*Subscription[key, T]]struct{}),
+	}
+}
+
+// Subscribe subscribes to a topic.
+func (b *Broker[key, T]) Subscribe(topic key) *Subscription[key, T] {
+	sub := &Subscription[key, T]{
+		broker: b,
+		topic:  topic,
+		ch:     make(chan T, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[*Subscription[key, T]]struct{})
+	}
+	b.topics[topic][sub] = struct{}{}
+
+	return sub
+}
+
+// Publish sends a message to every subscriber of a topic and returns the number of dropped subscribers.
+func (b *Broker[key, T]) Publish(topic key, msg T) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dropped := 0
+	for sub := range b.topics[topic] {
+		select {
+		case sub.ch <- msg:
+		default:
+			b.remove(sub)
+			dropped++
+		}
+	}
+
+	return dropped
+}
+
+// Subscribers returns the number of subscribers of a topic.
+func (b *Broker[key, T]) Subscribers(topic key) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.topics[topic])
+}
+
+// remove removes a subscription and closes its channel. The caller must hold the lock.
+func (b *Broker[key, T]) remove(sub *Subscription[key, T]) {
+	subs, ok := b.topics[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.topics, sub.topic)
+	}
+	sub.once.Do(func() { close(sub.ch) })
+}
+
+// C returns the channel messages are delivered to. It is closed when the subscription ends.
+func (s *Subscription[key, T]) C() <-chan T {
+	return s.ch
+}
+
+// Unsubscribe ends the subscription.
+func (s *Subscription[key, T]) Unsubscribe() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
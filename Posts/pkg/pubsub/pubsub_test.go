@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	broker := NewBroker[string, int](4)
+
+	first := broker.Subscribe("a")
+	second := broker.Subscribe("a")
+	other := broker.Subscribe("b")
+
+	assert.Equal(t, 0, broker.Publish("a", 1))
+	assert.Equal(t, 0, broker.Publish("a", 2))
+
+	assert.Equal(t, 1, <-first.C())
+	assert.Equal(t, 2, <-first.C())
+	assert.Equal(t, 1, <-second.C())
+	assert.Equal(t, 2, <-second.C())
+	assert.Len(t, other.C(), 0)
+}
+
+func TestBroker_Publish_KeepsOrder(t *testing.T) {
+	broker := NewBroker[string, int](100)
+	sub := broker.Subscribe("a")
+
+	for i := 0; i < 100; i++ {
+		broker.Publish("a", i)
+	}
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, i, <-sub.C())
+	}
+}
+
+func TestBroker_Publish_SlowConsumer(t *testing.T) {
+	broker := NewBroker[string, int](1)
+
+	slow := broker.Subscribe("a")
+	fast := broker.Subscribe("a")
+
+	assert.Equal(t, 0, broker.Publish("a", 1))
+	assert.Equal(t, 1, <-fast.C())
+
+	// slow has not read its first message, so its buffer is full
+	assert.Equal(t, 1, broker.Publish("a", 2))
+	assert.Equal(t, 2, <-fast.C())
+	assert.Equal(t, 1, broker.Subscribers("a"))
+
+	msg, ok := <-slow.C()
+	assert.True(t, ok)
+	assert.Equal(t, 1, msg)
+
+	_, ok = <-slow.C()
+	assert.False(t, ok)
+}
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	broker := NewBroker[string, int](1)
+	sub := broker.Subscribe("a")
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	_, ok := <-sub.C()
+	assert.False(t, ok)
+	assert.Equal(t, 0, broker.Subscribers("a"))
+	assert.Equal(t, 0, broker.Publish("a", 1))
+}
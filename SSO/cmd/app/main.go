@@ -3,14 +3,19 @@ package main
 import (
 	"SSO/config"
 	"SSO/internal/infrastructure/grpcserver"
+	"SSO/internal/infrastructure/grpcserver/interceptors"
+	"SSO/internal/infrastructure/httpserver"
+	"SSO/internal/infrastructure/media"
 	gormrepository "SSO/internal/infrastructure/repositories/gorm"
 	"SSO/internal/usecases"
 	"SSO/pkg/jwt"
+	"SSO/pkg/password"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	defaultLogger "log"
 	"log/slog"
+	"net/http"
 	"os"
 )
 
@@ -55,33 +60,50 @@ func startApp() error {
 		log,
 	)
 
+	refreshTokenRepo := gormrepository.NewGormRefreshTokenRepository(
+		db,
+		log,
+	)
+
+	// Initialize Media client
+	var mediaClient usecases.MediaClient
+	if cfg.Media.URL == "" {
+		log.Info("Media is not configured, avatars are disabled")
+	} else {
+		log.Info("Using Media", slog.Any("url", cfg.Media.URL))
+		mediaClient = media.NewClient(cfg.Media.URL, interceptors.GetToken, &http.Client{Timeout: cfg.Media.Timeout})
+	}
+
 	// Initialize useCases
 	userUseCases := usecases.NewUserUseCases(
 		userRepo,
+		password.NewHasher(password.DefaultParams),
+		mediaClient,
+		log,
 	)
 
 	// Initialize jwt manager
-	publicKey, err := jwt.ReadPublicKey(cfg.Tokens.PublicKeyPath)
+	keyRing, err := readKeyRing(cfg.Tokens)
 	if err != nil {
-		log.Error("Failed to read public key", slog.Any("error", err.Error()))
-		return err
-	}
-
-	privateKey, err := jwt.ReadPrivateKey(cfg.Tokens.PrivateKeyPath)
-	if err != nil {
-		log.Error("Failed to read private key", slog.Any("error", err.Error()))
+		log.Error("Failed to read keys", slog.Any("error", err.Error()))
 		return err
 	}
+	log.Info("Signing key loaded", slog.Any("kid", keyRing.SigningKey().ID))
 
 	managerOptions := jwt.ManagerOptions{
-		PrivateKey: privateKey,
-		PublicKey:  publicKey,
+		Keys:       keyRing,
 		AccessTTL:  cfg.Tokens.AccessTTL,
 		RefreshTTL: cfg.Tokens.RefreshTTL,
+		Roles:      cfg.Tokens.Roles,
 	}
 
 	jwtManager := jwt.NewManager(managerOptions)
 
+	tokenUseCases := usecases.NewTokenUseCases(
+		refreshTokenRepo,
+		jwtManager,
+	)
+
 	// Initialize server
 	server := grpcserver.NewServer(
 		log,
@@ -89,8 +111,19 @@ func startApp() error {
 		true,
 		jwtManager,
 		userUseCases,
+		tokenUseCases,
 	)
 
+	// Start http server
+	if cfg.Server.HTTPAddress != "" {
+		httpServer := httpserver.NewServer(log, cfg.Server.HTTPAddress, keyRing, userUseCases)
+		go func() {
+			if err := httpServer.Serve(); err != nil {
+				log.Error("Failed to start http server", slog.Any("error", err.Error()))
+			}
+		}()
+	}
+
 	// Start server
 	if err := server.Serve(); err != nil {
 		log.Error("Failed to start server", slog.Any("error", err.Error()))
@@ -100,6 +133,31 @@ func startApp() error {
 	return nil
 }
 
+// readKeyRing reads the signing key pair and the verification keys.
+// A public key that does not match the private key is rejected, its JWKS could not verify the issued tokens.
+func readKeyRing(cfg config.Tokens) (*jwt.KeyRing, error) {
+	publicKey, err := jwt.ReadPublicKey(cfg.PublicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := jwt.ReadPrivateKey(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationKeys := make([]jwt.Key, 0, len(cfg.VerificationKeyPaths))
+	for _, path := range cfg.VerificationKeyPaths {
+		key, err := jwt.ReadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys = append(verificationKeys, jwt.NewKey(nil, key))
+	}
+
+	return jwt.NewKeyRing(jwt.NewKey(privateKey, publicKey), verificationKeys...)
+}
+
 // InitLogger initializes a logger based on the environment.
 func InitLogger(env string) *slog.Logger {
 	var log *slog.Logger
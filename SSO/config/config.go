@@ -13,12 +13,14 @@ type Config struct {
 	Server   Server   `yaml:"server"`
 	Postgres Postgres `yaml:"postgres"`
 	Tokens   Tokens   `yaml:"tokens"`
+	Media    Media    `yaml:"media"`
 }
 
 // Server is the configuration for the server.
 type Server struct {
-	Address string        `yaml:"address" env-required:"true"`
-	Timeout time.Duration `yaml:"timeout" env-required:"true"`
+	Address     string        `yaml:"address" env-required:"true"`
+	HTTPAddress string        `yaml:"http_address"` // serves the JWKS document, disabled if empty
+	Timeout     time.Duration `yaml:"timeout" env-required:"true"`
 }
 
 // Postgres is the configuration for the PostgreSQL database.
@@ -31,11 +33,24 @@ type Postgres struct {
 }
 
 // Tokens is the configuration for the JWT tokens.
+//
+// To rotate the signing key, move the current public key to VerificationKeyPaths
+// and point PrivateKeyPath and PublicKeyPath to the new key pair.
 type Tokens struct {
-	PrivateKeyPath string        `yaml:"private_key_path" env-required:"true"`
-	PublicKeyPath  string        `yaml:"public_key_path" env-required:"true"`
-	AccessTTL      time.Duration `yaml:"access_ttl" env-required:"true"`
-	RefreshTTL     time.Duration `yaml:"refresh_ttl" env-required:"true"`
+	PrivateKeyPath       string              `yaml:"private_key_path" env-required:"true"`
+	PublicKeyPath        string              `yaml:"public_key_path" env-required:"true"`
+	VerificationKeyPaths []string            `yaml:"verification_key_paths"`
+	AccessTTL            time.Duration       `yaml:"access_ttl" env-required:"true"`
+	RefreshTTL           time.Duration       `yaml:"refresh_ttl" env-required:"true"`
+	Roles                map[string][]string `yaml:"roles"` // user ID to roles, "admin" may act on behalf of other users
+}
+
+// Media is the configuration for the client of the Media service, which stores the avatars.
+//
+// Avatars are disabled if URL is not set.
+type Media struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout" env-default:"10s"`
 }
 
 // MustParseConfig parses the configuration from the given path.
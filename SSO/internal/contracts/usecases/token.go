@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"SSO/pkg/jwt"
+	"context"
+	"github.com/google/uuid"
+)
+
+// TokenUseCases is a use case for issuing and revoking tokens.
+type TokenUseCases interface {
+	Issue(ctx context.Context, userID uuid.UUID) (*jwt.Pair, error)
+	Refresh(ctx context.Context, accessToken string, refreshToken string) (*jwt.Pair, error)
+	Revoke(ctx context.Context, refreshToken string) error
+}
@@ -14,10 +14,14 @@ type CreateUserDTO struct {
 }
 
 // UpdateUserDTO is a data transfer object for updating a user.
+// Empty credentials and nil profile fields are left unchanged, an empty AvatarID removes the avatar.
 type UpdateUserDTO struct {
-	Username string
-	Email    string
-	Password string
+	Username    string
+	Email       string
+	Password    string
+	DisplayName *string
+	Bio         *string
+	AvatarID    *string
 }
 
 // UserUseCases is a use case for users.
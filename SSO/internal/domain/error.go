@@ -7,4 +7,7 @@ var (
 	ErrNotFound        = errors.New("not found")
 	ErrAlreadyExists   = errors.New("already exists")
 	ErrInvalidPassword = errors.New("invalid password")
+	ErrTokenRevoked    = errors.New("token revoked")
+	ErrTokenReused     = errors.New("token reused")
+	ErrInvalidProfile  = errors.New("invalid profile")
 )
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"strings"
+)
+
+// MediaFile is a file stored by the Media service.
+type MediaFile struct {
+	ID          uuid.UUID `json:"id"`
+	AuthorID    uuid.UUID `json:"authorId"`
+	ContentType string    `json:"contentType"`
+}
+
+// IsImage reports whether the file is an image, which avatars must be.
+func (f MediaFile) IsImage() bool {
+	return strings.HasPrefix(f.ContentType, "image/")
+}
+
+// AvatarOwner returns the owner of the reference of a user to their avatar in the Media service.
+func AvatarOwner(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RefreshToken is a domain model for issued refresh tokens.
+type RefreshToken struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	FamilyID     uuid.UUID  `json:"family_id"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ReplacedByID *uuid.UUID `json:"replaced_by_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// GetID returns the ID of the refresh token.
+func (t *RefreshToken) GetID() uuid.UUID {
+	return t.ID
+}
+
+// SetID sets the ID of the refresh token.
+func (t *RefreshToken) SetID(id uuid.UUID) {
+	t.ID = id
+}
+
+// IsRotated reports whether the refresh token was already exchanged for a new one.
+func (t *RefreshToken) IsRotated() bool {
+	return t.ReplacedByID != nil
+}
+
+// IsRevoked reports whether the refresh token was revoked.
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpired reports whether the refresh token is expired at the given time.
+func (t *RefreshToken) IsExpired(at time.Time) bool {
+	return !at.Before(t.ExpiresAt)
+}
+
+// Revoke revokes the refresh token at the given time.
+func (t *RefreshToken) Revoke(at time.Time) {
+	if t.RevokedAt == nil {
+		t.RevokedAt = &at
+	}
+}
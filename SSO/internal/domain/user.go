@@ -1,18 +1,31 @@
 package domain
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"time"
+	"unicode/utf8"
+)
+
+// Limits of the profile of a user, in characters.
+const (
+	MaxDisplayNameLength = 64
+	MaxBioLength         = 500
 )
 
 // User is a domain model for users.
+//
+// The display name, bio and avatar are the public profile of the user, which other services show.
 type User struct {
-	UUID           uuid.UUID `json:"uuid"`
-	Username       string    `json:"username"`
-	Email          string    `json:"email"`
-	HashedPassword [32]byte  `json:"hashed_password"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	UUID           uuid.UUID  `json:"uuid"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	HashedPassword string     `json:"hashed_password"`
+	DisplayName    string     `json:"display_name"`
+	Bio            string     `json:"bio"`
+	AvatarID       *uuid.UUID `json:"avatar_id"` // file of the Media service, nil if the user has no avatar
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // GetID returns the ID of the user.
@@ -24,3 +37,14 @@ func (u *User) GetID() uuid.UUID {
 func (u *User) SetID(id uuid.UUID) {
 	u.UUID = id
 }
+
+// ValidateProfile returns ErrInvalidProfile if the profile of the user exceeds its limits.
+func (u *User) ValidateProfile() error {
+	if utf8.RuneCountInString(u.DisplayName) > MaxDisplayNameLength {
+		return fmt.Errorf("%w: display name is longer than %d characters", ErrInvalidProfile, MaxDisplayNameLength)
+	}
+	if utf8.RuneCountInString(u.Bio) > MaxBioLength {
+		return fmt.Errorf("%w: bio is longer than %d characters", ErrInvalidProfile, MaxBioLength)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestUser_ValidateProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		displayName string
+		bio         string
+		err         bool
+	}{
+		{name: "empty"},
+		{name: "at the limits", displayName: strings.Repeat("a", MaxDisplayNameLength), bio: strings.Repeat("a", MaxBioLength)},
+		{name: "limits count characters", displayName: strings.Repeat("é", MaxDisplayNameLength), bio: strings.Repeat("日", MaxBioLength)},
+		{name: "display name too long", displayName: strings.Repeat("a", MaxDisplayNameLength+1), err: true},
+		{name: "bio too long", bio: strings.Repeat("é", MaxBioLength+1), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &User{DisplayName: tt.displayName, Bio: tt.bio}
+
+			err := user.ValidateProfile()
+
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidProfile)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
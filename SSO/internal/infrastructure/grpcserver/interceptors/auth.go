@@ -14,11 +14,15 @@ import (
 // UserIDKey is the key used to store the user ID in the context.
 const UserIDKey key = "user_id"
 
+// TokenKey is the key used to store the access token of the request in the context.
+const TokenKey key = "token"
+
 // UnprotectedMethods is a map of unprotected methods.
 var UnprotectedMethods = map[string]struct{}{
-	"/AuthService/Login":        {},
-	"/AuthService/RefreshToken": {},
-	"/AuthService/Logout":       {},
+	"/AuthService/Login":         {},
+	"/AuthService/RefreshToken":  {},
+	"/AuthService/Logout":        {},
+	"/AuthService/GetPublicKeys": {},
 
 	"/UserService/ListUsers":  {},
 	"/UserService/CreateUser": {},
@@ -86,6 +90,7 @@ func AuthInterceptor(w jwt.Parser, logger *slog.Logger) grpc.UnaryServerIntercep
 
 		// Add the user ID to the request context
 		ctx = context.WithValue(ctx, UserIDKey, userID)
+		ctx = context.WithValue(ctx, TokenKey, token)
 
 		return handler(ctx, req)
 	}
@@ -96,3 +101,9 @@ func GetUserID(ctx context.Context) string {
 	userID, _ := ctx.Value(UserIDKey).(string)
 	return userID
 }
+
+// GetToken returns the access token of the request from the context.
+func GetToken(ctx context.Context) string {
+	token, _ := ctx.Value(TokenKey).(string)
+	return token
+}
@@ -0,0 +1,83 @@
+package interceptors
+
+import (
+	"SSO/pkg/jwt"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func newTestManager(t *testing.T) *jwt.Manager {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys, err := jwt.NewKeyRing(jwt.NewKey(key, nil))
+	require.NoError(t, err)
+
+	return jwt.NewManager(jwt.ManagerOptions{Keys: keys, AccessTTL: time.Minute, RefreshTTL: time.Hour})
+}
+
+// call calls a protected method through the interceptor with the authorization header,
+// and returns the user ID and the token the handler got.
+func call(t *testing.T, manager *jwt.Manager, authorization string) (string, string, error) {
+	t.Helper()
+	interceptor := AuthInterceptor(manager, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+
+	var userID, token string
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/UserService/UpdateUser"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			userID, token = GetUserID(ctx), GetToken(ctx)
+			return nil, nil
+		})
+	return userID, token, err
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	manager := newTestManager(t)
+	pair, err := manager.GeneratePair("user")
+	require.NoError(t, err)
+
+	userID, token, err := call(t, manager, "Bearer "+pair.AccessToken)
+
+	require.NoError(t, err)
+	assert.Equal(t, "user", userID)
+	assert.Equal(t, pair.AccessToken, token)
+}
+
+func TestAuthInterceptor_Rejected(t *testing.T) {
+	manager := newTestManager(t)
+	pair, err := manager.GeneratePair("user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{name: "refresh token", authorization: "Bearer " + pair.RefreshToken},
+		{name: "no bearer prefix", authorization: pair.AccessToken},
+		{name: "invalid token", authorization: "Bearer token"},
+		{name: "token of other keys", authorization: "Bearer " + func() string {
+			other, err := newTestManager(t).GeneratePair("user")
+			require.NoError(t, err)
+			return other.AccessToken
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := call(t, manager, tt.authorization)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
@@ -29,16 +29,25 @@ type server struct {
 
 	jwtManager *jwt.Manager
 	uuc        usecases.UserUseCases
+	tuc        usecases.TokenUseCases
 }
 
 // NewServer returns a new instance of the Server.
-func NewServer(logger *slog.Logger, address string, enableReflection bool, jwtManager *jwt.Manager, uuc usecases.UserUseCases) Server {
+func NewServer(
+	logger *slog.Logger,
+	address string,
+	enableReflection bool,
+	jwtManager *jwt.Manager,
+	uuc usecases.UserUseCases,
+	tuc usecases.TokenUseCases,
+) Server {
 	return &server{
 		logger:           logger,
 		address:          address,
 		enableReflection: enableReflection,
 		jwtManager:       jwtManager,
 		uuc:              uuc,
+		tuc:              tuc,
 	}
 }
 
@@ -47,7 +56,7 @@ func (s *server) Serve() error {
 
 	lis, err := net.Listen("tcp4", s.address)
 	if err != nil {
-		s.logger.Error("failed to listen: %v", err)
+		s.logger.Error("failed to listen", slog.String("error", err.Error()))
 		return err
 	}
 
@@ -58,7 +67,7 @@ func (s *server) Serve() error {
 	)
 
 	pb.RegisterUserServiceServer(s.grpcServer, services.NewUserServiceServer(s.logger, s.uuc))
-	pb.RegisterAuthServiceServer(s.grpcServer, services.NewAuthServiceServer(s.logger, s.uuc, s.jwtManager))
+	pb.RegisterAuthServiceServer(s.grpcServer, services.NewAuthServiceServer(s.logger, s.uuc, s.tuc, s.jwtManager.Options.Keys))
 
 	if s.enableReflection {
 		reflection.Register(s.grpcServer)
@@ -3,8 +3,13 @@ package services
 import (
 	pb "SSO/gen/go"
 	"SSO/internal/contracts/usecases"
+	"SSO/internal/domain"
+	"SSO/internal/utils/mappers"
 	"SSO/pkg/jwt"
 	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"log/slog"
 )
@@ -13,18 +18,25 @@ var _ pb.AuthServiceServer = AuthServiceServer{}
 
 // AuthServiceServer is used to implement pb.AuthServiceServer.
 type AuthServiceServer struct {
-	logger     *slog.Logger
-	uuc        usecases.UserUseCases
-	jwtManager *jwt.Manager
+	logger *slog.Logger
+	uuc    usecases.UserUseCases
+	tuc    usecases.TokenUseCases
+	keys   *jwt.KeyRing
 	pb.UnimplementedAuthServiceServer
 }
 
 // NewAuthServiceServer creates a new AuthServiceServer.
-func NewAuthServiceServer(logger *slog.Logger, uuc usecases.UserUseCases, jwtManager *jwt.Manager) AuthServiceServer {
+func NewAuthServiceServer(
+	logger *slog.Logger,
+	uuc usecases.UserUseCases,
+	tuc usecases.TokenUseCases,
+	keys *jwt.KeyRing,
+) AuthServiceServer {
 	return AuthServiceServer{
-		logger:     logger,
-		uuc:        uuc,
-		jwtManager: jwtManager,
+		logger: logger,
+		uuc:    uuc,
+		tuc:    tuc,
+		keys:   keys,
 	}
 }
 
@@ -35,7 +47,7 @@ func (a AuthServiceServer) Login(ctx context.Context, request *pb.LoginRequest)
 		return nil, err
 	}
 
-	tokens, err := a.jwtManager.GeneratePair(user.UUID.String())
+	tokens, err := a.tuc.Issue(ctx, user.UUID)
 	if err != nil {
 		return nil, err
 	}
@@ -48,14 +60,9 @@ func (a AuthServiceServer) Login(ctx context.Context, request *pb.LoginRequest)
 
 // RefreshToken refreshes a token.
 func (a AuthServiceServer) RefreshToken(ctx context.Context, request *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
-	userIDStr, err := a.jwtManager.ValidatePair(request.AccessToken, request.RefreshToken)
+	tokens, err := a.tuc.Refresh(ctx, request.AccessToken, request.RefreshToken)
 	if err != nil {
-		return nil, err
-	}
-
-	tokens, err := a.jwtManager.GeneratePair(userIDStr)
-	if err != nil {
-		return nil, err
+		return nil, tokenError(err)
 	}
 
 	return &pb.RefreshTokenResponse{
@@ -66,6 +73,34 @@ func (a AuthServiceServer) RefreshToken(ctx context.Context, request *pb.Refresh
 
 // Logout logs out a user.
 func (a AuthServiceServer) Logout(ctx context.Context, request *pb.LogoutRequest) (*emptypb.Empty, error) {
-	//TODO implement me
-	panic("implement me")
+	if err := a.tuc.Revoke(ctx, request.RefreshToken); err != nil {
+		return nil, tokenError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetPublicKeys returns the keys tokens are verified with.
+func (a AuthServiceServer) GetPublicKeys(ctx context.Context, empty *emptypb.Empty) (*pb.GetPublicKeysResponse, error) {
+	set := a.keys.JWKS()
+
+	keys := make([]*pb.PublicKey, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		keys = append(keys, mappers.JWKToPublicKeyResponse(key))
+	}
+
+	return &pb.GetPublicKeysResponse{Keys: keys}, nil
+}
+
+// tokenError maps token errors to an Unauthenticated status.
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrTokenRevoked),
+		errors.Is(err, domain.ErrTokenReused),
+		errors.Is(err, jwt.ErrInvalidToken),
+		errors.Is(err, jwt.ErrInvalidPair):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return err
+	}
 }
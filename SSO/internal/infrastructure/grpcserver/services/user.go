@@ -3,11 +3,14 @@ package services
 import (
 	pb "SSO/gen/go"
 	"SSO/internal/contracts/usecases"
+	"SSO/internal/domain"
 	"SSO/internal/infrastructure/grpcserver/interceptors"
 	"SSO/internal/utils/mappers"
 	"context"
 	"errors"
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"log/slog"
 )
@@ -103,7 +106,7 @@ func (u UserServiceServer) UpdateUser(ctx context.Context, request *pb.UpdateUse
 	userDto := mappers.UpdateUserRequestToUserDTO(request)
 	user, err := u.uuc.Update(ctx, userID, userDto)
 	if err != nil {
-		return nil, err
+		return nil, userError(err)
 	}
 
 	return mappers.UserDomainToUserResponse(user), nil
@@ -124,3 +127,11 @@ func (u UserServiceServer) DeleteUser(ctx context.Context, empty *emptypb.Empty)
 
 	return &emptypb.Empty{}, nil
 }
+
+// userError maps invalid user data to an InvalidArgument status.
+func userError(err error) error {
+	if errors.Is(err, domain.ErrInvalidProfile) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package httpserver
+
+import (
+	"SSO/internal/contracts/usecases"
+	"SSO/pkg/jwt"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// JWKSPath is the path the JSON Web Key Set is served at.
+const JWKSPath = "/.well-known/jwks.json"
+
+// Server is the interface that wraps the Serve method.
+type Server interface {
+	Serve() error
+}
+
+type server struct {
+	logger  *slog.Logger
+	address string
+	keys    *jwt.KeyRing
+	users   usecases.UserUseCases
+	srv     *http.Server
+}
+
+// NewServer returns a new instance of the Server that publishes the token verification keys
+// and the public profiles of users.
+func NewServer(logger *slog.Logger, address string, keys *jwt.KeyRing, users usecases.UserUseCases) Server {
+	return &server{
+		logger:  logger,
+		address: address,
+		keys:    keys,
+		users:   users,
+	}
+}
+
+// Serve starts the server.
+func (s *server) Serve() error {
+	s.srv = &http.Server{
+		Addr:              s.address,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	s.logger.Info("Starting http server", slog.String("address", s.address))
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+
+		<-stop
+
+		if err := s.srv.Shutdown(context.Background()); err != nil {
+			s.logger.Error("Failed to shutdown http server", slog.String("error", err.Error()))
+		}
+		s.logger.Info("Gracefully stopping http server")
+	}()
+
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error("Failed to start http server", slog.String("error", err.Error()))
+		return err
+	}
+
+	s.logger.Info("Http server stopped")
+
+	return nil
+}
+
+// routes returns the handler of the routes of the server.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+JWKSPath, s.jwks)
+	mux.HandleFunc("GET "+UsersPath, s.listProfiles)
+	mux.HandleFunc("GET "+UsersPath+"/{id}", s.getProfile)
+	return mux
+}
+
+// jwks writes the JSON Web Key Set.
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if err := json.NewEncoder(w).Encode(s.keys.JWKS()); err != nil {
+		s.logger.Error("Failed to write jwks", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,110 @@
+package httpserver
+
+import (
+	"SSO/internal/domain"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// UsersPath is the path the public profiles of users are served at.
+const UsersPath = "/users"
+
+// maxProfiles is the maximum number of profiles requested at once.
+const maxProfiles = 100
+
+// Profile is the public profile of a user, without their credentials.
+type Profile struct {
+	ID          uuid.UUID  `json:"id"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"displayName"`
+	Bio         string     `json:"bio"`
+	AvatarID    *uuid.UUID `json:"avatarId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// ProfileList is a list of public profiles.
+type ProfileList struct {
+	Users []Profile `json:"users"`
+}
+
+// profileOf returns the public profile of a user.
+func profileOf(user *domain.User) Profile {
+	return Profile{
+		ID:          user.UUID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		AvatarID:    user.AvatarID,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
+
+// getProfile writes the public profile of a user.
+func (s *server) getProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	user, err := s.users.GetByID(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get user", slog.String("error", err.Error()))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	s.writeJSON(w, profileOf(user))
+}
+
+// listProfiles writes the public profiles of the users given by the id query parameters.
+// Users that do not exist are left out.
+func (s *server) listProfiles(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()["id"]
+	if len(values) == 0 || len(values) > maxProfiles {
+		http.Error(w, "between 1 and 100 user ids are required", http.StatusBadRequest)
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "invalid user id", http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	users, err := s.users.GetByIds(r.Context(), ids)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		s.logger.Error("Failed to get users", slog.String("error", err.Error()))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	list := ProfileList{Users: make([]Profile, 0, len(users))}
+	for _, user := range users {
+		list.Users = append(list.Users, profileOf(user))
+	}
+	s.writeJSON(w, list)
+}
+
+// writeJSON writes a JSON response.
+func (s *server) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error("Failed to write response", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,136 @@
+package httpserver
+
+import (
+	"SSO/internal/contracts/usecases"
+	"SSO/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeUsers serves the users of a map, the other methods of usecases.UserUseCases are not used by the server.
+type fakeUsers struct {
+	usecases.UserUseCases
+	users map[uuid.UUID]*domain.User
+	err   error
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	user, ok := f.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeUsers) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var users []*domain.User
+	for _, id := range ids {
+		if user, ok := f.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func newTestServer(users *fakeUsers) http.Handler {
+	s := &server{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), users: users}
+	return s.routes()
+}
+
+func get(handler http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestServer_GetProfile(t *testing.T) {
+	avatarID := uuid.New()
+	user := &domain.User{UUID: uuid.New(), Username: "alice", Email: "alice@example.com", HashedPassword: "hash",
+		DisplayName: "Alice", Bio: "Hello", AvatarID: &avatarID}
+	server := newTestServer(&fakeUsers{users: map[uuid.UUID]*domain.User{user.UUID: user}})
+
+	w := get(server, UsersPath+"/"+user.UUID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	// The profile leaves the credentials of the user out
+	var profile Profile
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&profile))
+	assert.Equal(t, profileOf(user), profile)
+	assert.NotContains(t, w.Body.String(), "alice@example.com")
+	assert.NotContains(t, w.Body.String(), "hash")
+
+	assert.Equal(t, http.StatusNotFound, get(server, UsersPath+"/"+uuid.NewString()).Code)
+	assert.Equal(t, http.StatusBadRequest, get(server, UsersPath+"/alice").Code)
+}
+
+func TestServer_GetProfile_Error(t *testing.T) {
+	server := newTestServer(&fakeUsers{err: errors.New("database unavailable")})
+
+	w := get(server, UsersPath+"/"+uuid.NewString())
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "database")
+}
+
+func TestServer_ListProfiles(t *testing.T) {
+	alice := &domain.User{UUID: uuid.New(), Username: "alice"}
+	bob := &domain.User{UUID: uuid.New(), Username: "bob"}
+	server := newTestServer(&fakeUsers{users: map[uuid.UUID]*domain.User{alice.UUID: alice, bob.UUID: bob}})
+
+	// Users that do not exist are left out
+	w := get(server, UsersPath+"?id="+alice.UUID.String()+"&id="+uuid.NewString()+"&id="+bob.UUID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list ProfileList
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Equal(t, []Profile{profileOf(alice), profileOf(bob)}, list.Users)
+
+	// No user is an empty list, not null
+	w = get(server, UsersPath+"?id="+uuid.NewString())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"users":[]}`, w.Body.String())
+}
+
+func TestServer_ListProfiles_Errors(t *testing.T) {
+	tooMany := make([]string, 0, maxProfiles+1)
+	for range maxProfiles + 1 {
+		tooMany = append(tooMany, "id="+uuid.NewString())
+	}
+
+	tests := []struct {
+		name   string
+		users  *fakeUsers
+		target string
+		status int
+	}{
+		{name: "no id", users: &fakeUsers{}, target: UsersPath, status: http.StatusBadRequest},
+		{name: "too many ids", users: &fakeUsers{}, target: UsersPath + "?" + strings.Join(tooMany, "&"), status: http.StatusBadRequest},
+		{name: "invalid id", users: &fakeUsers{}, target: UsersPath + "?id=alice", status: http.StatusBadRequest},
+		{name: "repository error", users: &fakeUsers{err: errors.New("database unavailable")},
+			target: UsersPath + "?id=" + uuid.NewString(), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(newTestServer(tt.users), tt.target)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
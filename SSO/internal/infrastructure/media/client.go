@@ -0,0 +1,120 @@
+package media
+
+import (
+	"SSO/internal/domain"
+	"SSO/internal/usecases"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var _ usecases.MediaClient = &Client{}
+
+// Client is a client of the HTTP API of the Media service.
+//
+// Requests are made with the access token of the request context, so Media checks
+// the permissions of the user that made the request to SSO.
+type Client struct {
+	url    string
+	token  func(ctx context.Context) string
+	client *http.Client
+}
+
+// NewClient creates a new Client of the Media service at url.
+func NewClient(url string, token func(ctx context.Context) string, client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Client{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: client,
+	}
+}
+
+// GetFile returns the metadata of a file the user can read.
+func (c *Client) GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.MediaFile, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/files/"+id.String()+"/meta", userID)
+	if err != nil {
+		return domain.MediaFile{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden:
+		return domain.MediaFile{}, domain.ErrNotFound
+	default:
+		return domain.MediaFile{}, statusError(resp)
+	}
+
+	var file domain.MediaFile
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return domain.MediaFile{}, fmt.Errorf("failed to decode media file: %w", err)
+	}
+	return file, nil
+}
+
+// AddReference records that owner uses a file of the user.
+func (c *Client) AddReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	return c.reference(ctx, http.MethodPut, userID, fileID, owner)
+}
+
+// RemoveReference removes a reference of owner to a file of the user.
+func (c *Client) RemoveReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	return c.reference(ctx, http.MethodDelete, userID, fileID, owner)
+}
+
+func (c *Client) reference(ctx context.Context, method string, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	resp, err := c.do(ctx, method, "/files/"+fileID.String()+"/references/"+url.PathEscape(owner), userID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusForbidden:
+		return domain.ErrNotFound
+	default:
+		return statusError(resp)
+	}
+}
+
+// do sends a request to Media with the access token of ctx, acting as the user.
+func (c *Client) do(ctx context.Context, method string, path string, userID uuid.UUID) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path+"?author_id="+userID.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := c.token(ctx); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach media: %w", err)
+	}
+	return resp, nil
+}
+
+// statusError returns the error of an unexpected response of Media.
+func statusError(resp *http.Response) error {
+	var body struct {
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(data, &body) == nil && body.Code != "" {
+		return fmt.Errorf("media: unexpected status %d: %s: %s", resp.StatusCode, body.Code, body.Error)
+	}
+	return fmt.Errorf("media: unexpected status %d", resp.StatusCode)
+}
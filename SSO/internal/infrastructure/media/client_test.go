@@ -0,0 +1,58 @@
+package media
+
+import (
+	"SSO/internal/domain"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	file := domain.MediaFile{ID: uuid.New(), AuthorID: uuid.New(), ContentType: "image/png"}
+	owner := domain.AvatarOwner(file.AuthorID)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /files/{id}/meta", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, file.AuthorID.String(), r.URL.Query().Get("author_id"))
+		if r.PathValue("id") != file.ID.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(file)
+	})
+	mux.HandleFunc("PUT /files/{id}/references/{owner}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, file.AuthorID.String(), r.URL.Query().Get("author_id"))
+		if r.PathValue("owner") != owner {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /files/{id}/references/{owner}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":"internal server error","code":"internal"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", func(ctx context.Context) string { return "token" }, nil)
+	ctx := context.Background()
+
+	found, err := client.GetFile(ctx, file.AuthorID, file.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, file, found)
+
+	_, err = client.GetFile(ctx, file.AuthorID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	assert.NoError(t, client.AddReference(ctx, file.AuthorID, file.ID, owner))
+	assert.ErrorIs(t, client.AddReference(ctx, file.AuthorID, file.ID, "user:other"), domain.ErrNotFound)
+
+	err = client.RemoveReference(ctx, file.AuthorID, file.ID, owner)
+	assert.ErrorContains(t, err, "internal")
+}
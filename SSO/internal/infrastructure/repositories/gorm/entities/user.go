@@ -7,10 +7,13 @@ import (
 
 // User represents a user entity
 type User struct {
-	ID             uuid.UUID `json:"uuid" gorm:"primaryKey"`
-	Username       string    `json:"username"`
-	Email          string    `json:"email"`
-	HashedPassword string    `json:"hashed_password"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uuid.UUID  `json:"uuid" gorm:"primaryKey"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	HashedPassword string     `json:"hashed_password"`
+	DisplayName    string     `json:"display_name"`
+	Bio            string     `json:"bio"`
+	AvatarID       *uuid.UUID `json:"avatar_id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,85 @@
+package gormrepository
+
+import (
+	"SSO/internal/domain"
+	"SSO/internal/infrastructure/repositories/gorm/entities"
+	"SSO/internal/usecases"
+	"SSO/internal/utils/mappers"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log/slog"
+	"time"
+)
+
+var _ usecases.RefreshTokenRepositoryInterface = &GormRefreshTokenRepository{}
+
+// GormRefreshTokenRepository is a repository for refresh tokens in Gorm databases.
+type GormRefreshTokenRepository struct {
+	AbstractGormRepository[*domain.RefreshToken, entities.RefreshToken]
+}
+
+// NewGormRefreshTokenRepository creates a new GormRefreshTokenRepository.
+func NewGormRefreshTokenRepository(db *gorm.DB, logger *slog.Logger) *GormRefreshTokenRepository {
+	return &GormRefreshTokenRepository{
+		AbstractGormRepository: NewAbstractGormRepository[*domain.RefreshToken, entities.RefreshToken](
+			db,
+			logger,
+			mappers.RefreshTokenDomainToRefreshTokenEntity,
+			mappers.RefreshTokenEntityToRefreshTokenDomain,
+		),
+	}
+}
+
+// Rotate stores the next token and marks the token with the given ID as replaced by it.
+// It fails with domain.ErrTokenRevoked if the token was revoked or rotated concurrently.
+func (r *GormRefreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error {
+	const op = "GormRefreshTokenRepository.Rotate"
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(r.modelToEntity(next)).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		res := tx.Model(&entities.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL AND replaced_by IS NULL", id).
+			Updates(map[string]interface{}{
+				"revoked_at":  now,
+				"replaced_by": next.ID,
+				"updated_at":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrTokenRevoked
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeFamily revokes every token of the given family that is not revoked yet.
+func (r *GormRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	const op = "GormRefreshTokenRepository.RevokeFamily"
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{
+			"revoked_at": now,
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		return err
+	}
+	return nil
+}
@@ -57,3 +57,22 @@ func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (*dom
 	}
 	return r.entityToModel(&entity), nil
 }
+
+// Update updates a user.
+// Unlike the abstract repository, it also writes empty fields, so profile fields can be cleared.
+func (r *GormUserRepository) Update(ctx context.Context, user *domain.User) error {
+	const op = "GormUserRepository.Update"
+	entity := r.modelToEntity(user)
+	result := r.db.WithContext(ctx).Model(entity).Select("*").Omit("created_at").Updates(entity)
+	if err := result.Error; err != nil {
+		r.logger.Error(op, slog.Any("error", err.Error()))
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"SSO/internal/domain"
+	"SSO/pkg/jwt"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+
+	contractUseCases "SSO/internal/contracts/usecases"
+)
+
+// RefreshTokenRepositoryInterface is an interface for refresh token repositories.
+type RefreshTokenRepositoryInterface interface {
+	contractUseCases.AbstractRepositoryInterface[*domain.RefreshToken]
+	Rotate(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+// TokenManager generates and parses token pairs.
+type TokenManager interface {
+	jwt.Generator
+	jwt.Parser
+}
+
+var _ contractUseCases.TokenUseCases = &TokenUseCases{}
+
+// TokenUseCases is a use case for tokens.
+type TokenUseCases struct {
+	Repository RefreshTokenRepositoryInterface
+	manager    TokenManager
+}
+
+// NewTokenUseCases creates a new TokenUseCases.
+func NewTokenUseCases(repository RefreshTokenRepositoryInterface, manager TokenManager) *TokenUseCases {
+	return &TokenUseCases{
+		Repository: repository,
+		manager:    manager,
+	}
+}
+
+// Issue issues a pair of tokens starting a new token family.
+func (u *TokenUseCases) Issue(ctx context.Context, userID uuid.UUID) (*jwt.Pair, error) {
+	pair, err := u.manager.GeneratePair(userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := refreshTokenFromClaims(pair.RefreshClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, u.Repository.Create(ctx, token)
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens in the same family.
+// Presenting a token that was already exchanged revokes the whole family.
+func (u *TokenUseCases) Refresh(ctx context.Context, accessToken string, refreshToken string) (*jwt.Pair, error) {
+	claims, err := u.manager.ValidatePair(accessToken, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := u.getStored(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.IsRotated() {
+		if err := u.Repository.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrTokenReused
+	}
+	if current.IsRevoked() || current.IsExpired(time.Now()) {
+		return nil, domain.ErrTokenRevoked
+	}
+
+	pair, err := u.manager.GenerateFamilyPair(claims.UserID, claims.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := refreshTokenFromClaims(pair.RefreshClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.Repository.Rotate(ctx, current.ID, next); err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// Revoke revokes a refresh token. Revoking an already revoked token is a no-op.
+func (u *TokenUseCases) Revoke(ctx context.Context, refreshToken string) error {
+	claims, err := u.manager.ParseRefresh(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	token, err := u.getStored(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	if token.IsRevoked() {
+		return nil
+	}
+
+	token.Revoke(time.Now())
+	return u.Repository.Update(ctx, token)
+}
+
+// getStored returns the stored refresh token matching the claims.
+// Tokens that were never recorded are treated as revoked.
+func (u *TokenUseCases) getStored(ctx context.Context, claims *jwt.RefreshClaims) (*domain.RefreshToken, error) {
+	id, err := uuid.Parse(claims.TokenID)
+	if err != nil {
+		return nil, jwt.ErrInvalidToken
+	}
+
+	token, err := u.Repository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrTokenRevoked
+		}
+		return nil, err
+	}
+
+	if token.UserID.String() != claims.UserID || token.FamilyID.String() != claims.FamilyID {
+		return nil, jwt.ErrInvalidToken
+	}
+
+	return token, nil
+}
+
+func refreshTokenFromClaims(claims jwt.RefreshClaims) (*domain.RefreshToken, error) {
+	id, err := uuid.Parse(claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	familyID, err := uuid.Parse(claims.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RefreshToken{
+		ID:        id,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
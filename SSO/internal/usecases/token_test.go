@@ -0,0 +1,232 @@
+package usecases
+
+import (
+	"SSO/internal/domain"
+	"SSO/pkg/jwt"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRefreshTokenRepository keeps refresh tokens in a map and rotates them like the Gorm repository.
+type fakeRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]domain.RefreshToken
+}
+
+func newFakeRefreshTokenRepository() *fakeRefreshTokenRepository {
+	return &fakeRefreshTokenRepository{tokens: make(map[uuid.UUID]domain.RefreshToken)}
+}
+
+func (r *fakeRefreshTokenRepository) Create(ctx context.Context, entity *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[entity.ID]; ok {
+		return domain.ErrAlreadyExists
+	}
+	r.tokens[entity.ID] = *entity
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) Update(ctx context.Context, entity *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tokens[entity.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.tokens[entity.ID] = *entity
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tokens, id)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &token, nil
+}
+
+func (r *fakeRefreshTokenRepository) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	for _, id := range ids {
+		if token, err := r.GetByID(ctx, id); err == nil {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeRefreshTokenRepository) GetAll(ctx context.Context, limit int, offset int) ([]*domain.RefreshToken, error) {
+	return nil, nil
+}
+
+func (r *fakeRefreshTokenRepository) Rotate(ctx context.Context, id uuid.UUID, next *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.tokens[id]
+	if !ok || current.IsRevoked() || current.IsRotated() {
+		return domain.ErrTokenRevoked
+	}
+	current.Revoke(time.Now())
+	current.ReplacedByID = &next.ID
+	r.tokens[id] = current
+	r.tokens[next.ID] = *next
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, token := range r.tokens {
+		if token.FamilyID == familyID {
+			token.Revoke(time.Now())
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func newTestTokenUseCases(t *testing.T) (*TokenUseCases, *fakeRefreshTokenRepository) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	keys, err := jwt.NewKeyRing(jwt.NewKey(key, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	manager := jwt.NewManager(jwt.ManagerOptions{
+		Keys:       keys,
+		AccessTTL:  -time.Minute, // access tokens are already expired when they are refreshed
+		RefreshTTL: time.Hour,
+	})
+	repository := newFakeRefreshTokenRepository()
+	return NewTokenUseCases(repository, manager), repository
+}
+
+func TestTokenUseCases_Refresh(t *testing.T) {
+	uc, repository := newTestTokenUseCases(t)
+	ctx := context.Background()
+
+	first, err := uc.Issue(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, err := uc.Refresh(ctx, first.AccessToken, first.RefreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, first.RefreshClaims.FamilyID, second.RefreshClaims.FamilyID)
+	assert.NotEqual(t, first.RefreshClaims.TokenID, second.RefreshClaims.TokenID)
+
+	rotated, err := repository.GetByID(ctx, uuid.MustParse(first.RefreshClaims.TokenID))
+	assert.NoError(t, err)
+	if assert.True(t, rotated.IsRotated()) {
+		assert.Equal(t, second.RefreshClaims.TokenID, rotated.ReplacedByID.String())
+	}
+
+	third, err := uc.Refresh(ctx, second.AccessToken, second.RefreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, first.RefreshClaims.FamilyID, third.RefreshClaims.FamilyID)
+}
+
+func TestTokenUseCases_Refresh_Reuse(t *testing.T) {
+	uc, repository := newTestTokenUseCases(t)
+	ctx := context.Background()
+
+	first, err := uc.Issue(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := uc.Refresh(ctx, first.AccessToken, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = uc.Refresh(ctx, first.AccessToken, first.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenReused)
+
+	latest, err := repository.GetByID(ctx, uuid.MustParse(second.RefreshClaims.TokenID))
+	assert.NoError(t, err)
+	assert.True(t, latest.IsRevoked())
+
+	_, err = uc.Refresh(ctx, second.AccessToken, second.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+}
+
+func TestTokenUseCases_Refresh_Invalid(t *testing.T) {
+	uc, _ := newTestTokenUseCases(t)
+	ctx := context.Background()
+
+	first, err := uc.Issue(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	other, err := uc.Issue(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = uc.Refresh(ctx, other.AccessToken, first.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidPair)
+
+	_, err = uc.Refresh(ctx, first.AccessToken, "not a token")
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+}
+
+func TestTokenUseCases_Refresh_Unknown(t *testing.T) {
+	uc, repository := newTestTokenUseCases(t)
+	ctx := context.Background()
+
+	pair, err := uc.Issue(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_ = repository.Delete(ctx, uuid.MustParse(pair.RefreshClaims.TokenID))
+
+	_, err = uc.Refresh(ctx, pair.AccessToken, pair.RefreshToken)
+
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+}
+
+func TestTokenUseCases_Revoke(t *testing.T) {
+	uc, repository := newTestTokenUseCases(t)
+	ctx := context.Background()
+
+	pair, err := uc.Issue(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assert.NoError(t, uc.Revoke(ctx, pair.RefreshToken))
+
+	token, err := repository.GetByID(ctx, uuid.MustParse(pair.RefreshClaims.TokenID))
+	assert.NoError(t, err)
+	assert.True(t, token.IsRevoked())
+	revokedAt := *token.RevokedAt
+
+	assert.NoError(t, uc.Revoke(ctx, pair.RefreshToken))
+	token, _ = repository.GetByID(ctx, uuid.MustParse(pair.RefreshClaims.TokenID))
+	assert.Equal(t, revokedAt, *token.RevokedAt)
+
+	_, err = uc.Refresh(ctx, pair.AccessToken, pair.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+
+	assert.ErrorIs(t, uc.Revoke(ctx, pair.AccessToken), jwt.ErrInvalidToken)
+}
@@ -3,8 +3,11 @@ package usecases
 import (
 	"SSO/internal/domain"
 	"context"
-	"crypto/sha256"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"log/slog"
+	"strings"
 
 	contractUseCases "SSO/internal/contracts/usecases"
 )
@@ -16,18 +19,41 @@ type UserRepositoryInterface interface {
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 }
 
+// PasswordHasher hashes and verifies passwords.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+// MediaClient is a client of the Media service, which stores the avatars of the users.
+type MediaClient interface {
+	// GetFile returns the metadata of a file of the user, or domain.ErrNotFound.
+	GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.MediaFile, error)
+	// AddReference records that owner uses a file of the user, so Media keeps it.
+	AddReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error
+	// RemoveReference removes a reference of owner to a file of the user.
+	RemoveReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error
+}
+
 var _ contractUseCases.UserUseCases = &UserUseCases{}
 
 // UserUseCases is a use case for users.
 type UserUseCases struct {
 	Repository UserRepositoryInterface
+	Media      MediaClient // avatars are disabled if nil
+	hasher     PasswordHasher
+	logger     *slog.Logger
 	contractUseCases.AbstractUseCases[*domain.User]
 }
 
 // NewUserUseCases creates a new UserUseCases.
-func NewUserUseCases(repository UserRepositoryInterface) *UserUseCases {
+func NewUserUseCases(repository UserRepositoryInterface, hasher PasswordHasher, media MediaClient, logger *slog.Logger) *UserUseCases {
 	return &UserUseCases{
 		Repository:       repository,
+		Media:            media,
+		hasher:           hasher,
+		logger:           logger,
 		AbstractUseCases: contractUseCases.NewAbstractUseCase[*domain.User](repository),
 	}
 }
@@ -51,24 +77,54 @@ func (u *UserUseCases) Login(ctx context.Context, email string, password string)
 	if user == nil {
 		return nil, domain.ErrNotFound
 	}
-	if user.HashedPassword != hashPassword(password) {
+	ok, err := u.hasher.Verify(password, user.HashedPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, domain.ErrInvalidPassword
 	}
+	if u.hasher.NeedsRehash(user.HashedPassword) {
+		u.rehash(ctx, user, password)
+	}
 	return user, nil
 }
 
+// rehash upgrades the stored hash of a user to the current algorithm and parameters.
+// Failures do not fail the login: the old hash stays valid, so the upgrade is retried on the next login.
+func (u *UserUseCases) rehash(ctx context.Context, user *domain.User, password string) {
+	hashed, err := u.hasher.Hash(password)
+	if err != nil {
+		u.logger.Error("Failed to rehash password", slog.String("user_id", user.UUID.String()), slog.String("error", err.Error()))
+		return
+	}
+	upgraded := *user
+	upgraded.HashedPassword = hashed
+	if err := u.Repository.Update(ctx, &upgraded); err != nil {
+		u.logger.Error("Failed to store rehashed password", slog.String("user_id", user.UUID.String()), slog.String("error", err.Error()))
+		return
+	}
+	user.HashedPassword = hashed
+}
+
 // Create creates a new user.
 func (u *UserUseCases) Create(ctx context.Context, dto *contractUseCases.CreateUserDTO) (*domain.User, error) {
+	hashed, err := u.hasher.Hash(dto.Password)
+	if err != nil {
+		return nil, err
+	}
 	user := &domain.User{
 		UUID:           uuid.New(),
 		Username:       dto.Username,
 		Email:          dto.Email,
-		HashedPassword: hashPassword(dto.Password),
+		HashedPassword: hashed,
 	}
 	return user, u.Repository.Create(ctx, user)
 }
 
-// Update updates a user.
+// Update updates the credentials and the profile of a user, fields of dto that are not set are left unchanged.
+//
+// A new avatar must be an image of the user in Media, which is told that the user uses it instead of the previous one.
 func (u *UserUseCases) Update(ctx context.Context, ID uuid.UUID, dto *contractUseCases.UpdateUserDTO) (*domain.User, error) {
 	user, err := u.Repository.GetByID(ctx, ID)
 	if err != nil {
@@ -77,17 +133,93 @@ func (u *UserUseCases) Update(ctx context.Context, ID uuid.UUID, dto *contractUs
 	if user == nil {
 		return nil, domain.ErrNotFound
 	}
-	switch {
-	case dto.Username != "":
+	if dto.Username != "" {
 		user.Username = dto.Username
-	case dto.Email != "":
+	}
+	if dto.Email != "" {
 		user.Email = dto.Email
-	case dto.Password != "":
-		user.HashedPassword = hashPassword(dto.Password)
 	}
-	return user, u.Repository.Update(ctx, user)
+	if dto.Password != "" {
+		user.HashedPassword, err = u.hasher.Hash(dto.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+	previousAvatarID := user.AvatarID
+	if err := applyProfile(user, dto); err != nil {
+		return nil, err
+	}
+
+	avatarChanged := !sameID(previousAvatarID, user.AvatarID)
+	if avatarChanged && user.AvatarID != nil {
+		if err := u.useAvatar(ctx, user.UUID, *user.AvatarID); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.Repository.Update(ctx, user); err != nil {
+		if avatarChanged && user.AvatarID != nil {
+			_ = u.Media.RemoveReference(ctx, user.UUID, *user.AvatarID, domain.AvatarOwner(user.UUID))
+		}
+		return nil, err
+	}
+
+	// The previous avatar is released once the user does not point to it anymore,
+	// a failure leaves a stale reference, which only keeps the file in Media.
+	if avatarChanged && previousAvatarID != nil && u.Media != nil {
+		_ = u.Media.RemoveReference(ctx, user.UUID, *previousAvatarID, domain.AvatarOwner(user.UUID))
+	}
+	return user, nil
 }
 
-func hashPassword(password string) [32]byte {
-	return sha256.Sum256([]byte(password))
+// useAvatar checks that a file is an image of the user and adds the reference of the user to it.
+func (u *UserUseCases) useAvatar(ctx context.Context, userID uuid.UUID, avatarID uuid.UUID) error {
+	if u.Media == nil {
+		return fmt.Errorf("%w: avatars are disabled", domain.ErrInvalidProfile)
+	}
+
+	file, err := u.Media.GetFile(ctx, userID, avatarID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: avatar not found", domain.ErrInvalidProfile)
+	}
+	if err != nil {
+		return err
+	}
+	if file.AuthorID != userID {
+		return fmt.Errorf("%w: avatar must be a file of the user", domain.ErrInvalidProfile)
+	}
+	if !file.IsImage() {
+		return fmt.Errorf("%w: avatar must be an image", domain.ErrInvalidProfile)
+	}
+
+	return u.Media.AddReference(ctx, userID, avatarID, domain.AvatarOwner(userID))
+}
+
+// sameID reports whether two optional IDs are equal.
+func sameID(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// applyProfile sets the profile fields of dto on the user and validates the profile.
+func applyProfile(user *domain.User, dto *contractUseCases.UpdateUserDTO) error {
+	if dto.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*dto.DisplayName)
+	}
+	if dto.Bio != nil {
+		user.Bio = strings.TrimSpace(*dto.Bio)
+	}
+	if dto.AvatarID != nil {
+		if *dto.AvatarID == "" {
+			user.AvatarID = nil
+		} else {
+			avatarID, err := uuid.Parse(*dto.AvatarID)
+			if err != nil {
+				return fmt.Errorf("%w: invalid avatar id", domain.ErrInvalidProfile)
+			}
+			user.AvatarID = &avatarID
+		}
+	}
+	return user.ValidateProfile()
 }
@@ -0,0 +1,396 @@
+package usecases
+
+import (
+	contractUseCases "SSO/internal/contracts/usecases"
+	"SSO/internal/domain"
+	"SSO/pkg/password"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// fakeUserRepository keeps users in a map and records the updates, it fails updates if err is set.
+type fakeUserRepository struct {
+	users   map[uuid.UUID]domain.User
+	updates []domain.User
+	err     error
+}
+
+func newFakeUserRepository(users ...domain.User) *fakeUserRepository {
+	r := &fakeUserRepository{users: make(map[uuid.UUID]domain.User)}
+	for _, user := range users {
+		r.users[user.UUID] = user
+	}
+	return r
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, entity *domain.User) error {
+	r.users[entity.UUID] = *entity
+	return nil
+}
+
+func (r *fakeUserRepository) Update(ctx context.Context, entity *domain.User) error {
+	r.updates = append(r.updates, *entity)
+	if r.err != nil {
+		return r.err
+	}
+	r.users[entity.UUID] = *entity
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *fakeUserRepository) GetByIds(ctx context.Context, ids []uuid.UUID) ([]*domain.User, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetAll(ctx context.Context, limit int, offset int) ([]*domain.User, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+// prefixHasher hashes passwords by prefixing them.
+type prefixHasher struct{}
+
+func (prefixHasher) Hash(password string) (string, error) { return "hashed:" + password, nil }
+
+func (prefixHasher) Verify(password string, encoded string) (bool, error) {
+	return encoded == "hashed:"+password, nil
+}
+
+func (prefixHasher) NeedsRehash(encoded string) bool { return false }
+
+// fakeMedia serves the files of a map and records the references to them by file.
+type fakeMedia struct {
+	files map[uuid.UUID]domain.MediaFile
+	refs  map[uuid.UUID][]string
+}
+
+func newFakeMedia(files ...domain.MediaFile) *fakeMedia {
+	m := &fakeMedia{files: make(map[uuid.UUID]domain.MediaFile), refs: make(map[uuid.UUID][]string)}
+	for _, file := range files {
+		m.files[file.ID] = file
+	}
+	return m
+}
+
+func (m *fakeMedia) GetFile(ctx context.Context, userID uuid.UUID, id uuid.UUID) (domain.MediaFile, error) {
+	file, ok := m.files[id]
+	if !ok {
+		return domain.MediaFile{}, domain.ErrNotFound
+	}
+	return file, nil
+}
+
+func (m *fakeMedia) AddReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	m.refs[fileID] = append(m.refs[fileID], owner)
+	return nil
+}
+
+func (m *fakeMedia) RemoveReference(ctx context.Context, userID uuid.UUID, fileID uuid.UUID, owner string) error {
+	refs := m.refs[fileID]
+	for i, ref := range refs {
+		if ref == owner {
+			m.refs[fileID] = append(refs[:i], refs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// testParams are cheap argon2id parameters for tests.
+var testParams = password.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestUserUseCases_Login_UpgradesLegacyHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret"))
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "sha-256", hash: hex.EncodeToString(sum[:])},
+		{name: "bcrypt", hash: string(bcryptHash)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := domain.User{UUID: uuid.New(), Email: "alice@example.com", HashedPassword: tt.hash}
+			repository := newFakeUserRepository(user)
+			uuc := NewUserUseCases(repository, password.NewHasher(testParams), nil, discardLogger)
+
+			_, err := uuc.Login(context.Background(), user.Email, "wrong")
+			assert.ErrorIs(t, err, domain.ErrInvalidPassword)
+			assert.Empty(t, repository.updates)
+
+			// The legacy hash verifies and is replaced by an argon2id hash of the password
+			logged, err := uuc.Login(context.Background(), user.Email, "secret")
+			require.NoError(t, err)
+			require.Len(t, repository.updates, 1)
+			stored := repository.updates[0].HashedPassword
+			assert.True(t, strings.HasPrefix(stored, "$argon2id$"), stored)
+			assert.Equal(t, stored, logged.HashedPassword)
+			ok, err := password.NewHasher(testParams).Verify("secret", stored)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			// The upgraded hash is not rehashed again
+			_, err = uuc.Login(context.Background(), user.Email, "secret")
+			require.NoError(t, err)
+			assert.Len(t, repository.updates, 1)
+		})
+	}
+}
+
+func TestUserUseCases_Login_CurrentHash(t *testing.T) {
+	hasher := password.NewHasher(testParams)
+	hashed, err := hasher.Hash("secret")
+	require.NoError(t, err)
+	user := domain.User{UUID: uuid.New(), Email: "alice@example.com", HashedPassword: hashed}
+	repository := newFakeUserRepository(user)
+	uuc := NewUserUseCases(repository, hasher, nil, discardLogger)
+
+	logged, err := uuc.Login(context.Background(), user.Email, "secret")
+
+	require.NoError(t, err)
+	assert.Equal(t, hashed, logged.HashedPassword)
+	assert.Empty(t, repository.updates)
+}
+
+func TestUserUseCases_Login_UpgradeFails(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret"))
+	user := domain.User{UUID: uuid.New(), Email: "alice@example.com", HashedPassword: hex.EncodeToString(sum[:])}
+	repository := newFakeUserRepository(user)
+	repository.err = errors.New("database unavailable")
+	uuc := NewUserUseCases(repository, password.NewHasher(testParams), nil, discardLogger)
+
+	// The login succeeds with the legacy hash, which stays stored
+	logged, err := uuc.Login(context.Background(), user.Email, "secret")
+
+	require.NoError(t, err)
+	assert.Equal(t, user.HashedPassword, logged.HashedPassword)
+	assert.Len(t, repository.updates, 1)
+}
+
+func TestUserUseCases_Update(t *testing.T) {
+	user := domain.User{UUID: uuid.New(), Username: "alice", Email: "alice@example.com", HashedPassword: "hashed:old",
+		DisplayName: "Alice", Bio: "Hello"}
+	repository := newFakeUserRepository(user)
+	uuc := NewUserUseCases(repository, prefixHasher{}, nil, discardLogger)
+
+	// All the fields set in the request are updated at once
+	updated, err := uuc.Update(context.Background(), user.UUID, &contractUseCases.UpdateUserDTO{
+		Username:    "alice2",
+		Email:       "alice2@example.com",
+		Password:    "new",
+		DisplayName: ptr(" Alice Liddell "),
+		Bio:         ptr(""),
+	})
+	require.NoError(t, err)
+
+	want := user
+	want.Username = "alice2"
+	want.Email = "alice2@example.com"
+	want.HashedPassword = "hashed:new"
+	want.DisplayName = "Alice Liddell"
+	want.Bio = ""
+	assert.Equal(t, &want, updated)
+	assert.Equal(t, want, repository.users[user.UUID])
+
+	// Fields that are not set are left unchanged
+	updated, err = uuc.Update(context.Background(), user.UUID, &contractUseCases.UpdateUserDTO{Bio: ptr("Curious")})
+	require.NoError(t, err)
+	want.Bio = "Curious"
+	assert.Equal(t, &want, updated)
+
+	_, err = uuc.Update(context.Background(), uuid.New(), &contractUseCases.UpdateUserDTO{Username: "bob"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUserUseCases_Update_InvalidProfile(t *testing.T) {
+	user := domain.User{UUID: uuid.New(), Username: "alice", DisplayName: "Alice"}
+	repository := newFakeUserRepository(user)
+	uuc := NewUserUseCases(repository, prefixHasher{}, nil, discardLogger)
+
+	// Nothing is saved when the profile is invalid, not even the other fields
+	_, err := uuc.Update(context.Background(), user.UUID, &contractUseCases.UpdateUserDTO{
+		Username:    "alice2",
+		DisplayName: ptr(strings.Repeat("a", domain.MaxDisplayNameLength+1)),
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+	assert.Equal(t, user, repository.users[user.UUID])
+}
+
+func TestApplyProfile(t *testing.T) {
+	avatarID := uuid.New()
+
+	tests := []struct {
+		name string
+		dto  contractUseCases.UpdateUserDTO
+		want domain.User
+		err  error
+	}{
+		{name: "nothing set", want: domain.User{DisplayName: "Alice", Bio: "Hello", AvatarID: &avatarID}},
+		{name: "trimmed", dto: contractUseCases.UpdateUserDTO{DisplayName: ptr("  Bob "), Bio: ptr("\tHi\n")},
+			want: domain.User{DisplayName: "Bob", Bio: "Hi", AvatarID: &avatarID}},
+		{name: "cleared", dto: contractUseCases.UpdateUserDTO{DisplayName: ptr(""), Bio: ptr(""), AvatarID: ptr("")},
+			want: domain.User{}},
+		{name: "new avatar", dto: contractUseCases.UpdateUserDTO{AvatarID: ptr("6ba7b810-9dad-11d1-80b4-00c04fd430c8")},
+			want: domain.User{DisplayName: "Alice", Bio: "Hello", AvatarID: ptr(uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))}},
+		{name: "invalid avatar", dto: contractUseCases.UpdateUserDTO{AvatarID: ptr("avatar.png")}, err: domain.ErrInvalidProfile},
+		{name: "bio too long", dto: contractUseCases.UpdateUserDTO{Bio: ptr(strings.Repeat("a", domain.MaxBioLength+1))},
+			err: domain.ErrInvalidProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{DisplayName: "Alice", Bio: "Hello", AvatarID: &avatarID}
+
+			err := applyProfile(user, &tt.dto)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &tt.want, user)
+		})
+	}
+}
+
+func TestUserUseCases_Update_Avatar(t *testing.T) {
+	userID := uuid.New()
+	owner := domain.AvatarOwner(userID)
+	first := domain.MediaFile{ID: uuid.New(), AuthorID: userID, ContentType: "image/png"}
+	second := domain.MediaFile{ID: uuid.New(), AuthorID: userID, ContentType: "image/jpeg"}
+	media := newFakeMedia(first, second)
+	repository := newFakeUserRepository(domain.User{UUID: userID, Username: "alice"})
+	uuc := NewUserUseCases(repository, prefixHasher{}, media, discardLogger)
+	ctx := context.Background()
+
+	updated, err := uuc.Update(ctx, userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr(first.ID.String())})
+	require.NoError(t, err)
+	assert.Equal(t, &first.ID, updated.AvatarID)
+	assert.Equal(t, []string{owner}, media.refs[first.ID])
+
+	// Setting the same avatar again does not add another reference
+	_, err = uuc.Update(ctx, userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr(first.ID.String()), Bio: ptr("Hello")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{owner}, media.refs[first.ID])
+
+	// The previous avatar is released when it changes
+	_, err = uuc.Update(ctx, userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr(second.ID.String())})
+	require.NoError(t, err)
+	assert.Empty(t, media.refs[first.ID])
+	assert.Equal(t, []string{owner}, media.refs[second.ID])
+
+	// and when it is removed
+	updated, err = uuc.Update(ctx, userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr("")})
+	require.NoError(t, err)
+	assert.Nil(t, updated.AvatarID)
+	assert.Empty(t, media.refs[second.ID])
+}
+
+func TestUserUseCases_Update_InvalidAvatar(t *testing.T) {
+	userID := uuid.New()
+	current := domain.MediaFile{ID: uuid.New(), AuthorID: userID, ContentType: "image/png"}
+	text := domain.MediaFile{ID: uuid.New(), AuthorID: userID, ContentType: "text/plain; charset=utf-8"}
+	other := domain.MediaFile{ID: uuid.New(), AuthorID: uuid.New(), ContentType: "image/png"}
+	media := newFakeMedia(current, text, other)
+	media.refs[current.ID] = []string{domain.AvatarOwner(userID)}
+
+	tests := []struct {
+		name     string
+		avatarID uuid.UUID
+		err      string
+	}{
+		{name: "not found", avatarID: uuid.New(), err: "avatar not found"},
+		{name: "file of another user", avatarID: other.ID, err: "avatar must be a file of the user"},
+		{name: "not an image", avatarID: text.ID, err: "avatar must be an image"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := domain.User{UUID: userID, Username: "alice", AvatarID: &current.ID}
+			repository := newFakeUserRepository(user)
+			uuc := NewUserUseCases(repository, prefixHasher{}, media, discardLogger)
+
+			_, err := uuc.Update(context.Background(), userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr(tt.avatarID.String())})
+
+			assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+			assert.ErrorContains(t, err, tt.err)
+			assert.Equal(t, user, repository.users[userID])
+			assert.Empty(t, media.refs[tt.avatarID])
+			assert.Equal(t, []string{domain.AvatarOwner(userID)}, media.refs[current.ID])
+		})
+	}
+}
+
+func TestUserUseCases_Update_AvatarNotSaved(t *testing.T) {
+	userID := uuid.New()
+	avatar := domain.MediaFile{ID: uuid.New(), AuthorID: userID, ContentType: "image/png"}
+	media := newFakeMedia(avatar)
+	repository := newFakeUserRepository(domain.User{UUID: userID, Username: "alice"})
+	failure := errors.New("database unavailable")
+	repository.err = failure
+	uuc := NewUserUseCases(repository, prefixHasher{}, media, discardLogger)
+
+	// The reference to the new avatar is removed when the user is not saved
+	_, err := uuc.Update(context.Background(), userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr(avatar.ID.String())})
+	assert.Equal(t, failure, err)
+	assert.Empty(t, media.refs[avatar.ID])
+}
+
+func TestUserUseCases_Update_AvatarsDisabled(t *testing.T) {
+	userID := uuid.New()
+	avatarID := uuid.New()
+	repository := newFakeUserRepository(domain.User{UUID: userID, Username: "alice", AvatarID: &avatarID})
+	uuc := NewUserUseCases(repository, prefixHasher{}, nil, discardLogger)
+
+	_, err := uuc.Update(context.Background(), userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr(uuid.NewString())})
+	assert.ErrorIs(t, err, domain.ErrInvalidProfile)
+
+	// Avatars can still be removed
+	updated, err := uuc.Update(context.Background(), userID, &contractUseCases.UpdateUserDTO{AvatarID: ptr("")})
+	require.NoError(t, err)
+	assert.Nil(t, updated.AvatarID)
+}
@@ -0,0 +1,18 @@
+package mappers
+
+import (
+	pb "SSO/gen/go"
+	"SSO/pkg/jwt"
+)
+
+// JWKToPublicKeyResponse maps a JSON Web Key to a public key response.
+func JWKToPublicKeyResponse(key jwt.JWK) *pb.PublicKey {
+	return &pb.PublicKey{
+		Kid: key.Kid,
+		Kty: key.Kty,
+		Alg: key.Alg,
+		Use: key.Use,
+		N:   key.N,
+		E:   key.E,
+	}
+}
@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"SSO/internal/domain"
+	"SSO/internal/infrastructure/repositories/gorm/entities"
+)
+
+// RefreshTokenDomainToRefreshTokenEntity maps a domain refresh token to a refresh token entity.
+func RefreshTokenDomainToRefreshTokenEntity(domainToken *domain.RefreshToken) *entities.RefreshToken {
+	return &entities.RefreshToken{
+		ID:         domainToken.ID,
+		UserID:     domainToken.UserID,
+		FamilyID:   domainToken.FamilyID,
+		ExpiresAt:  domainToken.ExpiresAt,
+		RevokedAt:  domainToken.RevokedAt,
+		ReplacedBy: domainToken.ReplacedByID,
+		CreatedAt:  domainToken.CreatedAt,
+		UpdatedAt:  domainToken.UpdatedAt,
+	}
+}
+
+// RefreshTokenEntityToRefreshTokenDomain maps a refresh token entity to a domain refresh token.
+func RefreshTokenEntityToRefreshTokenDomain(entity *entities.RefreshToken) *domain.RefreshToken {
+	return &domain.RefreshToken{
+		ID:           entity.ID,
+		UserID:       entity.UserID,
+		FamilyID:     entity.FamilyID,
+		ExpiresAt:    entity.ExpiresAt,
+		RevokedAt:    entity.RevokedAt,
+		ReplacedByID: entity.ReplacedBy,
+		CreatedAt:    entity.CreatedAt,
+		UpdatedAt:    entity.UpdatedAt,
+	}
+}
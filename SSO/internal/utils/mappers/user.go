@@ -5,18 +5,21 @@ import (
 	"SSO/internal/contracts/usecases"
 	"SSO/internal/domain"
 	"SSO/internal/infrastructure/repositories/gorm/entities"
-	"encoding/hex"
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // UserDomainToUserResponse maps a domain user to a user response.
 func UserDomainToUserResponse(domainUser *domain.User) *pb.User {
 	return &pb.User{
-		Id:        domainUser.UUID.String(),
-		Username:  domainUser.Username,
-		Email:     domainUser.Email,
-		CreatedAt: timestamppb.New(domainUser.CreatedAt),
-		UpdatedAt: timestamppb.New(domainUser.UpdatedAt),
+		Id:          domainUser.UUID.String(),
+		Username:    domainUser.Username,
+		Email:       domainUser.Email,
+		DisplayName: domainUser.DisplayName,
+		Bio:         domainUser.Bio,
+		AvatarId:    avatarIDToString(domainUser.AvatarID),
+		CreatedAt:   timestamppb.New(domainUser.CreatedAt),
+		UpdatedAt:   timestamppb.New(domainUser.UpdatedAt),
 	}
 }
 
@@ -35,7 +38,10 @@ func UserDomainToUserEntity(domainUser *domain.User) *entities.User {
 		ID:             domainUser.UUID,
 		Username:       domainUser.Username,
 		Email:          domainUser.Email,
-		HashedPassword: hex.EncodeToString(domainUser.HashedPassword[:]),
+		HashedPassword: domainUser.HashedPassword,
+		DisplayName:    domainUser.DisplayName,
+		Bio:            domainUser.Bio,
+		AvatarID:       domainUser.AvatarID,
 		CreatedAt:      domainUser.CreatedAt,
 		UpdatedAt:      domainUser.UpdatedAt,
 	}
@@ -43,16 +49,14 @@ func UserDomainToUserEntity(domainUser *domain.User) *entities.User {
 
 // UserEntityToUserDomain maps a user entity to a domain user.
 func UserEntityToUserDomain(entity *entities.User) *domain.User {
-	pass, err := hex.DecodeString(entity.HashedPassword)
-	if err != nil {
-		panic(err)
-	}
-
 	return &domain.User{
 		UUID:           entity.ID,
 		Username:       entity.Username,
 		Email:          entity.Email,
-		HashedPassword: [32]byte(pass),
+		HashedPassword: entity.HashedPassword,
+		DisplayName:    entity.DisplayName,
+		Bio:            entity.Bio,
+		AvatarID:       entity.AvatarID,
 		CreatedAt:      entity.CreatedAt,
 		UpdatedAt:      entity.UpdatedAt,
 	}
@@ -61,8 +65,19 @@ func UserEntityToUserDomain(entity *entities.User) *domain.User {
 // UpdateUserRequestToUserDTO maps an update user request to an update user DTO.
 func UpdateUserRequestToUserDTO(request *pb.UpdateUserRequest) *usecases.UpdateUserDTO {
 	return &usecases.UpdateUserDTO{
-		Username: request.Username,
-		Email:    request.Email,
-		Password: request.Password,
+		Username:    request.Username,
+		Email:       request.Email,
+		Password:    request.Password,
+		DisplayName: request.DisplayName,
+		Bio:         request.Bio,
+		AvatarID:    request.AvatarId,
+	}
+}
+
+// avatarIDToString returns the ID of an avatar, empty if there is none.
+func avatarIDToString(avatarID *uuid.UUID) string {
+	if avatarID == nil {
+		return ""
 	}
+	return avatarID.String()
 }
@@ -0,0 +1,15 @@
+CREATE TABLE refresh_tokens
+(
+    id          UUID PRIMARY KEY,                                               -- Token ID, the jti claim
+    user_id     UUID      NOT NULL REFERENCES users (id) ON DELETE CASCADE,     -- Owner of the token
+    family_id   UUID      NOT NULL,                                             -- Tokens rotated from the same login
+    expires_at  TIMESTAMP NOT NULL,                                             -- Expiration of the token
+    revoked_at  TIMESTAMP,                                                      -- Set when the token is revoked or rotated
+    replaced_by UUID,                                                           -- Token issued in exchange for this one
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),                               -- Timestamp for record creation
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()                                -- Timestamp for record update
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
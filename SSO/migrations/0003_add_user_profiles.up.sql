@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(64) NOT NULL DEFAULT '',               -- Name shown instead of the username, empty if not set
+    ADD COLUMN bio          TEXT        NOT NULL DEFAULT '',               -- Short description of the user
+    ADD COLUMN avatar_id    UUID;                                          -- Avatar file in the Media service
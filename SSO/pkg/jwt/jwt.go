@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"os"
 	"time"
 )
@@ -12,12 +13,14 @@ import (
 // Parser is a JWT token reader
 type Parser interface {
 	Parse(tokenString string) (*jwt.Token, error)
-	ValidatePair(accessToken string, refreshToken string) (string, error)
+	ValidatePair(accessToken string, refreshToken string) (*RefreshClaims, error)
+	ParseRefresh(refreshToken string) (*RefreshClaims, error)
 }
 
 // Generator is a JWT token writer
 type Generator interface {
 	GeneratePair(userGUID string) (*Pair, error)
+	GenerateFamilyPair(userGUID string, familyID string) (*Pair, error)
 }
 
 // Errors
@@ -28,16 +31,29 @@ var (
 
 // Pair is a pair of access and refresh tokens
 type Pair struct {
-	AccessToken  string
-	RefreshToken string
+	AccessToken   string
+	RefreshToken  string
+	RefreshClaims RefreshClaims
 }
 
+// RefreshClaims are the claims of a refresh token that are needed to track it
+type RefreshClaims struct {
+	UserID    string
+	TokenID   string
+	FamilyID  string
+	ExpiresAt time.Time
+}
+
+// RoleAdmin is the role of users that may act on behalf of other users
+const RoleAdmin = "admin"
+
 // ManagerOptions is a set of options for the Manager
 type ManagerOptions struct {
-	PublicKey  *rsa.PublicKey
-	PrivateKey *rsa.PrivateKey
+	Keys       *KeyRing
 	AccessTTL  time.Duration
 	RefreshTTL time.Duration
+	// Roles are put in the "roles" claim of the access tokens of the listed users
+	Roles map[string][]string
 }
 
 // Manager is a JWT token generator and parser
@@ -52,29 +68,43 @@ func NewManager(options ManagerOptions) *Manager {
 	}
 }
 
-// GeneratePair generates a pair of access and refresh tokens
+// GeneratePair generates a pair of access and refresh tokens starting a new token family
 func (m *Manager) GeneratePair(userGUID string) (*Pair, error) {
+	return m.GenerateFamilyPair(userGUID, uuid.NewString())
+}
+
+// GenerateFamilyPair generates a pair of access and refresh tokens in the given token family
+func (m *Manager) GenerateFamilyPair(userGUID string, familyID string) (*Pair, error) {
 	iat := time.Now()
+	refreshID := uuid.NewString()
+	refreshExp := iat.Add(m.Options.RefreshTTL)
 
 	accessClaims := jwt.MapClaims{
 		"sub": userGUID,
 		"exp": iat.Add(m.Options.AccessTTL).Unix(),
 		"iat": iat.UnixNano(),
 	}
+	if roles := m.Options.Roles[userGUID]; len(roles) > 0 {
+		accessClaims["roles"] = roles
+	}
 
 	refreshClaims := jwt.MapClaims{
 		"sub":  userGUID,
-		"exp":  iat.Add(m.Options.RefreshTTL).Unix(),
+		"exp":  refreshExp.Unix(),
 		"iat":  iat.UnixNano(),
+		"jti":  refreshID,
+		"fam":  familyID,
 		"type": "refresh",
 	}
 
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, accessClaims).SignedString(m.Options.PrivateKey)
+	key := m.Options.Keys.SigningKey()
+
+	accessToken, err := sign(accessClaims, key)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, refreshClaims).SignedString(m.Options.PrivateKey)
+	refreshToken, err := sign(refreshClaims, key)
 	if err != nil {
 		return nil, err
 	}
@@ -82,48 +112,114 @@ func (m *Manager) GeneratePair(userGUID string) (*Pair, error) {
 	return &Pair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		RefreshClaims: RefreshClaims{
+			UserID:    userGUID,
+			TokenID:   refreshID,
+			FamilyID:  familyID,
+			ExpiresAt: time.Unix(refreshExp.Unix(), 0),
+		},
 	}, nil
 }
 
-// ValidatePair parses a token string
-func (m *Manager) ValidatePair(accessToken string, refreshToken string) (string, error) {
+// ValidatePair validates a pair of tokens and returns the claims of the refresh token.
+// The access token is usually expired when the pair is refreshed, so only its signature and subject are checked.
+func (m *Manager) ValidatePair(accessToken string, refreshToken string) (*RefreshClaims, error) {
 	accessClaims := jwt.MapClaims{}
-	_, err := jwt.ParseWithClaims(accessToken, accessClaims, func(token *jwt.Token) (interface{}, error) {
-		return m.Options.PublicKey, nil
-	})
+	_, err := jwt.ParseWithClaims(accessToken, accessClaims, m.keyFunc,
+		jwt.WithValidMethods(validMethods), jwt.WithoutClaimsValidation())
 	if err != nil {
-		return "", err
+		return nil, ErrInvalidToken
+	}
+
+	if _, ok := accessClaims["type"]; ok {
+		return nil, ErrInvalidToken
 	}
 
+	sub, _ := accessClaims["sub"].(string)
+	if sub == "" {
+		return nil, ErrInvalidToken
+	}
+
+	claims, err := m.ParseRefresh(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if sub != claims.UserID {
+		return nil, ErrInvalidPair
+	}
+
+	return claims, nil
+}
+
+// ParseRefresh parses a refresh token string and returns its claims
+func (m *Manager) ParseRefresh(refreshToken string) (*RefreshClaims, error) {
 	refreshClaims := jwt.MapClaims{}
-	_, err = jwt.ParseWithClaims(refreshToken, refreshClaims, func(token *jwt.Token) (interface{}, error) {
-		return m.Options.PublicKey, nil
-	})
+	_, err := jwt.ParseWithClaims(refreshToken, refreshClaims, m.keyFunc, jwt.WithValidMethods(validMethods))
 	if err != nil {
-		return "", err
+		return nil, ErrInvalidToken
+	}
+
+	if refreshClaims["type"] != "refresh" {
+		return nil, ErrInvalidToken
 	}
 
-	if accessClaims["sub"] != refreshClaims["sub"] {
-		return "", ErrInvalidPair
+	sub, _ := refreshClaims["sub"].(string)
+	jti, _ := refreshClaims["jti"].(string)
+	fam, _ := refreshClaims["fam"].(string)
+	if sub == "" || jti == "" || fam == "" {
+		return nil, ErrInvalidToken
 	}
 
-	return accessClaims["sub"].(string), nil
+	exp, err := refreshClaims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &RefreshClaims{
+		UserID:    sub,
+		TokenID:   jti,
+		FamilyID:  fam,
+		ExpiresAt: exp.Time,
+	}, nil
 }
 
-// Parse parses a token string
+// Parse parses an access token string, refresh tokens and other typed tokens are rejected
 func (m *Manager) Parse(tokenString string) (*jwt.Token, error) {
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return m.Options.PublicKey, nil
-	})
+	token, err := jwt.Parse(tokenString, m.keyFunc, jwt.WithValidMethods(validMethods))
 
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
 
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if _, ok := claims["type"]; ok {
+		return nil, ErrInvalidToken
+	}
+
 	return token, nil
 }
 
+// validMethods are the signing methods tokens are accepted with
+var validMethods = []string{jwt.SigningMethodRS256.Alg()}
+
+// keyFunc returns the verification key matching the kid header of a token
+func (m *Manager) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	return m.Options.Keys.VerificationKey(kid)
+}
+
+// sign signs claims with the key and sets the kid header
+func sign(claims jwt.Claims, key Key) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
 // ReadPublicKey reads a public key from a file
 func ReadPublicKey(publicKeyPath string) (*rsa.PublicKey, error) {
 	f, err := os.ReadFile(publicKeyPath)
@@ -0,0 +1,191 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const (
+	accessTTL  = 15 * time.Minute
+	refreshTTL = 24 * time.Hour
+)
+
+func newTestManager(t *testing.T) *Manager {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	keys, err := NewKeyRing(NewKey(key, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return NewManager(ManagerOptions{
+		Keys:       keys,
+		AccessTTL:  accessTTL,
+		RefreshTTL: refreshTTL,
+	})
+}
+
+func TestManager_GeneratePair(t *testing.T) {
+	manager := newTestManager(t)
+
+	pair, err := manager.GeneratePair("sub")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, "sub", pair.RefreshClaims.UserID)
+	assert.NotEmpty(t, pair.RefreshClaims.TokenID)
+	assert.NotEmpty(t, pair.RefreshClaims.FamilyID)
+}
+
+func TestManager_GenerateFamilyPair(t *testing.T) {
+	manager := newTestManager(t)
+
+	first, err := manager.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, err := manager.GenerateFamilyPair("sub", first.RefreshClaims.FamilyID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, first.RefreshClaims.FamilyID, second.RefreshClaims.FamilyID)
+	assert.NotEqual(t, first.RefreshClaims.TokenID, second.RefreshClaims.TokenID)
+}
+
+func TestManager_ValidatePair(t *testing.T) {
+	manager := newTestManager(t)
+
+	pair, err := manager.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	claims, err := manager.ValidatePair(pair.AccessToken, pair.RefreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, pair.RefreshClaims.TokenID, claims.TokenID)
+	assert.Equal(t, pair.RefreshClaims.FamilyID, claims.FamilyID)
+	assert.Equal(t, pair.RefreshClaims.ExpiresAt, claims.ExpiresAt)
+}
+
+func TestManager_ValidatePair_Invalid(t *testing.T) {
+	manager := newTestManager(t)
+
+	first, err := manager.GeneratePair("first")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, err := manager.GeneratePair("second")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = manager.ValidatePair(first.AccessToken, second.RefreshToken)
+
+	assert.Equal(t, ErrInvalidPair, err)
+}
+
+func TestManager_ValidatePair_ExpiredAccessToken(t *testing.T) {
+	manager := newTestManager(t)
+	manager.Options.AccessTTL = -time.Minute
+
+	pair, err := manager.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = manager.Parse(pair.AccessToken)
+	assert.Equal(t, ErrInvalidToken, err)
+
+	claims, err := manager.ValidatePair(pair.AccessToken, pair.RefreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, pair.RefreshClaims.TokenID, claims.TokenID)
+}
+
+func TestManager_ValidatePair_InvalidAccessToken(t *testing.T) {
+	manager := newTestManager(t)
+	other := newTestManager(t)
+
+	pair, err := manager.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	foreign, err := other.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		accessToken string
+	}{
+		{name: "malformed", accessToken: "not a token"},
+		{name: "foreign key", accessToken: foreign.AccessToken},
+		{name: "refresh token", accessToken: pair.RefreshToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := manager.ValidatePair(tt.accessToken, pair.RefreshToken)
+
+			assert.Equal(t, ErrInvalidToken, err)
+		})
+	}
+}
+
+func TestManager_ParseRefresh_AccessToken(t *testing.T) {
+	manager := newTestManager(t)
+
+	pair, err := manager.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = manager.ParseRefresh(pair.AccessToken)
+
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestManager_Parse_RefreshToken(t *testing.T) {
+	manager := newTestManager(t)
+
+	pair, err := manager.GeneratePair("sub")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = manager.Parse(pair.AccessToken)
+	assert.NoError(t, err)
+
+	_, err = manager.Parse(pair.RefreshToken)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestManager_GeneratePair_Roles(t *testing.T) {
+	manager := newTestManager(t)
+	manager.Options.Roles = map[string][]string{"admin": {RoleAdmin}}
+
+	pair, err := manager.GeneratePair("admin")
+	assert.NoError(t, err)
+	token, err := manager.Parse(pair.AccessToken)
+	assert.NoError(t, err)
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, []interface{}{RoleAdmin}, claims["roles"])
+
+	pair, err = manager.GeneratePair("user")
+	assert.NoError(t, err)
+	token, err = manager.Parse(pair.AccessToken)
+	assert.NoError(t, err)
+	claims = token.Claims.(jwt.MapClaims)
+	assert.NotContains(t, claims, "roles")
+}
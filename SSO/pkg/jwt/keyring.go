@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// Errors
+var (
+	ErrUnknownKey   = errors.New("unknown key")
+	ErrNoSigningKey = errors.New("no signing key")
+	ErrKeyMismatch  = errors.New("public key does not match the private key")
+)
+
+// Key is an RSA key with its ID
+type Key struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+}
+
+// NewKey creates a Key with the RFC 7638 thumbprint of the public key as its ID.
+// The private key may be nil for keys that are only used for verification.
+func NewKey(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Key {
+	if publicKey == nil && privateKey != nil {
+		publicKey = &privateKey.PublicKey
+	}
+
+	return Key{
+		ID:         Thumbprint(publicKey),
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}
+}
+
+// KeyRing holds one signing key and every key tokens are still verified with.
+//
+// The signing key is rotated by configuration: the previous public key is passed as a verification key,
+// so tokens signed before the rotation stay valid until they expire.
+type KeyRing struct {
+	signing      Key
+	verification map[string]Key
+}
+
+// NewKeyRing creates a new KeyRing, the public key of the signing key must match its private key
+func NewKeyRing(signing Key, verification ...Key) (*KeyRing, error) {
+	if signing.PrivateKey == nil {
+		return nil, ErrNoSigningKey
+	}
+	if !signing.PrivateKey.PublicKey.Equal(signing.PublicKey) {
+		return nil, ErrKeyMismatch
+	}
+
+	r := &KeyRing{
+		signing:      signing,
+		verification: map[string]Key{signing.ID: signing},
+	}
+	for _, key := range verification {
+		r.verification[key.ID] = key
+	}
+
+	return r, nil
+}
+
+// SigningKey returns the key new tokens are signed with
+func (r *KeyRing) SigningKey() Key {
+	return r.signing
+}
+
+// VerificationKey returns the public key with the given ID.
+// Tokens without a key ID were issued before key IDs existed and are verified with the signing key.
+func (r *KeyRing) VerificationKey(kid string) (*rsa.PublicKey, error) {
+	if kid == "" {
+		return r.signing.PublicKey, nil
+	}
+
+	key, ok := r.verification[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key.PublicKey, nil
+}
+
+// JWKS returns the verification keys as a JSON Web Key Set
+func (r *KeyRing) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(r.verification))}
+	for _, key := range r.verification {
+		set.Keys = append(set.Keys, ToJWK(key))
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// ToJWK returns the public part of a key as a JSON Web Key
+func ToJWK(key Key) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: key.ID,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+	}
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of a public key
+func Thumbprint(publicKey *rsa.PublicKey) string {
+	jwk := ToJWK(Key{PublicKey: publicKey})
+	// Members in lexicographic order, without whitespace, as required by RFC 7638
+	canonical := `{"e":"` + jwk.E + `","kty":"RSA","n":"` + jwk.N + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
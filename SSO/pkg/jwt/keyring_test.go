@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestKey(t *testing.T) Key {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return NewKey(key, nil)
+}
+
+func TestNewKeyRing_NoSigningKey(t *testing.T) {
+	key := newTestKey(t)
+
+	_, err := NewKeyRing(NewKey(nil, key.PublicKey))
+
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestNewKeyRing_KeyMismatch(t *testing.T) {
+	key := newTestKey(t)
+	other := newTestKey(t)
+
+	_, err := NewKeyRing(Key{ID: key.ID, PrivateKey: key.PrivateKey, PublicKey: other.PublicKey})
+
+	assert.ErrorIs(t, err, ErrKeyMismatch)
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	previous := newTestManager(t)
+	previousKey := previous.Options.Keys.SigningKey()
+
+	pair, err := previous.GeneratePair("sub")
+	assert.NoError(t, err)
+
+	// The next signing key is configured with the previous public key for verification
+	next := newTestKey(t)
+	keys, err := NewKeyRing(next, NewKey(nil, previousKey.PublicKey))
+	assert.NoError(t, err)
+	manager := NewManager(ManagerOptions{Keys: keys, AccessTTL: accessTTL, RefreshTTL: refreshTTL})
+
+	// Tokens signed with the previous key stay valid
+	token, err := manager.Parse(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, previousKey.ID, token.Header["kid"])
+
+	rotated, err := manager.GeneratePair("sub")
+	assert.NoError(t, err)
+
+	token, err = manager.Parse(rotated.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, next.ID, token.Header["kid"])
+
+	// Once the previous key is not configured anymore, its tokens are rejected
+	keys, err = NewKeyRing(next)
+	assert.NoError(t, err)
+	manager = NewManager(ManagerOptions{Keys: keys, AccessTTL: accessTTL, RefreshTTL: refreshTTL})
+
+	_, err = manager.Parse(pair.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestKeyRing_JWKS(t *testing.T) {
+	signing := newTestKey(t)
+	verification := newTestKey(t)
+
+	keys, err := NewKeyRing(signing, NewKey(nil, verification.PublicKey))
+	assert.NoError(t, err)
+
+	set := keys.JWKS()
+
+	assert.Len(t, set.Keys, 2)
+	for _, jwk := range set.Keys {
+		assert.Contains(t, []string{signing.ID, verification.ID}, jwk.Kid)
+		assert.Equal(t, "RSA", jwk.Kty)
+		assert.Equal(t, "RS256", jwk.Alg)
+		assert.Equal(t, "AQAB", jwk.E)
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	key := newTestKey(t)
+
+	assert.Equal(t, Thumbprint(key.PublicKey), Thumbprint(&key.PrivateKey.PublicKey))
+	assert.Len(t, key.ID, 43)
+}
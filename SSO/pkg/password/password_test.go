@@ -0,0 +1,113 @@
+package password
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+var testParams = Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHasher_Hash(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	encoded, err := hasher.Hash("password")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.False(t, hasher.NeedsRehash(encoded))
+}
+
+func TestHasher_Hash_Salted(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	first, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestHasher_Verify(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	encoded, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ok, err := hasher.Verify("password", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong", encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_Verify_LegacySHA256(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	sum := sha256.Sum256([]byte("password"))
+	encoded := hex.EncodeToString(sum[:])
+
+	ok, err := hasher.Verify("password", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong", encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, hasher.NeedsRehash(encoded))
+}
+
+func TestHasher_Verify_Bcrypt(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	encoded, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ok, err := hasher.Verify("password", string(encoded))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.True(t, hasher.NeedsRehash(string(encoded)))
+}
+
+func TestHasher_Verify_UnknownFormat(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	_, err := hasher.Verify("password", "plain")
+
+	assert.Equal(t, ErrUnknownFormat, err)
+}
+
+func TestHasher_NeedsRehash_ChangedParams(t *testing.T) {
+	encoded, err := NewHasher(testParams).Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	stronger := testParams
+	stronger.Iterations = 2
+
+	assert.True(t, NewHasher(stronger).NeedsRehash(encoded))
+}